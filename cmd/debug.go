@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// debugMode is the --debug flag.
+var debugMode bool
+
+// debugEnv enables debug mode like --debug when set to a true value.
+const debugEnv = "GH_PROBLEMAS_DEBUG"
+
+// debugEnabled reports whether --debug was given or GH_PROBLEMAS_DEBUG is
+// set to a true value such as 1 or true.
+func debugEnabled() bool {
+	if debugMode {
+		return true
+	}
+	on, err := strconv.ParseBool(os.Getenv(debugEnv))
+	return err == nil && on
+}
+
+// debugLog is the structured log written in debug mode.
+type debugLog struct {
+	logger *slog.Logger
+	path   string
+	file   *os.File
+}
+
+// openDebugLog opens debug.log in dir for appending, creating both if
+// needed. Entries are JSON, one per line, at every level.
+func openDebugLog(dir string) (*debugLog, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating state directory: %w", err)
+	}
+	path := filepath.Join(dir, "debug.log")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening debug log: %w", err)
+	}
+	handler := slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return &debugLog{logger: slog.New(handler), path: path, file: f}, nil
+}
+
+// Close closes the log file.
+func (d *debugLog) Close() error {
+	return d.file.Close()
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// editorCommand returns the editor used for writing comments, chosen as gh
+// does: GH_EDITOR, gh's editor setting, VISUAL, EDITOR, and then a
+// platform default.
+func editorCommand() string {
+	if editor := os.Getenv("GH_EDITOR"); editor != "" {
+		return editor
+	}
+	if cfg, err := config.Read(nil); err == nil {
+		if editor, err := cfg.Get([]string{"editor"}); err == nil && editor != "" {
+			return editor
+		}
+	}
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "nano"
+}
+
+// browserCommand returns the browser used for opening URLs, chosen as gh
+// does: GH_BROWSER, gh's browser setting, and BROWSER. It is empty when
+// none is set, for the system's opener.
+func browserCommand() string {
+	if browser := os.Getenv("GH_BROWSER"); browser != "" {
+		return browser
+	}
+	if cfg, err := config.Read(nil); err == nil {
+		if browser, err := cfg.Get([]string{"browser"}); err == nil && browser != "" {
+			return browser
+		}
+	}
+	return os.Getenv("BROWSER")
+}
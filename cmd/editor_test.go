@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte("editor: micro\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GH_CONFIG_DIR", dir)
+	t.Setenv("VISUAL", "vim")
+	t.Setenv("EDITOR", "vi")
+
+	t.Setenv("GH_EDITOR", "code --wait")
+	if got := editorCommand(); got != "code --wait" {
+		t.Errorf("with GH_EDITOR: editorCommand() = %q, want %q", got, "code --wait")
+	}
+
+	t.Setenv("GH_EDITOR", "")
+	if got := editorCommand(); got != "micro" {
+		t.Errorf("with gh config: editorCommand() = %q, want %q", got, "micro")
+	}
+}
+
+func TestBrowserCommand(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_BROWSER", "")
+	t.Setenv("BROWSER", "")
+	if got := browserCommand(); got != "" {
+		t.Errorf("with nothing set: browserCommand() = %q, want the system opener", got)
+	}
+
+	t.Setenv("BROWSER", "w3m")
+	if got := browserCommand(); got != "w3m" {
+		t.Errorf("with BROWSER: browserCommand() = %q, want %q", got, "w3m")
+	}
+
+	t.Setenv("GH_BROWSER", "firefox --new-tab")
+	if got := browserCommand(); got != "firefox --new-tab" {
+		t.Errorf("with GH_BROWSER: browserCommand() = %q, want %q", got, "firefox --new-tab")
+	}
+}
@@ -0,0 +1,469 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/spf13/cobra"
+)
+
+// exportOptions holds the flags for the export command.
+type exportOptions struct {
+	filterOptions
+	dir          string
+	limit        int
+	restart      bool
+	minRemaining int
+}
+
+var exportOpts exportOptions
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Archive issues with comments and timelines",
+	Long: `Archive every issue matching a filter, with its full comment thread and
+timeline, to a directory.
+
+Each issue is written to issues/<number>.md. When the export finishes, an
+index.md and a machine-readable issues.json bundle are written alongside.
+An interrupted export resumes from the last completed page when run again
+with the same filters; use --restart to start over.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	f := exportCmd.Flags()
+	exportOpts.addFlags(f)
+	f.StringVarP(&exportOpts.dir, "dir", "d", "issues-export", "Directory to write the archive to")
+	f.IntVarP(&exportOpts.limit, "limit", "L", 0, "Maximum number of issues to export (0 for all)")
+	f.BoolVar(&exportOpts.restart, "restart", false, "Discard saved progress and start over")
+	f.IntVar(&exportOpts.minRemaining, "min-remaining", 100, "Pause until the rate limit resets when fewer points remain")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if err := exportOpts.filterOptions.validate(); err != nil {
+		return err
+	}
+	if exportOpts.limit < 0 {
+		return fmt.Errorf("invalid --limit %d: must not be negative", exportOpts.limit)
+	}
+
+	s, err := newSession()
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	e := &exporter{
+		issues:    s.issueClient(),
+		comments:  data.NewCommentClient(s.querier, s.owner, s.name),
+		timeline:  data.NewTimelineClient(s.querier, s.owner, s.name),
+		rateLimit: data.NewRateLimitClient(s.querier),
+		repo:      s.repoName(),
+		opts:      exportOpts,
+		pageSize:  s.cfg.Defaults.PageSize,
+		out:       cmd.ErrOrStderr(),
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+	return e.run()
+}
+
+// issueFetcher is the subset of IssueClient used by the exporter.
+type issueFetcher interface {
+	issueLister
+	Get(number int) (data.Issue, error)
+}
+
+// timelineLister is the subset of TimelineClient used by the exporter.
+type timelineLister interface {
+	List(issueNumber, first int, after string) (data.TimelineListResult, error)
+}
+
+// rateLimiter is the subset of RateLimitClient used by the exporter.
+type rateLimiter interface {
+	Get() (data.RateLimit, error)
+}
+
+// exportState records progress so an interrupted export can resume.
+type exportState struct {
+	Filter   string `json:"filter"`
+	Cursor   string `json:"cursor"`
+	Exported []int  `json:"exported"`
+	Complete bool   `json:"complete"`
+}
+
+// exporter writes an issue archive, one page of issues at a time.
+type exporter struct {
+	issues    issueFetcher
+	comments  commentLister
+	timeline  timelineLister
+	rateLimit rateLimiter
+	repo      string
+	opts      exportOptions
+	pageSize  int
+	out       io.Writer
+	now       func() time.Time
+	sleep     func(time.Duration)
+}
+
+func (e *exporter) stagingDir() string {
+	return filepath.Join(e.opts.dir, ".export")
+}
+
+func (e *exporter) statePath() string {
+	return filepath.Join(e.stagingDir(), "state.json")
+}
+
+// searchResultCap is the most results GitHub search returns for a query,
+// however many issues match it.
+const searchResultCap = 1000
+
+// filterSignature identifies the repository and filters of an export, so a
+// resumed export cannot silently mix results from different filters.
+func (e *exporter) filterSignature() string {
+	o := e.opts.filterOptions
+	return fmt.Sprintf("%s state=%s labels=%s sort=%s-%s search=%s",
+		e.repo, o.state, strings.Join(o.labels, ","), o.sort, o.order, o.search)
+}
+
+func (e *exporter) run() error {
+	for _, dir := range []string{filepath.Join(e.opts.dir, "issues"), e.stagingDir()} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating export directory: %w", err)
+		}
+	}
+
+	state, err := e.loadState()
+	if err != nil {
+		return err
+	}
+	if state.Complete {
+		_, _ = fmt.Fprintf(e.out, "Export in %s is already complete; use --restart to export again\n", e.opts.dir)
+		return nil
+	}
+	if len(state.Exported) > 0 {
+		_, _ = fmt.Fprintf(e.out, "Resuming export after %d issues\n", len(state.Exported))
+	}
+
+	exported := make(map[int]bool, len(state.Exported))
+	for _, n := range state.Exported {
+		exported[n] = true
+	}
+
+	paginator := data.NewPaginator(e.pageSize)
+	paginator.Update(data.PageInfo{HasNextPage: true, EndCursor: state.Cursor}, 0)
+
+	matched := 0
+	for !e.limitReached(state) {
+		req := paginator.NextPageRequest()
+		if req == nil {
+			break
+		}
+
+		if err := e.waitForRateLimit(); err != nil {
+			return err
+		}
+
+		result, err := e.opts.fetchPage(e.issues, req.First, req.After)
+		if err != nil {
+			return fmt.Errorf("listing issues: %w", err)
+		}
+		matched = result.TotalCount
+
+		for _, issue := range result.Issues {
+			if e.limitReached(state) {
+				break
+			}
+			if exported[issue.Number] {
+				continue
+			}
+			if err := e.exportIssue(issue.Number); err != nil {
+				return err
+			}
+			exported[issue.Number] = true
+			state.Exported = append(state.Exported, issue.Number)
+			if err := e.saveState(state); err != nil {
+				return err
+			}
+		}
+
+		paginator.Update(result.PageInfo, len(result.Issues))
+		state.Cursor = result.PageInfo.EndCursor
+		if err := e.saveState(state); err != nil {
+			return err
+		}
+	}
+
+	if err := e.writeBundle(state.Exported); err != nil {
+		return err
+	}
+
+	state.Complete = true
+	if err := e.saveState(state); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(e.out, "Exported %d issues to %s\n", len(state.Exported), e.opts.dir)
+	if matched > searchResultCap && !e.limitReached(state) {
+		_, _ = fmt.Fprintf(e.out, "Warning: %d issues match the search, but GitHub search returns only the first %d; "+
+			"narrow it, for example with created:<YYYY-MM-DD, to export the rest\n", matched, searchResultCap)
+	}
+	return nil
+}
+
+func (e *exporter) limitReached(state *exportState) bool {
+	return e.opts.limit > 0 && len(state.Exported) >= e.opts.limit
+}
+
+func (e *exporter) loadState() (*exportState, error) {
+	signature := e.filterSignature()
+	fresh := &exportState{Filter: signature}
+
+	if e.opts.restart {
+		return fresh, nil
+	}
+
+	b, err := os.ReadFile(e.statePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading export state: %w", err)
+	}
+
+	var state exportState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, fmt.Errorf("parsing export state %s: %w", e.statePath(), err)
+	}
+	if state.Filter != signature {
+		return nil, fmt.Errorf("%s contains an export with different filters (%s); use --restart or another --dir", e.opts.dir, state.Filter)
+	}
+	return &state, nil
+}
+
+func (e *exporter) saveState(state *exportState) error {
+	return writeJSONFile(e.statePath(), state)
+}
+
+// waitForRateLimit pauses until the rate limit resets when the remaining
+// budget is below the configured floor.
+func (e *exporter) waitForRateLimit() error {
+	if e.opts.minRemaining <= 0 {
+		return nil
+	}
+
+	rl, err := e.rateLimit.Get()
+	if err != nil {
+		return fmt.Errorf("checking rate limit: %w", err)
+	}
+	if rl.Remaining >= e.opts.minRemaining {
+		return nil
+	}
+
+	wait := rl.ResetAt.Sub(e.now()) + time.Second
+	if wait <= 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(e.out, "Rate limit low (%d points remaining); waiting until %s\n", rl.Remaining, rl.ResetAt.Local().Format(time.Kitchen))
+	e.sleep(wait)
+	return nil
+}
+
+func (e *exporter) exportIssue(number int) error {
+	issue, err := e.issues.Get(number)
+	if err != nil {
+		return fmt.Errorf("loading issue #%d: %w", number, err)
+	}
+
+	comments, err := fetchAllComments(e.comments, number)
+	if err != nil {
+		return fmt.Errorf("loading comments for #%d: %w", number, err)
+	}
+
+	events, err := fetchAllTimeline(e.timeline, number)
+	if err != nil {
+		return fmt.Errorf("loading timeline for #%d: %w", number, err)
+	}
+
+	mdPath := filepath.Join(e.opts.dir, "issues", fmt.Sprintf("%d.md", number))
+	if err := os.WriteFile(mdPath, []byte(issueMarkdown(e.repo, issue, comments, events)), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", mdPath, err)
+	}
+
+	record := issueRecord(issue)
+	commentRecords := make([]map[string]interface{}, len(comments))
+	for i, c := range comments {
+		commentRecords[i] = commentRecord(c)
+	}
+	record["comments"] = commentRecords
+	eventRecords := make([]map[string]interface{}, len(events))
+	for i, ev := range events {
+		eventRecords[i] = timelineRecord(ev)
+	}
+	record["timeline"] = eventRecords
+
+	_, _ = fmt.Fprintf(e.out, "Exported #%d %s\n", issue.Number, issue.Title)
+	return writeJSONFile(filepath.Join(e.stagingDir(), fmt.Sprintf("%d.json", number)), record)
+}
+
+// writeBundle assembles index.md and issues.json from the staged records.
+func (e *exporter) writeBundle(numbers []int) error {
+	records := make([]map[string]interface{}, 0, len(numbers))
+	for _, n := range numbers {
+		b, err := os.ReadFile(filepath.Join(e.stagingDir(), fmt.Sprintf("%d.json", n)))
+		if err != nil {
+			return fmt.Errorf("reading staged issue #%d: %w", n, err)
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal(b, &record); err != nil {
+			return fmt.Errorf("parsing staged issue #%d: %w", n, err)
+		}
+		records = append(records, record)
+	}
+
+	bundle := map[string]interface{}{
+		"repository": e.repo,
+		"exportedAt": e.now().UTC(),
+		"filter":     e.filterSignature(),
+		"issues":     records,
+	}
+	if err := writeJSONFile(filepath.Join(e.opts.dir, "issues.json"), bundle); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(e.opts.dir, "index.md"), []byte(indexMarkdown(e.repo, records)), 0o644)
+}
+
+// fetchAllTimeline pages through every timeline event on an issue.
+func fetchAllTimeline(client timelineLister, number int) ([]data.TimelineEvent, error) {
+	paginator := data.NewPaginator(100)
+	var events []data.TimelineEvent
+
+	for req := paginator.NextPageRequest(); req != nil; req = paginator.NextPageRequest() {
+		result, err := client.List(number, req.First, req.After)
+		if err != nil {
+			return nil, err
+		}
+		paginator.Update(result.PageInfo, len(result.Events))
+		events = append(events, result.Events...)
+	}
+
+	return events, nil
+}
+
+// timelineRecord converts a timeline event to a map keyed by its output
+// field names.
+func timelineRecord(e data.TimelineEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        e.Type,
+		"actor":       e.Actor,
+		"createdAt":   e.CreatedAt,
+		"description": e.Description(),
+	}
+}
+
+func writeJSONFile(path string, value interface{}) error {
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// issueMarkdown renders an archived issue as a standalone Markdown document.
+func issueMarkdown(repo string, issue data.Issue, comments []data.Comment, events []data.TimelineEvent) string {
+	var sb strings.Builder
+	stamp := func(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+	fmt.Fprintf(&sb, "# %s (%s#%d)\n\n", issue.Title, repo, issue.Number)
+	fmt.Fprintf(&sb, "- State: %s\n", issue.State)
+	fmt.Fprintf(&sb, "- Author: @%s\n", issue.Author)
+	fmt.Fprintf(&sb, "- Created: %s\n", stamp(issue.CreatedAt))
+	fmt.Fprintf(&sb, "- Updated: %s\n", stamp(issue.UpdatedAt))
+	if len(issue.Labels) > 0 {
+		names := make([]string, len(issue.Labels))
+		for i, l := range issue.Labels {
+			names[i] = l.Name
+		}
+		fmt.Fprintf(&sb, "- Labels: %s\n", strings.Join(names, ", "))
+	}
+	if len(issue.Assignees) > 0 {
+		fmt.Fprintf(&sb, "- Assignees: %s\n", strings.Join(issue.Assignees, ", "))
+	}
+	if issue.Milestone != "" {
+		fmt.Fprintf(&sb, "- Milestone: %s\n", issue.Milestone)
+	}
+
+	sb.WriteString("\n")
+	if issue.Body != "" {
+		sb.WriteString(strings.TrimRight(issue.Body, "\n"))
+	} else {
+		sb.WriteString("_No description provided._")
+	}
+	sb.WriteString("\n")
+
+	if len(events) > 0 {
+		sb.WriteString("\n## Timeline\n\n")
+		for _, ev := range events {
+			fmt.Fprintf(&sb, "- %s @%s %s\n", stamp(ev.CreatedAt), ev.Actor, ev.Description())
+		}
+	}
+
+	if len(comments) > 0 {
+		fmt.Fprintf(&sb, "\n## Comments (%d)\n", len(comments))
+		for _, c := range comments {
+			fmt.Fprintf(&sb, "\n### @%s on %s\n\n", c.Author, stamp(c.CreatedAt))
+			sb.WriteString(strings.TrimRight(c.Body, "\n"))
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// indexMarkdown renders a table of contents linking each archived issue.
+func indexMarkdown(repo string, records []map[string]interface{}) string {
+	sorted := make([]map[string]interface{}, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return numberOf(sorted[i]) < numberOf(sorted[j])
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s issue archive\n\n", repo)
+	fmt.Fprintf(&sb, "%d issues.\n\n", len(sorted))
+	sb.WriteString("| # | Title | State | Author | Updated |\n")
+	sb.WriteString("|---|-------|-------|--------|---------|\n")
+	for _, r := range sorted {
+		n := numberOf(r)
+		title := strings.ReplaceAll(fmt.Sprint(r["title"]), "|", `\|`)
+		fmt.Fprintf(&sb, "| [%d](issues/%d.md) | %s | %v | %v | %v |\n", n, n, title, r["state"], r["author"], r["updatedAt"])
+	}
+	return sb.String()
+}
+
+// numberOf reads the issue number from a record decoded from JSON.
+func numberOf(record map[string]interface{}) int {
+	switch n := record["number"].(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+)
+
+// exportIssues extends pagedLister with Get and an optional failure.
+type exportIssues struct {
+	pagedLister
+	failOn int
+}
+
+func (e *exportIssues) Get(number int) (data.Issue, error) {
+	if number == e.failOn {
+		return data.Issue{}, errors.New("connection reset")
+	}
+	issue := e.issues[number-1]
+	issue.Body = "Body of " + issue.Title
+	return issue, nil
+}
+
+type staticTimeline struct{}
+
+func (staticTimeline) List(_, _ int, _ string) (data.TimelineListResult, error) {
+	return data.TimelineListResult{Events: []data.TimelineEvent{
+		{Type: "LabeledEvent", Actor: "bob", Label: &data.Label{Name: "bug"}},
+	}}, nil
+}
+
+type staticRateLimit struct {
+	rl    data.RateLimit
+	calls int
+}
+
+func (s *staticRateLimit) Get() (data.RateLimit, error) {
+	s.calls++
+	return s.rl, nil
+}
+
+func newTestExporter(t *testing.T, dir string, issues *exportIssues, rl *staticRateLimit) *exporter {
+	t.Helper()
+	return &exporter{
+		issues:    issues,
+		comments:  &pagedCommentLister{comments: []data.Comment{{Author: "carol", Body: "+1"}}},
+		timeline:  staticTimeline{},
+		rateLimit: rl,
+		repo:      "owner/repo",
+		opts: exportOptions{
+			filterOptions: filterOptions{state: "open", sort: "created", order: "desc"},
+			dir:           dir,
+			minRemaining:  100,
+		},
+		pageSize: 2,
+		out:      &strings.Builder{},
+		now:      func() time.Time { return time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC) },
+		sleep:    func(time.Duration) {},
+	}
+}
+
+func TestExport_WritesArchive(t *testing.T) {
+	dir := t.TempDir()
+	issues := &exportIssues{pagedLister: pagedLister{issues: sampleIssues(3)}}
+	e := newTestExporter(t, dir, issues, &staticRateLimit{rl: data.RateLimit{Remaining: 5000}})
+
+	if err := e.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	md, err := os.ReadFile(filepath.Join(dir, "issues", "2.md"))
+	if err != nil {
+		t.Fatalf("expected issue markdown: %v", err)
+	}
+	for _, want := range []string{"# Issue B (owner/repo#2)", "Body of Issue B", "## Timeline", "@bob added label bug", "## Comments (1)", "+1"} {
+		if !strings.Contains(string(md), want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatalf("expected index: %v", err)
+	}
+	if !strings.Contains(string(index), "[3](issues/3.md)") {
+		t.Errorf("expected index to link issue 3, got:\n%s", index)
+	}
+
+	var bundle struct {
+		Repository string `json:"repository"`
+		Issues     []struct {
+			Number   int           `json:"number"`
+			Comments []interface{} `json:"comments"`
+			Timeline []interface{} `json:"timeline"`
+		} `json:"issues"`
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "issues.json"))
+	if err != nil {
+		t.Fatalf("expected bundle: %v", err)
+	}
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		t.Fatalf("invalid bundle: %v", err)
+	}
+	if bundle.Repository != "owner/repo" || len(bundle.Issues) != 3 {
+		t.Fatalf("unexpected bundle: %+v", bundle)
+	}
+	if len(bundle.Issues[0].Comments) != 1 || len(bundle.Issues[0].Timeline) != 1 {
+		t.Errorf("expected comments and timeline in bundle, got %+v", bundle.Issues[0])
+	}
+}
+
+func TestExport_ResumesAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	issues := &exportIssues{pagedLister: pagedLister{issues: sampleIssues(5)}, failOn: 4}
+	rl := &staticRateLimit{rl: data.RateLimit{Remaining: 5000}}
+
+	if err := newTestExporter(t, dir, issues, rl).run(); err == nil {
+		t.Fatal("expected first run to fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "issues.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("expected no bundle after an interrupted export")
+	}
+
+	issues.failOn = 0
+	issues.requests = nil
+	if err := newTestExporter(t, dir, issues, rl).run(); err != nil {
+		t.Fatalf("unexpected error on resume: %v", err)
+	}
+
+	if len(issues.requests) == 0 || issues.requests[0].After != "cursor-Issue B" {
+		t.Fatalf("expected resume from the last completed page, got %+v", issues.requests)
+	}
+	for n := 1; n <= 5; n++ {
+		if _, err := os.Stat(filepath.Join(dir, "issues", fmt.Sprintf("%d.md", n))); err != nil {
+			t.Errorf("expected issue %d to be exported: %v", n, err)
+		}
+	}
+}
+
+func TestExport_RejectsDifferentFilterOnResume(t *testing.T) {
+	dir := t.TempDir()
+	issues := &exportIssues{pagedLister: pagedLister{issues: sampleIssues(3)}, failOn: 2}
+	rl := &staticRateLimit{rl: data.RateLimit{Remaining: 5000}}
+	_ = newTestExporter(t, dir, issues, rl).run()
+
+	e := newTestExporter(t, dir, issues, rl)
+	e.opts.state = "closed"
+	if err := e.run(); err == nil || !strings.Contains(err.Error(), "--restart") {
+		t.Fatalf("expected filter mismatch error, got %v", err)
+	}
+}
+
+func TestExport_WaitsForRateLimitReset(t *testing.T) {
+	dir := t.TempDir()
+	issues := &exportIssues{pagedLister: pagedLister{issues: sampleIssues(1)}}
+	rl := &staticRateLimit{rl: data.RateLimit{
+		Remaining: 10,
+		ResetAt:   time.Date(2025, time.January, 1, 0, 10, 0, 0, time.UTC),
+	}}
+	e := newTestExporter(t, dir, issues, rl)
+	var slept time.Duration
+	e.sleep = func(d time.Duration) { slept += d }
+
+	if err := e.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slept != 10*time.Minute+time.Second {
+		t.Errorf("expected to wait until reset, slept %v", slept)
+	}
+}
+
+func TestExport_WarnsAtSearchResultCap(t *testing.T) {
+	dir := t.TempDir()
+	issues := &exportIssues{pagedLister: pagedLister{issues: sampleIssues(3), total: 1500}}
+	e := newTestExporter(t, dir, issues, &staticRateLimit{rl: data.RateLimit{Remaining: 5000}})
+	e.opts.search = "crash"
+
+	if err := e.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := e.out.(*strings.Builder).String()
+	if !strings.Contains(out, "1500 issues match the search, but GitHub search returns only the first 1000") {
+		t.Errorf("expected a warning about the search cap, got:\n%s", out)
+	}
+
+	issues.total = 0
+	e = newTestExporter(t, t.TempDir(), issues, &staticRateLimit{rl: data.RateLimit{Remaining: 5000}})
+	e.opts.search = "crash"
+	if err := e.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := e.out.(*strings.Builder).String(); strings.Contains(out, "Warning") {
+		t.Errorf("expected no warning under the cap, got:\n%s", out)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/spf13/pflag"
+)
+
+// filterOptions holds the issue filter flags shared by the non-interactive
+// subcommands.
+type filterOptions struct {
+	state  string
+	labels []string
+	sort   string
+	order  string
+	search string
+}
+
+func (o *filterOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVarP(&o.state, "state", "s", "open", "Filter by state: {open|closed|all}")
+	f.StringSliceVarP(&o.labels, "label", "l", nil, "Filter by label (repeatable)")
+	f.StringVar(&o.sort, "sort", "created", "Sort by: {created|updated|comments}")
+	f.StringVar(&o.order, "order", "desc", "Sort direction: {asc|desc}")
+	f.StringVarP(&o.search, "search", "S", "", "Filter with a GitHub search query")
+}
+
+func (o filterOptions) validate() error {
+	switch o.state {
+	case "open", "closed", "all":
+	default:
+		return fmt.Errorf("invalid --state %q: expected open, closed, or all", o.state)
+	}
+	if _, ok := sortFields[o.sort]; !ok {
+		return fmt.Errorf("invalid --sort %q: expected created, updated, or comments", o.sort)
+	}
+	if o.order != "asc" && o.order != "desc" {
+		return fmt.Errorf("invalid --order %q: expected asc or desc", o.order)
+	}
+	return nil
+}
+
+// sortFields maps --sort values to IssueOrder fields.
+var sortFields = map[string]string{
+	"created":  "CREATED_AT",
+	"updated":  "UPDATED_AT",
+	"comments": "COMMENTS",
+}
+
+// searchQuery builds a GitHub search query equivalent to the filters.
+func (o filterOptions) searchQuery() string {
+	parts := []string{o.search}
+	if o.state != "all" {
+		parts = append(parts, "is:"+o.state)
+	}
+	for _, l := range o.labels {
+		parts = append(parts, fmt.Sprintf("label:%q", l))
+	}
+	parts = append(parts, fmt.Sprintf("sort:%s-%s", o.sort, o.order))
+	return strings.Join(parts, " ")
+}
+
+// issueListOptions converts the filters to repository issue list options.
+func (o filterOptions) issueListOptions() data.IssueListOptions {
+	opts := data.IssueListOptions{
+		Labels: o.labels,
+		OrderBy: data.IssueOrder{
+			Field:     sortFields[o.sort],
+			Direction: strings.ToUpper(o.order),
+		},
+	}
+	if o.state != "all" {
+		opts.States = []string{strings.ToUpper(o.state)}
+	}
+	return opts
+}
+
+// issueLister is the subset of IssueClient used to page through issues.
+type issueLister interface {
+	List(opts data.IssueListOptions) (data.IssueListResult, error)
+	Search(query string, first int, after string) (data.IssueListResult, error)
+}
+
+// fetchPage fetches one page of matching issues, using the search API when a
+// search query is given.
+func (o filterOptions) fetchPage(client issueLister, first int, after string) (data.IssueListResult, error) {
+	if o.search != "" {
+		return client.Search(o.searchQuery(), first, after)
+	}
+	opts := o.issueListOptions()
+	opts.First = first
+	opts.After = after
+	return client.List(opts)
+}
@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/spf13/cobra"
+)
+
+// importOptions holds the flags for the import command.
+type importOptions struct {
+	format        string
+	dryRun        bool
+	createMissing bool
+	mapping       string
+}
+
+var importOpts importOptions
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create issues from a JSON or CSV file",
+	Long: `Create issues from a JSON or CSV file of records with the fields id, title,
+body, labels, assignees, and milestone. The output of "list --format json" or
+"list --format csv" is accepted as input, using "number" as the id.
+
+Labels, milestones, and assignees are validated against the repository before
+anything is created; pass --create-missing to create missing labels and
+milestones. Imported ids are recorded in a mapping file so that running the
+same import again skips issues that were already created.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	f := importCmd.Flags()
+	f.StringVar(&importOpts.format, "format", "auto", "Input format: {auto|json|csv}")
+	f.BoolVarP(&importOpts.dryRun, "dry-run", "n", false, "Show what would be created without creating anything")
+	f.BoolVar(&importOpts.createMissing, "create-missing", false, "Create labels and milestones that do not exist")
+	f.StringVar(&importOpts.mapping, "mapping", "", "Mapping file of source ids to issue numbers (default: <file>.mapping.json)")
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	format, err := importFormat(path, importOpts.format)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	records, err := readImportRecords(f, format)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	mappingPath := importOpts.mapping
+	if mappingPath == "" {
+		mappingPath = path + ".mapping.json"
+	}
+
+	s, err := newSession()
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	im := &importer{
+		issues:     s.issueClient(),
+		labels:     data.NewLabelClient(s.querier, s.owner, s.name),
+		milestones: data.NewMilestoneClient(s.querier, s.rest, s.owner, s.name),
+		users:      data.NewUserClient(s.querier),
+		opts:       importOpts,
+		mapping:    mappingPath,
+		out:        cmd.OutOrStdout(),
+		errOut:     cmd.ErrOrStderr(),
+	}
+	return im.run(records)
+}
+
+// importFormat resolves the input format, inferring it from the file
+// extension when set to auto.
+func importFormat(path, format string) (string, error) {
+	switch format {
+	case "json", "csv":
+		return format, nil
+	case "auto":
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			return "json", nil
+		case ".csv":
+			return "csv", nil
+		}
+		return "", fmt.Errorf("cannot infer format of %s; use --format json or --format csv", path)
+	default:
+		return "", fmt.Errorf("invalid --format %q: expected auto, json, or csv", format)
+	}
+}
+
+// importLabel is a label referenced by an import record. Color is only used
+// when the label has to be created.
+type importLabel struct {
+	Name  string
+	Color string
+}
+
+// importRecord is a single issue to import.
+type importRecord struct {
+	ID        string
+	Title     string
+	Body      string
+	Labels    []importLabel
+	Assignees []string
+	Milestone string
+}
+
+// jsonImportRecord accepts both hand-written records and list JSON output.
+type jsonImportRecord struct {
+	ID        json.RawMessage   `json:"id"`
+	Number    json.RawMessage   `json:"number"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Labels    []json.RawMessage `json:"labels"`
+	Assignees []string          `json:"assignees"`
+	Milestone string            `json:"milestone"`
+}
+
+func readImportRecords(r io.Reader, format string) ([]importRecord, error) {
+	var records []importRecord
+	var err error
+	if format == "csv" {
+		records, err = readCSVRecords(r)
+	} else {
+		records, err = readJSONRecords(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(records))
+	for i, rec := range records {
+		if rec.ID == "" {
+			return nil, fmt.Errorf("record %d has no id", i+1)
+		}
+		if seen[rec.ID] {
+			return nil, fmt.Errorf("record %d: duplicate id %q", i+1, rec.ID)
+		}
+		seen[rec.ID] = true
+	}
+	return records, nil
+}
+
+func readJSONRecords(r io.Reader) ([]importRecord, error) {
+	var raw []jsonImportRecord
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	records := make([]importRecord, len(raw))
+	for i, jr := range raw {
+		id := jsonScalar(jr.ID)
+		if id == "" {
+			id = jsonScalar(jr.Number)
+		}
+
+		labels := make([]importLabel, 0, len(jr.Labels))
+		for _, l := range jr.Labels {
+			var name string
+			if err := json.Unmarshal(l, &name); err == nil {
+				labels = append(labels, importLabel{Name: name})
+				continue
+			}
+			var obj labelRecord
+			if err := json.Unmarshal(l, &obj); err != nil {
+				return nil, fmt.Errorf("record %d: labels must be strings or {name, color} objects", i+1)
+			}
+			labels = append(labels, importLabel(obj))
+		}
+
+		records[i] = importRecord{
+			ID:        id,
+			Title:     jr.Title,
+			Body:      jr.Body,
+			Labels:    labels,
+			Assignees: jr.Assignees,
+			Milestone: jr.Milestone,
+		}
+	}
+	return records, nil
+}
+
+// jsonScalar returns a JSON string or number as a string.
+func jsonScalar(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func readCSVRecords(r io.Reader) ([]importRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("missing title column")
+	}
+	idColumn := "id"
+	if _, ok := columns["id"]; !ok {
+		idColumn = "number"
+	}
+
+	cell := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	records := make([]importRecord, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		var labels []importLabel
+		for _, name := range splitList(cell(row, "labels")) {
+			labels = append(labels, importLabel{Name: name})
+		}
+		records = append(records, importRecord{
+			ID:        cell(row, idColumn),
+			Title:     cell(row, "title"),
+			Body:      cell(row, "body"),
+			Labels:    labels,
+			Assignees: splitList(cell(row, "assignees")),
+			Milestone: cell(row, "milestone"),
+		})
+	}
+	return records, nil
+}
+
+// splitList splits a comma- or semicolon-separated cell.
+func splitList(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' })
+	var out []string
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Interfaces over the data clients used by the importer.
+type (
+	issueCreator interface {
+		RepositoryID() (string, error)
+		Create(input data.IssueCreateInput) (data.Issue, error)
+	}
+	labelStore interface {
+		List() ([]data.Label, error)
+		Create(repoID, name, color string) (data.Label, error)
+	}
+	milestoneStore interface {
+		List() ([]data.Milestone, error)
+		Create(title string) (data.Milestone, error)
+	}
+	userResolver interface {
+		ID(login string) (string, error)
+	}
+)
+
+// importer validates records against the repository and creates issues.
+type importer struct {
+	issues     issueCreator
+	labels     labelStore
+	milestones milestoneStore
+	users      userResolver
+	opts       importOptions
+	mapping    string
+	out        io.Writer
+	errOut     io.Writer
+}
+
+// importPlan is the validated set of changes an import will make.
+type importPlan struct {
+	newLabels     []importLabel
+	newMilestones []string
+	creates       []importRecord
+	skipped       map[string]int
+
+	labelIDs     map[string]string // lower-cased name to ID
+	milestoneIDs map[string]string // title to ID
+	userIDs      map[string]string // login to ID
+}
+
+func (im *importer) run(records []importRecord) error {
+	mapping, err := loadImportMapping(im.mapping)
+	if err != nil {
+		return err
+	}
+
+	plan, err := im.plan(records, mapping)
+	if err != nil {
+		return err
+	}
+
+	if im.opts.dryRun {
+		return printImportPlan(im.out, plan)
+	}
+
+	// Labels and issues are created in the same repository, so its ID is
+	// looked up once rather than for each.
+	var repoID string
+	if len(plan.newLabels) > 0 || len(plan.creates) > 0 {
+		if repoID, err = im.issues.RepositoryID(); err != nil {
+			return fmt.Errorf("looking up repository: %w", err)
+		}
+	}
+
+	for _, l := range plan.newLabels {
+		color := l.Color
+		if color == "" {
+			color = "ededed"
+		}
+		created, err := im.labels.Create(repoID, l.Name, color)
+		if err != nil {
+			return fmt.Errorf("creating label %q: %w", l.Name, err)
+		}
+		plan.labelIDs[strings.ToLower(l.Name)] = created.ID
+		_, _ = fmt.Fprintf(im.errOut, "Created label %q\n", l.Name)
+	}
+
+	for _, title := range plan.newMilestones {
+		created, err := im.milestones.Create(title)
+		if err != nil {
+			return fmt.Errorf("creating milestone %q: %w", title, err)
+		}
+		plan.milestoneIDs[title] = created.ID
+		_, _ = fmt.Fprintf(im.errOut, "Created milestone %q\n", title)
+	}
+
+	for _, rec := range plan.creates {
+		input := data.IssueCreateInput{RepositoryID: repoID, Title: rec.Title, Body: rec.Body}
+		for _, l := range rec.Labels {
+			input.LabelIDs = append(input.LabelIDs, plan.labelIDs[strings.ToLower(l.Name)])
+		}
+		for _, a := range rec.Assignees {
+			input.AssigneeIDs = append(input.AssigneeIDs, plan.userIDs[a])
+		}
+		if rec.Milestone != "" {
+			input.MilestoneID = plan.milestoneIDs[rec.Milestone]
+		}
+
+		issue, err := im.issues.Create(input)
+		if err != nil {
+			return fmt.Errorf("creating issue for %s: %w", rec.ID, err)
+		}
+		mapping[rec.ID] = issue.Number
+		if err := writeJSONFile(im.mapping, mapping); err != nil {
+			return fmt.Errorf("writing mapping file: %w", err)
+		}
+		_, _ = fmt.Fprintf(im.out, "%s -> #%d %s\n", rec.ID, issue.Number, rec.Title)
+	}
+
+	_, _ = fmt.Fprintf(im.errOut, "Created %d issues, skipped %d already imported\n", len(plan.creates), len(plan.skipped))
+	return nil
+}
+
+// plan validates every record before anything is created, collecting all
+// problems into a single error.
+func (im *importer) plan(records []importRecord, mapping map[string]int) (*importPlan, error) {
+	labels, err := im.labels.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+	milestones, err := im.milestones.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing milestones: %w", err)
+	}
+
+	plan := &importPlan{
+		skipped:      map[string]int{},
+		labelIDs:     map[string]string{},
+		milestoneIDs: map[string]string{},
+		userIDs:      map[string]string{},
+	}
+	for _, l := range labels {
+		plan.labelIDs[strings.ToLower(l.Name)] = l.ID
+	}
+	for _, m := range milestones {
+		plan.milestoneIDs[m.Title] = m.ID
+	}
+
+	var problems []string
+	missingLabels := map[string]bool{}
+	missingMilestones := map[string]bool{}
+	unknownUsers := map[string]bool{}
+
+	for _, rec := range records {
+		if n, ok := mapping[rec.ID]; ok {
+			plan.skipped[rec.ID] = n
+			continue
+		}
+		if strings.TrimSpace(rec.Title) == "" {
+			problems = append(problems, fmt.Sprintf("%s: title is required", rec.ID))
+			continue
+		}
+
+		for _, l := range rec.Labels {
+			key := strings.ToLower(l.Name)
+			if _, ok := plan.labelIDs[key]; ok || missingLabels[key] {
+				continue
+			}
+			missingLabels[key] = true
+			if im.opts.createMissing {
+				plan.newLabels = append(plan.newLabels, l)
+			} else {
+				problems = append(problems, fmt.Sprintf("%s: label %q does not exist", rec.ID, l.Name))
+			}
+		}
+
+		if m := rec.Milestone; m != "" {
+			if _, ok := plan.milestoneIDs[m]; !ok && !missingMilestones[m] {
+				missingMilestones[m] = true
+				if im.opts.createMissing {
+					plan.newMilestones = append(plan.newMilestones, m)
+				} else {
+					problems = append(problems, fmt.Sprintf("%s: milestone %q does not exist", rec.ID, m))
+				}
+			}
+		}
+
+		for _, login := range rec.Assignees {
+			if _, ok := plan.userIDs[login]; ok || unknownUsers[login] {
+				continue
+			}
+			id, err := im.users.ID(login)
+			if err != nil {
+				unknownUsers[login] = true
+				problems = append(problems, fmt.Sprintf("%s: assignee %q: %v", rec.ID, login, err))
+				continue
+			}
+			plan.userIDs[login] = id
+		}
+
+		plan.creates = append(plan.creates, rec)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("validation failed:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return plan, nil
+}
+
+func printImportPlan(w io.Writer, plan *importPlan) error {
+	for _, l := range plan.newLabels {
+		if _, err := fmt.Fprintf(w, "Would create label %q\n", l.Name); err != nil {
+			return err
+		}
+	}
+	for _, m := range plan.newMilestones {
+		if _, err := fmt.Fprintf(w, "Would create milestone %q\n", m); err != nil {
+			return err
+		}
+	}
+
+	skipped := make([]string, 0, len(plan.skipped))
+	for id := range plan.skipped {
+		skipped = append(skipped, id)
+	}
+	sort.Strings(skipped)
+	for _, id := range skipped {
+		if _, err := fmt.Fprintf(w, "Would skip %s: already imported as #%d\n", id, plan.skipped[id]); err != nil {
+			return err
+		}
+	}
+
+	for _, rec := range plan.creates {
+		var details []string
+		if len(rec.Labels) > 0 {
+			names := make([]string, len(rec.Labels))
+			for i, l := range rec.Labels {
+				names[i] = l.Name
+			}
+			details = append(details, "labels: "+strings.Join(names, ", "))
+		}
+		if len(rec.Assignees) > 0 {
+			details = append(details, "assignees: "+strings.Join(rec.Assignees, ", "))
+		}
+		if rec.Milestone != "" {
+			details = append(details, "milestone: "+rec.Milestone)
+		}
+		line := fmt.Sprintf("Would create issue from %s: %s", rec.ID, strconv.Quote(rec.Title))
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, "; ") + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d to create, %d already imported\n", len(plan.creates), len(plan.skipped))
+	return err
+}
+
+// loadImportMapping reads the source id to issue number mapping, returning
+// an empty mapping when the file does not exist yet.
+func loadImportMapping(path string) (map[string]int, error) {
+	mapping := map[string]int{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return mapping, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping file: %w", err)
+	}
+	if err := json.Unmarshal(b, &mapping); err != nil {
+		return nil, fmt.Errorf("parsing mapping file %s: %w", path, err)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cboone/gh-problemas/internal/data"
+)
+
+type fakeIssueCreator struct {
+	created []data.IssueCreateInput
+	failOn  string
+	lookups int
+}
+
+func (f *fakeIssueCreator) RepositoryID() (string, error) {
+	f.lookups++
+	return "R_1", nil
+}
+
+func (f *fakeIssueCreator) Create(input data.IssueCreateInput) (data.Issue, error) {
+	if input.Title == f.failOn {
+		return data.Issue{}, fmt.Errorf("boom")
+	}
+	f.created = append(f.created, input)
+	return data.Issue{Number: 100 + len(f.created), Title: input.Title}, nil
+}
+
+type fakeLabelStore struct {
+	labels  []data.Label
+	created []string
+}
+
+func (f *fakeLabelStore) List() ([]data.Label, error) { return f.labels, nil }
+
+func (f *fakeLabelStore) Create(_, name, color string) (data.Label, error) {
+	f.created = append(f.created, name+"#"+color)
+	return data.Label{ID: "L_" + name, Name: name, Color: color}, nil
+}
+
+type fakeMilestoneStore struct {
+	milestones []data.Milestone
+	created    []string
+}
+
+func (f *fakeMilestoneStore) List() ([]data.Milestone, error) { return f.milestones, nil }
+
+func (f *fakeMilestoneStore) Create(title string) (data.Milestone, error) {
+	f.created = append(f.created, title)
+	return data.Milestone{ID: "M_" + title, Title: title}, nil
+}
+
+type fakeUsers map[string]string
+
+func (f fakeUsers) ID(login string) (string, error) {
+	if id, ok := f[login]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("user %q not found", login)
+}
+
+func newTestImporter(t *testing.T, opts importOptions) (*importer, *fakeIssueCreator, *fakeLabelStore, *fakeMilestoneStore) {
+	t.Helper()
+	issues := &fakeIssueCreator{}
+	labels := &fakeLabelStore{labels: []data.Label{{ID: "L_bug", Name: "Bug"}}}
+	milestones := &fakeMilestoneStore{milestones: []data.Milestone{{ID: "M_v1", Title: "v1"}}}
+	im := &importer{
+		issues:     issues,
+		labels:     labels,
+		milestones: milestones,
+		users:      fakeUsers{"alice": "U_alice"},
+		opts:       opts,
+		mapping:    filepath.Join(t.TempDir(), "issues.json.mapping.json"),
+		out:        &bytes.Buffer{},
+		errOut:     &bytes.Buffer{},
+	}
+	return im, issues, labels, milestones
+}
+
+func TestReadImportRecords_JSON(t *testing.T) {
+	input := `[
+		{"id": "a-1", "title": "First", "labels": ["bug", {"name": "area/ui", "color": "00ff00"}], "assignees": ["alice"], "milestone": "v1"},
+		{"number": 7, "title": "From list output"}
+	]`
+	records, err := readImportRecords(strings.NewReader(input), "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Labels[1] != (importLabel{Name: "area/ui", Color: "00ff00"}) {
+		t.Errorf("unexpected label: %+v", records[0].Labels[1])
+	}
+	if records[1].ID != "7" {
+		t.Errorf("expected number to be used as id, got %q", records[1].ID)
+	}
+}
+
+func TestReadImportRecords_CSV(t *testing.T) {
+	input := "id,title,body,labels,assignees,milestone\n" +
+		"x1,Crash on start,It crashes,\"bug, area/ui\",alice,v1\n"
+	records, err := readImportRecords(strings.NewReader(input), "csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rec := records[0]
+	if rec.ID != "x1" || rec.Title != "Crash on start" || len(rec.Labels) != 2 || rec.Labels[1].Name != "area/ui" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+}
+
+func TestReadImportRecords_DuplicateID(t *testing.T) {
+	input := `[{"id": "a", "title": "One"}, {"id": "a", "title": "Two"}]`
+	if _, err := readImportRecords(strings.NewReader(input), "json"); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate id error, got %v", err)
+	}
+}
+
+func TestImporter_ValidationCollectsProblems(t *testing.T) {
+	im, issues, _, _ := newTestImporter(t, importOptions{})
+	records := []importRecord{
+		{ID: "1", Title: "Missing label", Labels: []importLabel{{Name: "nope"}}},
+		{ID: "2", Title: "Unknown user", Assignees: []string{"mallory"}},
+		{ID: "3", Title: "Missing milestone", Milestone: "v9"},
+		{ID: "4"},
+	}
+
+	err := im.run(records)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`label "nope"`, `"mallory"`, `milestone "v9"`, "4: title is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got:\n%v", want, err)
+		}
+	}
+	if len(issues.created) != 0 {
+		t.Fatal("expected nothing to be created when validation fails")
+	}
+}
+
+func TestImporter_DryRun(t *testing.T) {
+	im, issues, labels, milestones := newTestImporter(t, importOptions{dryRun: true, createMissing: true})
+	records := []importRecord{
+		{ID: "1", Title: "New one", Labels: []importLabel{{Name: "bug"}, {Name: "area/ui"}}, Milestone: "v2"},
+	}
+
+	if err := im.run(records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues.created)+len(labels.created)+len(milestones.created)+issues.lookups != 0 {
+		t.Fatal("dry run should not create or look up anything")
+	}
+
+	out := im.out.(*bytes.Buffer).String()
+	for _, want := range []string{
+		`Would create label "area/ui"`,
+		`Would create milestone "v2"`,
+		`Would create issue from 1: "New one" (labels: bug, area/ui; milestone: v2)`,
+		"1 to create, 0 already imported",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestImporter_CreatesAndSkipsOnRerun(t *testing.T) {
+	im, issues, labels, milestones := newTestImporter(t, importOptions{createMissing: true})
+	records := []importRecord{
+		{ID: "1", Title: "First", Labels: []importLabel{{Name: "BUG"}, {Name: "area/ui", Color: "00ff00"}}, Assignees: []string{"alice"}, Milestone: "v1"},
+		{ID: "2", Title: "Second", Milestone: "v2"},
+	}
+
+	if err := im.run(records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues.created) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues.created))
+	}
+	first := issues.created[0]
+	if strings.Join(first.LabelIDs, ",") != "L_bug,L_area/ui" || first.AssigneeIDs[0] != "U_alice" || first.MilestoneID != "M_v1" {
+		t.Errorf("unexpected create input: %+v", first)
+	}
+	if issues.created[1].MilestoneID != "M_v2" {
+		t.Errorf("expected created milestone id, got %q", issues.created[1].MilestoneID)
+	}
+	if issues.lookups != 1 || first.RepositoryID != "R_1" || issues.created[1].RepositoryID != "R_1" {
+		t.Errorf("expected the repository ID to be looked up once and reused, got %d lookups", issues.lookups)
+	}
+	if len(labels.created) != 1 || labels.created[0] != "area/ui#00ff00" {
+		t.Errorf("unexpected created labels: %v", labels.created)
+	}
+	if len(milestones.created) != 1 {
+		t.Errorf("unexpected created milestones: %v", milestones.created)
+	}
+
+	mapping, err := loadImportMapping(im.mapping)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mapping["1"] != 101 || mapping["2"] != 102 {
+		t.Fatalf("unexpected mapping: %v", mapping)
+	}
+
+	rerun, rerunIssues, _, _ := newTestImporter(t, importOptions{})
+	rerun.mapping = im.mapping
+	if err := rerun.run(records); err != nil {
+		t.Fatalf("unexpected error on rerun: %v", err)
+	}
+	if len(rerunIssues.created) != 0 {
+		t.Fatalf("expected rerun to skip imported records, created %d", len(rerunIssues.created))
+	}
+}
+
+func TestImporter_PartialFailureKeepsMapping(t *testing.T) {
+	im, issues, _, _ := newTestImporter(t, importOptions{})
+	issues.failOn = "Second"
+	records := []importRecord{{ID: "1", Title: "First"}, {ID: "2", Title: "Second"}}
+
+	if err := im.run(records); err == nil {
+		t.Fatal("expected error")
+	}
+	b, err := os.ReadFile(im.mapping)
+	if err != nil {
+		t.Fatalf("expected mapping file: %v", err)
+	}
+	if !strings.Contains(string(b), `"1": 101`) {
+		t.Fatalf("expected first record in mapping, got %s", b)
+	}
+}
+
+func TestImportFormat(t *testing.T) {
+	if f, err := importFormat("issues.CSV", "auto"); err != nil || f != "csv" {
+		t.Errorf("expected csv, got %q, %v", f, err)
+	}
+	if _, err := importFormat("issues.txt", "auto"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+	if _, err := importFormat("issues.json", "xml"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+// listOptions holds the flags for the list command.
+type listOptions struct {
+	filterOptions
+	limit    int
+	format   string
+	fields   []string
+	jq       string
+	template string
+}
+
+var listOpts listOptions
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print issues without starting the TUI",
+	Long: `Print issues matching a filter without starting the TUI.
+
+Output is a table by default. Use --format json or csv for machine-readable
+output, --jq to filter JSON, or --template to render with a Go template.
+Field names are stable: ` + strings.Join(listFields(), ", ") + `.`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	f := listCmd.Flags()
+	listOpts.addFlags(f)
+	f.IntVarP(&listOpts.limit, "limit", "L", 30, "Maximum number of issues to print")
+	f.StringVar(&listOpts.format, "format", "table", "Output format: {table|json|csv}")
+	f.StringSliceVar(&listOpts.fields, "fields", nil, "Comma-separated fields to output")
+	f.StringVarP(&listOpts.jq, "jq", "q", "", "Filter JSON output using a jq expression")
+	f.StringVarP(&listOpts.template, "template", "t", "", "Format JSON output using a Go template")
+	rootCmd.AddCommand(listCmd)
+}
+
+// listFields returns the fields available to the list command. Bodies are
+// not fetched by list queries.
+func listFields() []string {
+	fields := make([]string, 0, len(issueFields))
+	for _, f := range issueFields {
+		if f != "body" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	if err := listOpts.validate(); err != nil {
+		return err
+	}
+
+	s, err := newSession()
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	pageSize := s.cfg.Defaults.PageSize
+	client := s.issueClient()
+	issues, err := fetchIssues(client, listOpts, pageSize)
+	if err != nil {
+		return err
+	}
+
+	return printIssues(cmd.OutOrStdout(), cmd.ErrOrStderr(), issues, listOpts, term.FromEnv())
+}
+
+func (o listOptions) validate() error {
+	if err := o.filterOptions.validate(); err != nil {
+		return err
+	}
+	if o.limit < 1 {
+		return fmt.Errorf("invalid --limit %d: must be at least 1", o.limit)
+	}
+	switch o.format {
+	case "table", "json", "csv":
+	default:
+		return fmt.Errorf("invalid --format %q: expected table, json, or csv", o.format)
+	}
+	if o.jq != "" && o.template != "" {
+		return fmt.Errorf("cannot use --jq and --template together")
+	}
+	if (o.jq != "" || o.template != "") && o.format == "csv" {
+		return fmt.Errorf("--jq and --template cannot be used with --format csv")
+	}
+	return validateFields(o.fields, listFields())
+}
+
+// fetchIssues pages through matching issues until the limit is reached or
+// results are exhausted.
+func fetchIssues(client issueLister, o listOptions, pageSize int) ([]data.Issue, error) {
+	paginator := data.NewPaginator(pageSize)
+	var issues []data.Issue
+
+	for len(issues) < o.limit {
+		req := paginator.NextPageRequest()
+		if req == nil {
+			break
+		}
+		first := req.First
+		if remaining := o.limit - len(issues); remaining < first {
+			first = remaining
+		}
+
+		result, err := o.fetchPage(client, first, req.After)
+		if err != nil {
+			return nil, err
+		}
+
+		paginator.Update(result.PageInfo, len(result.Issues))
+		issues = append(issues, result.Issues...)
+	}
+
+	if len(issues) > o.limit {
+		issues = issues[:o.limit]
+	}
+	return issues, nil
+}
+
+func printIssues(w, errW io.Writer, issues []data.Issue, o listOptions, t term.Term) error {
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+
+	fields := o.fields
+	if len(fields) == 0 {
+		fields = listFields()
+	}
+	records := make([]map[string]interface{}, len(issues))
+	for i, issue := range issues {
+		records[i] = issueRecord(issue)
+	}
+	records = selectFields(records, fields)
+
+	switch {
+	case o.template != "":
+		return writeTemplate(w, records, o.template, width, t.IsColorEnabled())
+	case o.jq != "" || o.format == "json":
+		return writeJSON(w, records, o.jq)
+	case o.format == "csv":
+		return writeCSV(w, records, fields)
+	default:
+		if len(issues) == 0 {
+			_, err := fmt.Fprintln(errW, "No issues match the given filters")
+			return err
+		}
+		if len(o.fields) > 0 {
+			return writeRecordTable(w, records, fields, t.IsTerminalOutput(), width)
+		}
+		return writeIssueTable(w, issues, t.IsTerminalOutput(), width)
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// pagedLister serves issues in fixed-size pages and records requests.
+type pagedLister struct {
+	issues   []data.Issue
+	requests []data.IssueListOptions
+	queries  []string
+	// total is the number of matches a search reports, if not len(issues).
+	total int
+}
+
+func (p *pagedLister) page(first int, after string) data.IssueListResult {
+	start := 0
+	if after != "" {
+		for i, issue := range p.issues {
+			if after == issueCursor(issue) {
+				start = i + 1
+			}
+		}
+	}
+	end := start + first
+	if end > len(p.issues) {
+		end = len(p.issues)
+	}
+	page := p.issues[start:end]
+	cursor := ""
+	if len(page) > 0 {
+		cursor = issueCursor(page[len(page)-1])
+	}
+	return data.IssueListResult{
+		Issues:   page,
+		PageInfo: data.PageInfo{HasNextPage: end < len(p.issues), EndCursor: cursor},
+	}
+}
+
+func (p *pagedLister) List(opts data.IssueListOptions) (data.IssueListResult, error) {
+	p.requests = append(p.requests, opts)
+	return p.page(opts.First, opts.After), nil
+}
+
+func (p *pagedLister) Search(query string, first int, after string) (data.IssueListResult, error) {
+	p.queries = append(p.queries, query)
+	result := p.page(first, after)
+	result.TotalCount = max(p.total, len(p.issues))
+	return result, nil
+}
+
+func issueCursor(issue data.Issue) string {
+	return "cursor-" + issue.Title
+}
+
+func sampleIssues(n int) []data.Issue {
+	issues := make([]data.Issue, n)
+	for i := range issues {
+		issues[i] = data.Issue{
+			Number:    i + 1,
+			Title:     "Issue " + string(rune('A'+i)),
+			State:     "OPEN",
+			Author:    "alice",
+			CreatedAt: time.Date(2025, time.January, i+1, 0, 0, 0, 0, time.UTC),
+			Labels:    []data.Label{{Name: "bug", Color: "d73a4a"}},
+		}
+	}
+	return issues
+}
+
+func defaultListOptions() listOptions {
+	return listOptions{
+		filterOptions: filterOptions{state: "open", sort: "created", order: "desc"},
+		limit:         30,
+		format:        "table",
+	}
+}
+
+func TestFetchIssues_PaginatesToLimit(t *testing.T) {
+	lister := &pagedLister{issues: sampleIssues(7)}
+	opts := defaultListOptions()
+	opts.limit = 5
+
+	issues, err := fetchIssues(lister, opts, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 5 {
+		t.Fatalf("expected 5 issues, got %d", len(issues))
+	}
+	if len(lister.requests) != 3 {
+		t.Fatalf("expected 3 page requests, got %d", len(lister.requests))
+	}
+	if last := lister.requests[2]; last.First != 1 {
+		t.Errorf("expected final request to ask for 1 issue, got %d", last.First)
+	}
+	if lister.requests[0].States[0] != "OPEN" || lister.requests[0].OrderBy.Field != "CREATED_AT" {
+		t.Errorf("unexpected list options: %+v", lister.requests[0])
+	}
+}
+
+func TestFetchIssues_StopsWhenExhausted(t *testing.T) {
+	lister := &pagedLister{issues: sampleIssues(3)}
+	issues, err := fetchIssues(lister, defaultListOptions(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(issues))
+	}
+}
+
+func TestFetchIssues_SearchUsesFilters(t *testing.T) {
+	lister := &pagedLister{issues: sampleIssues(1)}
+	opts := defaultListOptions()
+	opts.search = "crash"
+	opts.labels = []string{"needs triage"}
+	opts.sort = "updated"
+
+	if _, err := fetchIssues(lister, opts, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `crash is:open label:"needs triage" sort:updated-desc`
+	if len(lister.queries) != 1 || lister.queries[0] != want {
+		t.Fatalf("expected search query %q, got %v", want, lister.queries)
+	}
+}
+
+func TestListOptions_Validate(t *testing.T) {
+	opts := defaultListOptions()
+	if err := opts.validate(); err != nil {
+		t.Fatalf("unexpected error for defaults: %v", err)
+	}
+
+	bad := defaultListOptions()
+	bad.fields = []string{"number", "nope"}
+	if err := bad.validate(); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+
+	bad = defaultListOptions()
+	bad.format = "csv"
+	bad.jq = ".[]"
+	if err := bad.validate(); err == nil {
+		t.Fatal("expected error for --jq with csv")
+	}
+}
+
+func TestWriteCSV_StableColumns(t *testing.T) {
+	records := []map[string]interface{}{issueRecord(sampleIssues(1)[0])}
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, records, []string{"number", "title", "labels", "createdAt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "number,title,labels,createdAt\n1,Issue A,bug,2025-01-01T00:00:00Z\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSON_WithJQ(t *testing.T) {
+	records := selectFields([]map[string]interface{}{issueRecord(sampleIssues(1)[0])}, []string{"number", "title"})
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, records, ".[].title"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "Issue A" {
+		t.Fatalf("unexpected jq output: %q", buf.String())
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	records := []map[string]interface{}{issueRecord(sampleIssues(1)[0])}
+	var buf bytes.Buffer
+	if err := writeTemplate(&buf, records, `{{range .}}#{{.number}} {{.title}}{{"\n"}}{{end}}`, 80, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "#1 Issue A\n" {
+		t.Fatalf("unexpected template output: %q", buf.String())
+	}
+}
+
+func TestPrintIssues_TableHonorsFields(t *testing.T) {
+	opts := defaultListOptions()
+	opts.fields = []string{"number", "labels", "createdAt"}
+	issues := sampleIssues(1)
+	issues[0].Labels = append(issues[0].Labels, data.Label{Name: "ui"})
+	var buf bytes.Buffer
+	if err := printIssues(&buf, io.Discard, issues, opts, term.FromEnv()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "1\tbug, ui\t2025-01-01T00:00:00Z\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected table:\n%q", buf.String())
+	}
+}
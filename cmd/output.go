@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/utils"
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/template"
+)
+
+// issueFields lists the stable field names used for JSON, CSV, and template
+// output, in column order. Names are derived from data.Issue.
+var issueFields = []string{
+	"number",
+	"title",
+	"state",
+	"author",
+	"labels",
+	"assignees",
+	"milestone",
+	"commentCount",
+	"reactionCount",
+	"createdAt",
+	"updatedAt",
+	"url",
+	"body",
+}
+
+// labelRecord is the output shape of a data.Label.
+type labelRecord struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// issueRecord converts an issue to a map keyed by its output field names.
+func issueRecord(issue data.Issue) map[string]interface{} {
+	labels := make([]labelRecord, len(issue.Labels))
+	for i, l := range issue.Labels {
+		labels[i] = labelRecord{Name: l.Name, Color: l.Color}
+	}
+
+	assignees := issue.Assignees
+	if assignees == nil {
+		assignees = []string{}
+	}
+
+	return map[string]interface{}{
+		"number":        issue.Number,
+		"title":         issue.Title,
+		"state":         issue.State,
+		"author":        issue.Author,
+		"labels":        labels,
+		"assignees":     assignees,
+		"milestone":     issue.Milestone,
+		"commentCount":  issue.CommentCount,
+		"reactionCount": issue.ReactionCount,
+		"createdAt":     issue.CreatedAt,
+		"updatedAt":     issue.UpdatedAt,
+		"url":           issue.URL,
+		"body":          issue.Body,
+	}
+}
+
+// commentRecord converts a comment to a map keyed by its output field names.
+func commentRecord(c data.Comment) map[string]interface{} {
+	return map[string]interface{}{
+		"author":        c.Author,
+		"body":          c.Body,
+		"createdAt":     c.CreatedAt,
+		"updatedAt":     c.UpdatedAt,
+		"reactionCount": c.Reactions,
+	}
+}
+
+// selectFields restricts records to the given field names. An empty field
+// list keeps every field.
+func selectFields(records []map[string]interface{}, fields []string) []map[string]interface{} {
+	if len(fields) == 0 {
+		return records
+	}
+
+	selected := make([]map[string]interface{}, len(records))
+	for i, r := range records {
+		s := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			s[f] = r[f]
+		}
+		selected[i] = s
+	}
+	return selected
+}
+
+// validateFields returns an error naming the first unknown field.
+func validateFields(fields, known []string) error {
+	for _, f := range fields {
+		found := false
+		for _, k := range known {
+			if f == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown field %q; available fields: %s", f, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// writeJSON writes value as indented JSON, or filters it through a jq
+// expression when one is given.
+func writeJSON(w io.Writer, value interface{}, jqExpr string) error {
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if jqExpr != "" {
+		return jq.Evaluate(bytes.NewReader(b), w, jqExpr)
+	}
+
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// writeTemplate renders value through a Go text/template using the gh
+// template helpers (tablerow, timeago, color, and so on).
+func writeTemplate(w io.Writer, value interface{}, tmpl string, width int, color bool) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	t := template.New(w, width, color)
+	if err := t.Parse(tmpl); err != nil {
+		return err
+	}
+	if err := t.Execute(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	return t.Flush()
+}
+
+// writeCSV writes records with a header row, one column per field.
+func writeCSV(w io.Writer, records []map[string]interface{}, fields []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(fields); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		row := make([]string, len(fields))
+		for i, f := range fields {
+			row[i] = csvValue(r[f])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ",")
+	case []labelRecord:
+		names := make([]string, len(v))
+		for i, l := range v {
+			names[i] = l.Name
+		}
+		return strings.Join(names, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// writeRecordTable writes a human-readable table of records with a column
+// per field, for --fields with --format table.
+func writeRecordTable(w io.Writer, records []map[string]interface{}, fields []string, isTTY bool, width int) error {
+	tp := tableprinter.New(w, isTTY, width)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = strings.ToUpper(f)
+	}
+	tp.AddHeader(header)
+	for _, r := range records {
+		for _, f := range fields {
+			tp.AddField(tableValue(r[f], isTTY))
+		}
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// tableValue formats a field for a table: lists comma-separated with
+// spaces, and times relative to now on a terminal.
+func tableValue(v interface{}, isTTY bool) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case []labelRecord:
+		names := make([]string, len(v))
+		for i, l := range v {
+			names[i] = l.Name
+		}
+		return strings.Join(names, ", ")
+	case time.Time:
+		if isTTY && !v.IsZero() {
+			return utils.RelativeTime(v)
+		}
+	}
+	return csvValue(v)
+}
+
+// writeIssueTable writes a human-readable table of issues.
+func writeIssueTable(w io.Writer, issues []data.Issue, isTTY bool, width int) error {
+	tp := tableprinter.New(w, isTTY, width)
+	tp.AddHeader([]string{"NUMBER", "TITLE", "LABELS", "AUTHOR", "CREATED"})
+	for _, issue := range issues {
+		names := make([]string, len(issue.Labels))
+		for i, l := range issue.Labels {
+			names[i] = l.Name
+		}
+
+		tp.AddField(fmt.Sprintf("#%d", issue.Number))
+		tp.AddField(issue.Title)
+		tp.AddField(strings.Join(names, ", "))
+		tp.AddField(issue.Author)
+		if isTTY {
+			tp.AddField(utils.RelativeTime(issue.CreatedAt))
+		} else {
+			tp.AddField(issue.CreatedAt.UTC().Format(time.RFC3339))
+		}
+		tp.EndRow()
+	}
+	return tp.Render()
+}
@@ -2,18 +2,36 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/cboone/gh-problemas/internal/config"
 	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/fakegithub"
 	"github.com/cboone/gh-problemas/internal/ui"
 	"github.com/cboone/gh-problemas/internal/ui/views"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/spf13/cobra"
 )
 
+// hostname is the --hostname flag, shared by every command.
+var hostname string
+
+// recordPath and replayPath are the --record and --replay flags.
+var (
+	recordPath string
+	replayPath string
+)
+
+// demoMode is the --demo flag.
+var demoMode bool
+
 var rootCmd = &cobra.Command{
 	Use:           "gh-problemas",
 	Short:         "A terminal UI for triaging and managing GitHub issues",
@@ -24,72 +42,395 @@ var rootCmd = &cobra.Command{
 }
 
 func runApp(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	s, err := newSession()
 	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+		return err
 	}
+	defer s.close()
 
-	gqlClient, err := api.DefaultGraphQLClient()
+	pageSize := s.cfg.Defaults.PageSize
+	dateFormat := s.cfg.Defaults.DateFormat
+	sections, err := dashboardSections(s.cfg.Sections)
 	if err != nil {
-		return fmt.Errorf("could not create GitHub API client: %w\nTry running: gh auth login", err)
+		return fmt.Errorf("loading config: %w", err)
 	}
-
-	owner, name, err := resolveRepository(cfg.Defaults.Repo, gqlClient)
+	// Remembered sorts are a convenience, so a damaged state file only
+	// costs them.
+	state, err := config.LoadState(config.StateDir())
 	if err != nil {
-		return err
+		fmt.Fprintf(os.Stderr, "Warning: %v; starting with an empty state\n", err)
+		if s.debug != nil {
+			s.debug.logger.Warn("ignoring state", "error", err)
+		}
 	}
+	priorityLabels := s.cfg.Defaults.PriorityLabels
 
-	repoName := owner + "/" + name
-	pageSize := cfg.Defaults.PageSize
-	dateFormat := cfg.Defaults.DateFormat
-
-	issueClient := data.NewIssueClient(gqlClient, owner, name)
-	commentClient := data.NewCommentClient(gqlClient, owner, name)
+	issueClient := s.issueClient()
+	commentClient := data.NewCommentClient(s.querier, s.owner, s.name)
+	reactionClient := data.NewReactionClient(s.querier)
+	timelineClient := data.NewTimelineClient(s.querier, s.owner, s.name)
+	labelClient := data.NewLabelClient(s.querier, s.owner, s.name)
+	projectClient := data.NewProjectClient(s.querier, s.owner, s.name)
+	issueTypeClient := data.NewIssueTypeClient(s.querier, s.owner, s.name)
+	hyperlinks := hyperlinksSupported()
 	app := ui.NewApp(
 		issueClient,
-		repoName,
+		s.repoName(),
 		func(a *ui.App) ui.View {
-			return views.NewDashboardViewWithPageSize(a.IssueClient(), a.Styles(), a.Keys(), a.Width(), a.Height(), pageSize)
+			dashboard := views.NewDashboardViewWithPageSize(a.IssueClient(), a.Styles(), a.Keys(), a.Width(), a.Height(), pageSize)
+			dashboard.SetSections(sections)
+			dashboard.SetSortMemory(state)
+			dashboard.SetPriorityLabels(priorityLabels)
+			dashboard.SetLabelClient(labelClient)
+			dashboard.SetProjectClient(projectClient)
+			return dashboard
 		},
 		func(a *ui.App, issueNumber int) ui.View {
-			return views.NewDetailViewWithCommentsAndDateFormat(a.IssueClient(), commentClient, a.Styles(), a.Keys(), issueNumber, a.Width(), a.Height(), dateFormat)
+			detail := views.NewDetailViewWithCommentsAndDateFormat(a.IssueClient(), commentClient, a.Styles(), a.Keys(), issueNumber, a.Width(), a.Height(), dateFormat)
+			detail.SetReactionClient(reactionClient)
+			detail.SetTimelineClient(timelineClient)
+			detail.SetProjectClient(projectClient)
+			if s.features.IssueTypes {
+				detail.SetIssueTypeClient(issueTypeClient)
+			}
+			detail.SetHyperlinks(hyperlinks)
+			return detail
 		},
 	)
 
+	app.SetEditor(ui.ExternalEditor(editorCommand()))
+	app.SetBrowser(ui.ExternalBrowser(browserCommand()))
+	if s.debug != nil {
+		app.SetDebugLog(s.debug.logger, s.debug.path)
+	}
+
 	p := tea.NewProgram(app, tea.WithAltScreen())
+	s.querier.OnRateLimit = func(rl data.RateLimit) {
+		p.Send(ui.RateLimitMsg{RateLimit: rl})
+	}
 	_, err = p.Run()
 	return err
 }
 
-func resolveRepository(configRepo string, gqlClient data.Querier) (string, string, error) {
-	owner := ""
-	name := ""
+// hyperlinksSupported reports whether to write OSC 8 hyperlinks: when the
+// terminal takes colors, and is not one of the few that print the escape
+// sequences instead of ignoring them.
+func hyperlinksSupported() bool {
+	switch os.Getenv("TERM") {
+	case "dumb", "linux":
+		return false
+	}
+	return term.FromEnv().IsColorEnabled()
+}
+
+// session holds the configuration, API clients, and repository shared by
+// the TUI and the non-interactive subcommands.
+type session struct {
+	cfg     *config.Config
+	repo    repoRef
+	querier *data.RateLimitQuerier
+	rest    data.RESTDoer
+	owner   string
+	name    string
+	// features are the optional issue fields the host has.
+	features data.IssueFeatures
+	// record is the cassette being recorded, or nil.
+	record *data.Cassette
+	// debug is the log written in debug mode, or nil.
+	debug *debugLog
+}
+
+func newSession() (*session, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	clients := newHostClients(time.Duration(cfg.Defaults.RequestTimeout) * time.Second)
+	var debug *debugLog
+	if debugEnabled() {
+		if debug, err = openDebugLog(config.StateDir()); err != nil {
+			return nil, err
+		}
+		clients.logger = debug.logger
+	}
+	if demoMode {
+		clients.demo = fakegithub.NewServer(fakegithub.NewDemoStore(time.Now()))
+	}
+	if replayPath != "" {
+		if clients.replay, err = data.LoadCassette(replayPath); err != nil {
+			return nil, err
+		}
+	}
+	if recordPath != "" {
+		clients.record = data.NewCassette(recordPath)
+	}
+	querierFor := func(host string) (data.Querier, error) {
+		return clients.querier(host)
+	}
+
+	var repo repoRef
+	if clients.demo != nil {
+		repo = repoRef{host: defaultHost, owner: fakegithub.DemoOwner, name: fakegithub.DemoName}
+	} else if clients.replay != nil && clients.replay.Repository != "" {
+		// A cassette only holds data for the repository it was recorded
+		// against.
+		var ok bool
+		if repo, ok = parseRepoRef(clients.replay.Repository); !ok || repo.host == "" {
+			return nil, fmt.Errorf("invalid repository %q in cassette %s", clients.replay.Repository, replayPath)
+		}
+	} else {
+		repo, err = resolveRepository(cfg.Defaults.Repo, cfg.Defaults.Host, hostname, querierFor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if clients.record != nil {
+		// Saving the empty cassette now reports an unwritable path before
+		// anything is recorded.
+		clients.record.SetRepository(repo.host + "/" + repo.owner + "/" + repo.name)
+		if err := clients.record.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	if debug != nil {
+		debug.logger.Info("session started", "version", version, "args", os.Args[1:], "repository", repo.host+"/"+repo.owner+"/"+repo.name)
+	}
+
+	querier, err := clients.querier(repo.host)
+	if err != nil {
+		return nil, err
+	}
+	restClient, err := clients.restClient(repo.host)
+	if err != nil {
+		return nil, err
+	}
+
+	// github.com and its data residency hosts have every feature;
+	// Enterprise Server depends on its version.
+	features := data.AllIssueFeatures
+	if auth.IsEnterprise(repo.host) {
+		if features, err = data.DetectIssueFeatures(querier); err != nil {
+			return nil, fmt.Errorf("checking the features of %s: %w", repo.host, err)
+		}
+	}
+
+	return &session{
+		cfg:      cfg,
+		repo:     repo,
+		querier:  querier,
+		rest:     restClient,
+		owner:    repo.owner,
+		name:     repo.name,
+		features: features,
+		record:   clients.record,
+		debug:    debug,
+	}, nil
+}
+
+// issueClient returns an IssueClient for the session's repository that
+// selects only the fields its host has.
+func (s *session) issueClient() *data.IssueClient {
+	client := data.NewIssueClient(s.querier, s.owner, s.name)
+	client.SetFeatures(s.features)
+	return client
+}
+
+// close releases the session's resources and saves the recording, which is
+// written once at the end rather than after every request.
+func (s *session) close() {
+	if s.record != nil {
+		if err := s.record.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if s.debug != nil {
+		s.debug.Close()
+	}
+}
+
+func (s *session) repoName() string {
+	return s.repo.String()
+}
+
+// repoRef identifies a repository on a GitHub host.
+type repoRef struct {
+	host  string
+	owner string
+	name  string
+}
+
+// String returns OWNER/REPO, prefixed with the host when it is not
+// github.com.
+func (r repoRef) String() string {
+	if r.host == "" || r.host == defaultHost {
+		return r.owner + "/" + r.name
+	}
+	return r.host + "/" + r.owner + "/" + r.name
+}
+
+const defaultHost = "github.com"
+
+// resolveRepository determines the repository to work on and its host.
+//
+// The repository comes from defaults.repo, written as [HOST/]OWNER/REPO, or
+// else from the git context. Its host is the --hostname flag if given, then
+// the host named in defaults.repo or the git remote, then defaults.host, and
+// finally gh's default host. An "@me" owner is resolved with a client for
+// that host.
+func resolveRepository(configRepo, configHost, flagHost string, querierFor func(host string) (data.Querier, error)) (repoRef, error) {
+	var repo repoRef
 
 	if configRepo != "" {
-		parts := strings.Split(configRepo, "/")
-		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-			return "", "", fmt.Errorf("invalid defaults.repo %q: expected owner/repo", configRepo)
+		var ok bool
+		if repo, ok = parseRepoRef(configRepo); !ok {
+			return repoRef{}, fmt.Errorf("invalid defaults.repo %q: expected [host/]owner/repo", configRepo)
 		}
-		owner = parts[0]
-		name = parts[1]
 	} else {
-		repo, err := repository.Current()
+		current, err := repository.Current()
 		if err != nil {
-			return "", "", fmt.Errorf("could not determine repository: %w", err)
+			return repoRef{}, fmt.Errorf("could not determine repository: %w", err)
+		}
+		repo = repoRef{host: current.Host, owner: current.Owner, name: current.Name}
+	}
+
+	switch {
+	case flagHost != "":
+		repo.host = flagHost
+	case repo.host == "" && configHost != "":
+		repo.host = configHost
+	case repo.host == "":
+		repo.host, _ = auth.DefaultHost()
+	}
+	repo.host = auth.NormalizeHostname(repo.host)
+
+	if repo.owner == "@me" {
+		q, err := querierFor(repo.host)
+		if err != nil {
+			return repoRef{}, err
+		}
+		login, err := data.NewUserClient(q).WhoAmI()
+		if err != nil {
+			return repoRef{}, fmt.Errorf("resolving @me for defaults.repo: %w", err)
+		}
+		repo.owner = login
+	}
+
+	return repo, nil
+}
+
+// parseRepoRef parses [HOST/]OWNER/REPO.
+func parseRepoRef(s string) (repoRef, bool) {
+	parts := strings.Split(s, "/")
+	for _, p := range parts {
+		if p == "" {
+			return repoRef{}, false
 		}
-		owner = repo.Owner
-		name = repo.Name
 	}
+	switch len(parts) {
+	case 2:
+		return repoRef{owner: parts[0], name: parts[1]}, true
+	case 3:
+		return repoRef{host: parts[0], owner: parts[1], name: parts[2]}, true
+	}
+	return repoRef{}, false
+}
 
-	if owner == "@me" {
-		login, err := data.NewUserClient(gqlClient).WhoAmI()
+// hostClients builds the API clients for a host on demand, authenticated
+// with the token gh has for that host and wrapped for recording, replay, or
+// the demo as the flags ask. They are cached per host, so resolving the
+// repository and working on it share a client and its rate limit budget.
+type hostClients struct {
+	timeout time.Duration
+	// record, if set, captures all GraphQL traffic.
+	record *data.Cassette
+	// replay, if set, answers all GraphQL requests instead of GitHub.
+	replay *data.Cassette
+	// demo, if set, answers all requests from a generated repository.
+	demo *fakegithub.Server
+	// logger, if set, logs every GraphQL request.
+	logger  *slog.Logger
+	graphql map[string]*data.RateLimitQuerier
+	rest    map[string]data.RESTDoer
+}
+
+func newHostClients(timeout time.Duration) *hostClients {
+	return &hostClients{
+		timeout: timeout,
+		graphql: map[string]*data.RateLimitQuerier{},
+		rest:    map[string]data.RESTDoer{},
+	}
+}
+
+// querier returns the GraphQL querier for host, with request timeouts and
+// rate limit handling.
+func (h *hostClients) querier(host string) (*data.RateLimitQuerier, error) {
+	if q, ok := h.graphql[host]; ok {
+		return q, nil
+	}
+
+	var base data.ContextQuerier
+	switch {
+	case h.demo != nil:
+		base = h.demo
+	case h.replay != nil:
+		base = h.replay.Player(host)
+	default:
+		client, err := api.NewGraphQLClient(api.ClientOptions{Host: host})
 		if err != nil {
-			return "", "", fmt.Errorf("resolving @me for defaults.repo: %w", err)
+			return nil, clientError(host, err)
 		}
-		owner = login
+		base = data.NewTimeoutQuerier(client, h.timeout)
+	}
+	if h.record != nil {
+		base = h.record.Recorder(host, base)
+	}
+	if h.logger != nil {
+		base = data.NewLoggingQuerier(base, h.logger.With("host", host))
 	}
 
-	return owner, name, nil
+	q := data.NewRateLimitQuerier(base)
+	h.graphql[host] = q
+	return q, nil
+}
+
+// restClient returns the REST client for host. REST traffic is not
+// recorded, so there is no REST client when replaying.
+func (h *hostClients) restClient(host string) (data.RESTDoer, error) {
+	if h.demo != nil {
+		return h.demo.LocalREST(), nil
+	}
+	if h.replay != nil {
+		return nil, nil
+	}
+	if c, ok := h.rest[host]; ok {
+		return c, nil
+	}
+	client, err := api.NewRESTClient(api.ClientOptions{Host: host, Timeout: h.timeout})
+	if err != nil {
+		return nil, clientError(host, err)
+	}
+	h.rest[host] = client
+	return client, nil
+}
+
+func clientError(host string, err error) error {
+	login := "gh auth login"
+	if host != defaultHost {
+		login += " --hostname " + host
+	}
+	return fmt.Errorf("could not create GitHub API client for %s: %w\nTry running: %s", host, err, login)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&hostname, "hostname", "", "GitHub host to use, e.g. github.example.com")
+	rootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "Record GraphQL traffic to a cassette `file`, with tokens scrubbed")
+	rootCmd.PersistentFlags().StringVar(&replayPath, "replay", "", "Answer GraphQL requests from a cassette `file` instead of GitHub")
+	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Log requests and UI events to debug.log in the state directory (also GH_PROBLEMAS_DEBUG=1)")
+	rootCmd.PersistentFlags().BoolVar(&demoMode, "demo", false, "Use a generated in-memory repository instead of GitHub; no login needed")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.MarkFlagsMutuallyExclusive("demo", "replay")
 }
 
 // Execute runs the root command.
@@ -97,7 +438,11 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// version is the program version, as set by SetVersion.
+var version string
+
 // SetVersion sets the version string on the root command.
 func SetVersion(v string) {
+	version = v
 	rootCmd.Version = v
 }
@@ -3,7 +3,13 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
 )
 
 type mockQuerier struct {
@@ -22,20 +28,32 @@ func (m *mockQuerier) Do(_ string, _ map[string]interface{}, resp interface{}) e
 	return json.Unmarshal(b, resp)
 }
 
+// querierFor returns a querier factory that always hands out q and records
+// the hosts it was asked for.
+func querierFor(q data.Querier, hosts *[]string) func(string) (data.Querier, error) {
+	return func(host string) (data.Querier, error) {
+		if hosts != nil {
+			*hosts = append(*hosts, host)
+		}
+		return q, nil
+	}
+}
+
 func TestResolveRepository_ConfigRepo(t *testing.T) {
-	owner, repo, err := resolveRepository("octo/proj", &mockQuerier{})
+	repo, err := resolveRepository("octo/proj", "", "", querierFor(&mockQuerier{}, nil))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if owner != "octo" || repo != "proj" {
-		t.Fatalf("unexpected owner/repo: %s/%s", owner, repo)
+	if repo.owner != "octo" || repo.name != "proj" {
+		t.Fatalf("unexpected owner/repo: %s/%s", repo.owner, repo.name)
 	}
 }
 
 func TestResolveRepository_InvalidConfigRepo(t *testing.T) {
-	_, _, err := resolveRepository("not-valid", &mockQuerier{})
-	if err == nil {
-		t.Fatal("expected error for invalid defaults.repo")
+	for _, configRepo := range []string{"not-valid", "a/b/c/d", "host//repo"} {
+		if _, err := resolveRepository(configRepo, "", "", querierFor(&mockQuerier{}, nil)); err == nil {
+			t.Fatalf("expected error for invalid defaults.repo %q", configRepo)
+		}
 	}
 }
 
@@ -46,18 +64,231 @@ func TestResolveRepository_AtMe(t *testing.T) {
 		},
 	}}
 
-	owner, repo, err := resolveRepository("@me/proj", q)
+	var hosts []string
+	repo, err := resolveRepository("ghe.example.com/@me/proj", "", "", querierFor(q, &hosts))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if owner != "alice" || repo != "proj" {
-		t.Fatalf("unexpected owner/repo: %s/%s", owner, repo)
+	if repo.owner != "alice" || repo.name != "proj" {
+		t.Fatalf("unexpected owner/repo: %s/%s", repo.owner, repo.name)
+	}
+	if len(hosts) != 1 || hosts[0] != "ghe.example.com" {
+		t.Fatalf("expected @me to be resolved on the repository's host, got %v", hosts)
 	}
 }
 
 func TestResolveRepository_AtMeError(t *testing.T) {
-	_, _, err := resolveRepository("@me/proj", &mockQuerier{err: errors.New("auth required")})
+	_, err := resolveRepository("@me/proj", "", "", querierFor(&mockQuerier{err: errors.New("auth required")}, nil))
 	if err == nil {
 		t.Fatal("expected error for @me resolution failure")
 	}
 }
+
+func TestResolveRepository_Host(t *testing.T) {
+	tests := []struct {
+		name       string
+		configRepo string
+		configHost string
+		flagHost   string
+		want       string
+	}{
+		{"host in defaults.repo", "ghe.example.com/octo/proj", "", "", "ghe.example.com/octo/proj"},
+		{"defaults.host", "octo/proj", "ghe.example.com", "", "ghe.example.com/octo/proj"},
+		{"repo host beats defaults.host", "other.example.com/octo/proj", "ghe.example.com", "", "other.example.com/octo/proj"},
+		{"flag beats everything", "other.example.com/octo/proj", "ghe.example.com", "flag.example.com", "flag.example.com/octo/proj"},
+		{"github.com is implicit", "github.com/octo/proj", "", "", "octo/proj"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := resolveRepository(tt.configRepo, tt.configHost, tt.flagHost, querierFor(&mockQuerier{}, nil))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.String() != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, repo.String())
+			}
+		})
+	}
+}
+
+func TestResolveRepository_GitContextHost(t *testing.T) {
+	t.Setenv("GH_REPO", "ghe.example.com/octo/proj")
+
+	repo, err := resolveRepository("", "ignored.example.com", "", querierFor(&mockQuerier{}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.host != "ghe.example.com" || repo.owner != "octo" || repo.name != "proj" {
+		t.Fatalf("unexpected repository: %+v", repo)
+	}
+}
+
+func TestParseRepoRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want repoRef
+		ok   bool
+	}{
+		{"octo/proj", repoRef{owner: "octo", name: "proj"}, true},
+		{"ghe.example.com/octo/proj", repoRef{host: "ghe.example.com", owner: "octo", name: "proj"}, true},
+		{"proj", repoRef{}, false},
+		{"octo//proj", repoRef{}, false},
+		{"a/b/c/d", repoRef{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRepoRef(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRepoRef(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHostClients_CachedPerHost(t *testing.T) {
+	clients := newHostClients(time.Second)
+	clients.replay = &data.Cassette{}
+
+	first, err := clients.querier("github.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := clients.querier("github.com"); again != first {
+		t.Error("expected the same querier for the same host")
+	}
+	if other, _ := clients.querier("ghe.example.com"); other == first {
+		t.Error("expected a separate querier for another host")
+	}
+}
+
+func TestNewSession_ReplayUsesCassetteRepository(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GH_REPO", "other/repo")
+
+	path := filepath.Join(t.TempDir(), "bug.json")
+	cassette := `{"version": 1, "repository": "ghe.example.com/octo/proj", "interactions": [
+		{"host": "ghe.example.com", "query": "query { rateLimit { limit cost remaining resetAt } viewer { login } }", "response": {"viewer": {"login": "recorded"}}},
+		{"host": "ghe.example.com", "query": "query IssueFeatures { rateLimit { limit cost remaining resetAt } __type(name: \"Issue\") { fields { name } } }", "response": {"__type": {"fields": [{"name": "title"}]}}}
+	]}`
+	if err := os.WriteFile(path, []byte(cassette), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	replayPath = path
+	t.Cleanup(func() { replayPath = "" })
+
+	s, err := newSession()
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	if got := s.repoName(); got != "ghe.example.com/octo/proj" {
+		t.Errorf("repo = %q", got)
+	}
+	if s.rest != nil {
+		t.Error("expected no REST client when replaying")
+	}
+	if s.features != (data.IssueFeatures{}) {
+		t.Errorf("features = %+v, want none on a server whose schema lacks them", s.features)
+	}
+
+	login, err := data.NewUserClient(s.querier).WhoAmI()
+	if err != nil || login != "recorded" {
+		t.Fatalf("WhoAmI = %q, %v", login, err)
+	}
+}
+
+func TestNewSession_RecordWritesRepository(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GH_REPO", "octo/proj")
+	t.Setenv("GH_TOKEN", "fake-token")
+	t.Setenv("GH_HOST", "github.com")
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	recordPath = path
+	t.Cleanup(func() { recordPath = "" })
+
+	if _, err := newSession(); err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	cassette, err := data.LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	if cassette.Repository != "github.com/octo/proj" {
+		t.Errorf("repository = %q", cassette.Repository)
+	}
+}
+
+func TestNewSession_Demo(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GH_REPO", "other/repo")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	demoMode = true
+	t.Cleanup(func() { demoMode = false })
+
+	s, err := newSession()
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	if got := s.repoName(); got != "gh-problemas/demo" {
+		t.Errorf("repo = %q", got)
+	}
+
+	issues := data.NewIssueClient(s.querier, s.owner, s.name)
+	created, err := issues.Create(data.IssueCreateInput{Title: "Made in the demo"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := issues.Get(created.Number)
+	if err != nil || got.Title != "Made in the demo" {
+		t.Fatalf("Get(%d) = %+v, %v", created.Number, got, err)
+	}
+
+	milestone, err := data.NewMilestoneClient(s.querier, s.rest, s.owner, s.name).Create("v3.0")
+	if err != nil || milestone.Title != "v3.0" {
+		t.Fatalf("Create milestone = %+v, %v", milestone, err)
+	}
+}
+
+func TestDebugEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true, "yes": false} {
+		t.Setenv("GH_PROBLEMAS_DEBUG", value)
+		if got := debugEnabled(); got != want {
+			t.Errorf("GH_PROBLEMAS_DEBUG=%q: debugEnabled() = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestNewSession_DebugLogsRequests(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	t.Setenv("GH_PROBLEMAS_DEBUG", "1")
+
+	demoMode = true
+	t.Cleanup(func() { demoMode = false })
+
+	s, err := newSession()
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	if _, err := data.NewIssueClient(s.querier, s.owner, s.name).Get(3); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	s.close()
+
+	path := filepath.Join(state, "gh-problemas", "debug.log")
+	if s.debug.path != path {
+		t.Errorf("log path = %q, want %q", s.debug.path, path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading debug log: %v", err)
+	}
+	log := string(b)
+	for _, want := range []string{`"msg":"session started"`, `"repository":"github.com/gh-problemas/demo"`, `"msg":"graphql request"`, `"operation":"GetIssue"`, `"cost":1`} {
+		if !strings.Contains(log, want) {
+			t.Errorf("debug log lacks %s:\n%s", want, log)
+		}
+	}
+}
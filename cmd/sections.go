@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/config"
+	"github.com/cboone/gh-problemas/internal/ui/views"
+)
+
+// dashboardSections converts the configured sections for the dashboard,
+// rejecting unknown states, layouts, columns, sorts, and groupings, and
+// boards without columns.
+func dashboardSections(sections []config.Section) ([]views.Section, error) {
+	out := make([]views.Section, 0, len(sections))
+	for i, s := range sections {
+		title := s.Title
+		if title == "" {
+			title = fmt.Sprintf("Section %d", i+1)
+		}
+		sec := views.Section{Title: title, Labels: s.Filters.Labels, ProjectFields: s.Filters.ProjectFields, Types: s.Filters.Types, Columns: s.Columns, Sort: s.Sort, GroupBy: s.GroupBy}
+
+		switch s.Filters.State {
+		case "", "open":
+			sec.States = []string{"OPEN"}
+		case "closed":
+			sec.States = []string{"CLOSED"}
+		case "all":
+		default:
+			return nil, fmt.Errorf("invalid state %q in section %q: expected open, closed, or all", s.Filters.State, title)
+		}
+
+		switch s.Layout {
+		case "", "list":
+		case "table":
+			sec.Table = true
+		case "board":
+			board, err := sectionBoard(s.Board, title)
+			if err != nil {
+				return nil, err
+			}
+			sec.Board = board
+		default:
+			return nil, fmt.Errorf("invalid layout %q in section %q: expected list, table, or board", s.Layout, title)
+		}
+
+		for _, c := range s.Columns {
+			if !views.ValidTableColumn(c) {
+				return nil, fmt.Errorf("unknown column %q in section %q; available columns: %s, or project:<field>", c, title, strings.Join(views.TableColumnKeys(), ", "))
+			}
+		}
+		if s.Sort != "" && !slices.Contains(views.SortKeys(), s.Sort) {
+			return nil, fmt.Errorf("unknown sort %q in section %q; available sorts: %s", s.Sort, title, strings.Join(views.SortKeys(), ", "))
+		}
+		if s.GroupBy != "" && !views.ValidGroupBy(s.GroupBy) {
+			return nil, fmt.Errorf("invalid group_by %q in section %q: expected label, label:<prefix>, milestone, assignee, author, state, or type", s.GroupBy, title)
+		}
+		out = append(out, sec)
+	}
+	return out, nil
+}
+
+// sectionBoard converts a board's settings, which name either labels or a
+// project.
+func sectionBoard(b config.SectionBoard, title string) (*views.Board, error) {
+	switch {
+	case len(b.Labels) > 0 && b.Project > 0:
+		return nil, fmt.Errorf("board section %q sets both labels and a project; choose one", title)
+	case len(b.Labels) > 0:
+		return &views.Board{Labels: b.Labels}, nil
+	case b.Project > 0:
+		field := b.Field
+		if field == "" {
+			field = "Status"
+		}
+		return &views.Board{Project: b.Project, Field: field}, nil
+	}
+	return nil, fmt.Errorf("board section %q needs board.labels or board.project", title)
+}
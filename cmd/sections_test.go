@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cboone/gh-problemas/internal/config"
+)
+
+func TestDashboardSections(t *testing.T) {
+	sections, err := dashboardSections([]config.Section{
+		{Title: "Bugs", Filters: config.SectionFilters{Labels: []string{"bug"}, ProjectFields: map[string]string{"priority": "P1"}, Types: []string{"Bug"}}, Layout: "table", Columns: []string{"number", "title", "reactions", "project:Iteration"}, Sort: "reactions", GroupBy: "label:area/*"},
+		{Filters: config.SectionFilters{State: "all"}},
+	})
+	if err != nil {
+		t.Fatalf("dashboardSections: %v", err)
+	}
+	bugs := sections[0]
+	if !bugs.Table || len(bugs.Columns) != 4 || bugs.ProjectFields["priority"] != "P1" || bugs.Types[0] != "Bug" || strings.Join(bugs.States, ",") != "OPEN" || bugs.Labels[0] != "bug" || bugs.Sort != "reactions" || bugs.GroupBy != "label:area/*" {
+		t.Errorf("bugs = %+v", bugs)
+	}
+	if all := sections[1]; all.Title != "Section 2" || all.States != nil || all.Table {
+		t.Errorf("all = %+v", all)
+	}
+}
+
+func TestDashboardSections_Boards(t *testing.T) {
+	sections, err := dashboardSections([]config.Section{
+		{Title: "Status", Layout: "board", Board: config.SectionBoard{Labels: []string{"status/todo", "status/done"}}},
+		{Title: "Roadmap", Layout: "board", Board: config.SectionBoard{Project: 3}},
+	})
+	if err != nil {
+		t.Fatalf("dashboardSections: %v", err)
+	}
+	if b := sections[0].Board; b == nil || len(b.Labels) != 2 || b.Project != 0 {
+		t.Errorf("label board = %+v", b)
+	}
+	if b := sections[1].Board; b == nil || b.Project != 3 || b.Field != "Status" {
+		t.Errorf("project board = %+v", b)
+	}
+}
+
+func TestDashboardSections_Invalid(t *testing.T) {
+	tests := []struct {
+		section config.Section
+		want    string
+	}{
+		{config.Section{Title: "X", Filters: config.SectionFilters{State: "merged"}}, `invalid state "merged" in section "X"`},
+		{config.Section{Title: "X", Layout: "grid"}, `invalid layout "grid"`},
+		{config.Section{Title: "X", Layout: "board"}, `board section "X" needs board.labels or board.project`},
+		{config.Section{Title: "X", Layout: "board", Board: config.SectionBoard{Labels: []string{"a"}, Project: 1}}, `sets both labels and a project`},
+		{config.Section{Title: "X", Layout: "table", Columns: []string{"title", "votes"}}, `unknown column "votes" in section "X"; available columns: number, title`},
+		{config.Section{Title: "X", Layout: "table", Columns: []string{"project:"}}, `unknown column "project:" in section "X"`},
+		{config.Section{Title: "X", Sort: "votes"}, `unknown sort "votes" in section "X"; available sorts: created,`},
+		{config.Section{Title: "X", GroupBy: "milestone:v1"}, `invalid group_by "milestone:v1" in section "X"`},
+	}
+	for _, tt := range tests {
+		_, err := dashboardSections([]config.Section{tt.section})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("err = %v, want it to contain %q", err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui/views"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+// viewOptions holds the flags for the view command.
+type viewOptions struct {
+	comments bool
+	noColor  bool
+	width    int
+	format   string
+}
+
+var viewOpts viewOptions
+
+var viewCmd = &cobra.Command{
+	Use:   "view <number>",
+	Short: "Print an issue and its comments",
+	Long: `Print an issue with its metadata, rendered body, and comments, as shown in
+the TUI detail view. Use --format json for machine-readable output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runView,
+}
+
+func init() {
+	f := viewCmd.Flags()
+	f.BoolVar(&viewOpts.comments, "comments", true, "Include comments")
+	f.BoolVar(&viewOpts.noColor, "no-color", false, "Disable colors and terminal styling")
+	f.IntVarP(&viewOpts.width, "width", "w", 0, "Wrap output to this width (default: terminal width)")
+	f.StringVar(&viewOpts.format, "format", "markdown", "Output format: {markdown|json}")
+	rootCmd.AddCommand(viewCmd)
+}
+
+func runView(cmd *cobra.Command, args []string) error {
+	number, err := parseIssueNumber(args[0])
+	if err != nil {
+		return err
+	}
+	if viewOpts.format != "markdown" && viewOpts.format != "json" {
+		return fmt.Errorf("invalid --format %q: expected markdown or json", viewOpts.format)
+	}
+	if viewOpts.width < 0 {
+		return fmt.Errorf("invalid --width %d: must not be negative", viewOpts.width)
+	}
+
+	s, err := newSession()
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	issueClient := s.issueClient()
+	issue, err := issueClient.Get(number)
+	if err != nil {
+		return fmt.Errorf("loading issue #%d: %w", number, err)
+	}
+
+	var comments []data.Comment
+	if viewOpts.comments {
+		commentClient := data.NewCommentClient(s.querier, s.owner, s.name)
+		comments, err = fetchAllComments(commentClient, number)
+		if err != nil {
+			return fmt.Errorf("loading comments for #%d: %w", number, err)
+		}
+	}
+
+	return printIssue(cmd.OutOrStdout(), issue, comments, s.owner+"/"+s.name, viewOpts, s.cfg.Defaults.DateFormat, term.FromEnv())
+}
+
+// parseIssueNumber accepts "123" or "#123".
+func parseIssueNumber(arg string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid issue number %q", arg)
+	}
+	return n, nil
+}
+
+// commentLister is the subset of CommentClient used by fetchAllComments.
+type commentLister interface {
+	List(issueNumber, first int, after string) (data.CommentListResult, error)
+}
+
+// fetchAllComments pages through every comment on an issue.
+func fetchAllComments(client commentLister, number int) ([]data.Comment, error) {
+	paginator := data.NewPaginator(100)
+	var comments []data.Comment
+
+	for req := paginator.NextPageRequest(); req != nil; req = paginator.NextPageRequest() {
+		result, err := client.List(number, req.First, req.After)
+		if err != nil {
+			return nil, err
+		}
+		paginator.Update(result.PageInfo, len(result.Comments))
+		comments = append(comments, result.Comments...)
+	}
+
+	return comments, nil
+}
+
+func printIssue(w io.Writer, issue data.Issue, comments []data.Comment, repo string, o viewOptions, dateFormat string, t term.Term) error {
+	if o.format == "json" {
+		record := issueRecord(issue)
+		if o.comments {
+			records := make([]map[string]interface{}, len(comments))
+			for i, c := range comments {
+				records[i] = commentRecord(c)
+			}
+			record["comments"] = records
+		}
+		return writeJSON(w, record, "")
+	}
+
+	width := o.width
+	if width == 0 {
+		width = 80
+		if tw, _, err := t.Size(); err == nil && tw > 0 {
+			width = tw
+		}
+	}
+
+	noColor := o.noColor || !t.IsColorEnabled()
+	if noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	_, err := fmt.Fprintln(w, views.RenderIssue(issue, comments, views.RenderOptions{
+		Width:      width,
+		DateFormat: dateFormat,
+		NoColor:    noColor,
+		Repo:       repo,
+	}))
+	return err
+}
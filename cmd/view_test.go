@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// pagedCommentLister serves comments two at a time.
+type pagedCommentLister struct {
+	comments []data.Comment
+	calls    int
+}
+
+func (p *pagedCommentLister) List(_, first int, after string) (data.CommentListResult, error) {
+	p.calls++
+	start := 0
+	if after != "" {
+		start = int(after[0] - '0')
+	}
+	if first > 2 {
+		first = 2
+	}
+	end := start + first
+	if end > len(p.comments) {
+		end = len(p.comments)
+	}
+	return data.CommentListResult{
+		Comments: p.comments[start:end],
+		PageInfo: data.PageInfo{HasNextPage: end < len(p.comments), EndCursor: string(rune('0' + end))},
+	}, nil
+}
+
+func TestParseIssueNumber(t *testing.T) {
+	for _, arg := range []string{"42", "#42"} {
+		n, err := parseIssueNumber(arg)
+		if err != nil || n != 42 {
+			t.Errorf("parseIssueNumber(%q) = %d, %v", arg, n, err)
+		}
+	}
+	for _, arg := range []string{"", "abc", "0", "-3"} {
+		if _, err := parseIssueNumber(arg); err == nil {
+			t.Errorf("expected error for %q", arg)
+		}
+	}
+}
+
+func TestFetchAllComments_FollowsCursors(t *testing.T) {
+	lister := &pagedCommentLister{comments: []data.Comment{
+		{Author: "a"}, {Author: "b"}, {Author: "c"}, {Author: "d"}, {Author: "e"},
+	}}
+
+	comments, err := fetchAllComments(lister, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 5 {
+		t.Fatalf("expected 5 comments, got %d", len(comments))
+	}
+	if lister.calls != 3 {
+		t.Errorf("expected 3 requests, got %d", lister.calls)
+	}
+}
+
+func testIssue() data.Issue {
+	return data.Issue{
+		Number:    12,
+		Title:     "Crash on start",
+		State:     "OPEN",
+		Author:    "alice",
+		CreatedAt: time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, time.March, 2, 10, 0, 0, 0, time.UTC),
+		Labels:    []data.Label{{Name: "bug", Color: "d73a4a"}},
+		Body:      "It **crashes**.",
+	}
+}
+
+func TestPrintIssue_NoColorMarkdown(t *testing.T) {
+	comments := []data.Comment{{Author: "bob", Body: "Same here", CreatedAt: time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)}}
+	opts := viewOptions{comments: true, noColor: true, width: 60, format: "markdown"}
+
+	var buf bytes.Buffer
+	if err := printIssue(&buf, testIssue(), comments, "owner/repo", opts, "2006-01-02", term.FromEnv()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("expected no ANSI escapes, got %q", out)
+	}
+	for _, want := range []string{"Crash on start #12", "Created: 2025-03-01", "[bug]", "crashes", "Comments (1)", "Same here"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintIssue_JSON(t *testing.T) {
+	comments := []data.Comment{{Author: "bob", Body: "Same here"}}
+	opts := viewOptions{comments: true, format: "json"}
+
+	var buf bytes.Buffer
+	if err := printIssue(&buf, testIssue(), comments, "owner/repo", opts, "relative", term.FromEnv()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got struct {
+		Number   int    `json:"number"`
+		Body     string `json:"body"`
+		Comments []struct {
+			Author string `json:"author"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Number != 12 || got.Body != "It **crashes**." {
+		t.Errorf("unexpected issue fields: %+v", got)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].Author != "bob" {
+		t.Errorf("unexpected comments: %+v", got.Comments)
+	}
+}
@@ -10,17 +10,60 @@ import (
 
 // Config holds the application configuration.
 type Config struct {
-	Version  int      `mapstructure:"version"`
-	Defaults Defaults `mapstructure:"defaults"`
-	Theme    string   `mapstructure:"theme"`
+	Version  int       `mapstructure:"version"`
+	Defaults Defaults  `mapstructure:"defaults"`
+	Theme    string    `mapstructure:"theme"`
+	Sections []Section `mapstructure:"sections"`
 }
 
 // Defaults holds default configuration values.
 type Defaults struct {
 	Repo            string `mapstructure:"repo"`
+	Host            string `mapstructure:"host"`
 	RefreshInterval int    `mapstructure:"refresh_interval"`
 	PageSize        int    `mapstructure:"page_size"`
 	DateFormat      string `mapstructure:"date_format"`
+	RequestTimeout  int    `mapstructure:"request_timeout"` // seconds; 0 disables
+	// PriorityLabels rank issues for the priority sort, most urgent first.
+	PriorityLabels []string `mapstructure:"priority_labels"`
+}
+
+// Section is a tab of the dashboard: a filtered list of issues and how to
+// display it.
+type Section struct {
+	Title   string         `mapstructure:"title"`
+	Filters SectionFilters `mapstructure:"filters"`
+	Layout  string         `mapstructure:"layout"`   // "list", "table", or "board"
+	Columns []string       `mapstructure:"columns"`  // table columns, in order
+	Sort    string         `mapstructure:"sort"`     // such as "updated"; see views.SortKeys
+	GroupBy string         `mapstructure:"group_by"` // such as "milestone" or "label:area/*"
+	Board   SectionBoard   `mapstructure:"board"`
+}
+
+// SectionBoard sets the columns of a board section: one per label, or one
+// per option of a single-select field of a project owned by the repository
+// owner.
+type SectionBoard struct {
+	Labels  []string `mapstructure:"labels"`
+	Project int      `mapstructure:"project"`
+	Field   string   `mapstructure:"field"` // defaults to Status
+}
+
+// SectionFilters selects the issues shown in a section.
+type SectionFilters struct {
+	State  string   `mapstructure:"state"` // "open", "closed", or "all"
+	Labels []string `mapstructure:"labels"`
+	// ProjectFields maps project field names to the value issues must
+	// have, such as {Priority: P1}.
+	ProjectFields map[string]string `mapstructure:"project_fields"`
+	// Types keeps the issues of any of these issue types, such as Bug.
+	Types []string `mapstructure:"types"`
+}
+
+// DefaultSections is the dashboard used when the config defines no
+// sections.
+func DefaultSections() []Section {
+	return []Section{{Title: "Open Issues", Filters: SectionFilters{State: "open"}, Layout: "list"}}
 }
 
 // Load reads configuration from the config file with sensible defaults.
@@ -30,9 +73,12 @@ func Load() (*Config, error) {
 	// Set defaults
 	v.SetDefault("version", 1)
 	v.SetDefault("defaults.repo", "")
+	v.SetDefault("defaults.host", "")
 	v.SetDefault("defaults.refresh_interval", 300)
 	v.SetDefault("defaults.page_size", 50)
 	v.SetDefault("defaults.date_format", "relative")
+	v.SetDefault("defaults.request_timeout", 30)
+	v.SetDefault("defaults.priority_labels", []string{"P0", "P1", "P2", "P3"})
 	v.SetDefault("theme", "dark")
 
 	// Config path
@@ -51,6 +97,9 @@ func Load() (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
+	if len(cfg.Sections) == 0 {
+		cfg.Sections = DefaultSections()
+	}
 
 	return &cfg, nil
 }
@@ -66,3 +115,18 @@ func configDirectory() string {
 	}
 	return filepath.Join(home, ".config", "gh-problemas")
 }
+
+// StateDir returns the directory for files the program writes for itself,
+// such as logs: $XDG_STATE_HOME/gh-problemas, defaulting to
+// ~/.local/state/gh-problemas.
+func StateDir() string {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gh-problemas")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".", ".local", "state", "gh-problemas")
+	}
+	return filepath.Join(home, ".local", "state", "gh-problemas")
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -28,6 +29,12 @@ func TestLoad_NoConfigFile(t *testing.T) {
 	if cfg.Defaults.DateFormat != "relative" {
 		t.Errorf("expected date_format relative, got %s", cfg.Defaults.DateFormat)
 	}
+	if cfg.Defaults.RequestTimeout != 30 {
+		t.Errorf("expected request_timeout 30, got %d", cfg.Defaults.RequestTimeout)
+	}
+	if got := strings.Join(cfg.Defaults.PriorityLabels, ","); got != "P0,P1,P2,P3" {
+		t.Errorf("expected priority_labels P0,P1,P2,P3, got %s", got)
+	}
 	if cfg.Theme != "dark" {
 		t.Errorf("expected theme dark, got %s", cfg.Theme)
 	}
@@ -112,3 +119,66 @@ func TestLoad_XDGConfigHome(t *testing.T) {
 		t.Errorf("expected theme light, got %s", cfg.Theme)
 	}
 }
+
+func TestLoad_Sections(t *testing.T) {
+	tmp := t.TempDir()
+	configDir := filepath.Join(tmp, "gh-problemas")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+
+	configContent := `sections:
+  - title: Bugs
+    filters:
+      labels: [bug]
+    layout: table
+    columns: [number, title, age]
+  - title: Closed
+    filters:
+      state: closed
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(cfg.Sections))
+	}
+	bugs := cfg.Sections[0]
+	if bugs.Title != "Bugs" || bugs.Layout != "table" || len(bugs.Columns) != 3 || len(bugs.Filters.Labels) != 1 {
+		t.Errorf("unexpected first section: %+v", bugs)
+	}
+	if cfg.Sections[1].Filters.State != "closed" {
+		t.Errorf("expected second section state closed, got %q", cfg.Sections[1].Filters.State)
+	}
+}
+
+func TestLoad_DefaultSections(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Sections) != 1 || cfg.Sections[0].Title != "Open Issues" {
+		t.Errorf("expected the default Open Issues section, got %+v", cfg.Sections)
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	if got := StateDir(); got != filepath.Join("/tmp/state", "gh-problemas") {
+		t.Errorf("StateDir() = %q", got)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/mona")
+	if got := StateDir(); got != filepath.Join("/home/mona", ".local", "state", "gh-problemas") {
+		t.Errorf("StateDir() = %q", got)
+	}
+}
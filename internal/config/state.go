@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// State is what the program remembers between runs, such as the last sort
+// used in each dashboard section. It is kept in state.json in the state
+// directory.
+type State struct {
+	mu    sync.Mutex
+	path  string
+	Sorts map[string]string `json:"sorts"` // by section title
+}
+
+// LoadState reads state.json from dir. A missing file gives an empty
+// state. So does a file that cannot be read or parsed, along with the
+// error, so that the caller can warn and carry on; the file is replaced
+// when the state is next saved.
+func LoadState(dir string) (*State, error) {
+	path := filepath.Join(dir, "state.json")
+	s := &State{path: path, Sorts: map[string]string{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("reading state: %w", err)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return &State{path: path, Sorts: map[string]string{}}, fmt.Errorf("parsing state %s: %w", path, err)
+	}
+	if s.Sorts == nil {
+		s.Sorts = map[string]string{}
+	}
+	return s, nil
+}
+
+// Sort returns the last sort used in the section with the given title, or
+// "" if there is none.
+func (s *State) Sort(section string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Sorts[section]
+}
+
+// SetSort remembers the sort used in a section and saves the state.
+func (s *State) SetSort(section, sort string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Sorts[section] = sort
+	return s.save()
+}
+
+func (s *State) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write and rename so that a crash never leaves a truncated file.
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestState_RemembersSortsAcrossLoads(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gh-problemas")
+
+	s, err := LoadState(dir)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if got := s.Sort("Bugs"); got != "" {
+		t.Errorf("expected no sort in a new state, got %q", got)
+	}
+	if err := s.SetSort("Bugs", "reactions"); err != nil {
+		t.Fatalf("SetSort: %v", err)
+	}
+
+	reloaded, err := LoadState(dir)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if got := reloaded.Sort("Bugs"); got != "reactions" {
+		t.Errorf("expected reactions after reload, got %q", got)
+	}
+}
+
+func TestLoadState_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "state.json"), []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	s, err := LoadState(dir)
+	if err == nil {
+		t.Fatal("expected error for corrupt state, got nil")
+	}
+	if s == nil || s.Sort("Bugs") != "" {
+		t.Fatal("expected an empty state to continue with")
+	}
+	if err := s.SetSort("Bugs", "reactions"); err != nil {
+		t.Fatalf("SetSort: %v", err)
+	}
+	if reloaded, err := LoadState(dir); err != nil || reloaded.Sort("Bugs") != "reactions" {
+		t.Fatalf("expected the corrupt state to be replaced, got %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// cassetteVersion is the format version written to new cassettes.
+const cassetteVersion = 1
+
+// Cassette is a recording of GraphQL traffic. A cassette created with
+// NewCassette records requests made through its Recorder queriers, which
+// are written out by Save; one opened with LoadCassette answers requests from
+// its Player queriers, so a session can be replayed without network access
+// or credentials.
+type Cassette struct {
+	Version int `json:"version"`
+	// Repository is the repository the recording was made against, written
+	// as HOST/OWNER/REPO.
+	Repository   string        `json:"repository,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+
+	path   string
+	mu     sync.Mutex
+	served map[string]int
+}
+
+// Interaction is one recorded request and its outcome.
+type Interaction struct {
+	Host      string                 `json:"host"`
+	Operation string                 `json:"operation,omitempty"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	Response  json.RawMessage        `json:"response,omitempty"`
+	Error     *RecordedError         `json:"error,omitempty"`
+}
+
+// RecordedError is a recorded failure: an HTTP error when Status is set,
+// GraphQL errors when Errors is set, and any other error otherwise. An HTTP
+// error keeps its headers, such as Retry-After, which decide how it is
+// retried.
+type RecordedError struct {
+	Status  int                    `json:"status,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Headers http.Header            `json:"headers,omitempty"`
+	Errors  []RecordedGraphQLError `json:"errors,omitempty"`
+}
+
+// RecordedGraphQLError is one entry of a GraphQL error response.
+type RecordedGraphQLError struct {
+	Type    string        `json:"type,omitempty"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// NewCassette creates an empty cassette that is saved to path.
+func NewCassette(path string) *Cassette {
+	return &Cassette{Version: cassetteVersion, path: path}
+}
+
+// LoadCassette reads a cassette from path.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+	c := &Cassette{path: path}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d", path, c.Version)
+	}
+	return c, nil
+}
+
+// SetRepository records the repository the session works on.
+func (c *Cassette) SetRepository(repo string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Repository = repo
+}
+
+// Save writes the cassette to its path, replacing any file there.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cassette-*")
+	if err != nil {
+		return fmt.Errorf("saving cassette: %w", err)
+	}
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("saving cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("saving cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("saving cassette: %w", err)
+	}
+	return nil
+}
+
+// Recorder wraps q so that every request it sends to host is added to the
+// cassette, with tokens scrubbed.
+func (c *Cassette) Recorder(host string, q ContextQuerier) ContextQuerier {
+	return &recordingQuerier{cassette: c, host: host, querier: q}
+}
+
+// Player returns a querier that answers requests for host from the
+// cassette. Identical requests are answered in the order they were
+// recorded; once those run out, the last answer is repeated.
+func (c *Cassette) Player(host string) ContextQuerier {
+	return &replayQuerier{cassette: c, host: host}
+}
+
+type recordingQuerier struct {
+	cassette *Cassette
+	host     string
+	querier  ContextQuerier
+}
+
+func (r *recordingQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return r.DoWithContext(context.Background(), query, variables, response)
+}
+
+func (r *recordingQuerier) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	var raw json.RawMessage
+	err := r.querier.DoWithContext(ctx, query, variables, &raw)
+	if len(raw) > 0 {
+		if uerr := json.Unmarshal(raw, response); uerr != nil && err == nil {
+			err = uerr
+		}
+	}
+	// A cancelled request says nothing about the server.
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	interaction := Interaction{
+		Host:      r.host,
+		Operation: operationName(query),
+		Query:     query,
+		Variables: scrubVariables(variables),
+		Response:  scrubJSON(raw),
+		Error:     recordError(err),
+	}
+
+	c := r.cassette
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, interaction)
+	c.mu.Unlock()
+	return err
+}
+
+type replayQuerier struct {
+	cassette *Cassette
+	host     string
+}
+
+func (r *replayQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return r.DoWithContext(context.Background(), query, variables, response)
+}
+
+func (r *replayQuerier) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	key := interactionKey(r.host, query, scrubVariables(variables))
+
+	c := r.cassette
+	c.mu.Lock()
+	var matches []*Interaction
+	for i := range c.Interactions {
+		in := &c.Interactions[i]
+		if interactionKey(in.Host, in.Query, in.Variables) == key {
+			matches = append(matches, in)
+		}
+	}
+	if c.served == nil {
+		c.served = map[string]int{}
+	}
+	n := c.served[key]
+	c.served[key]++
+	c.mu.Unlock()
+
+	if len(matches) == 0 {
+		vars, _ := json.Marshal(variables)
+		return fmt.Errorf("cassette %s has no recording of %s with variables %s", filepath.Base(c.path), operationLabel(query), vars)
+	}
+	in := matches[min(n, len(matches)-1)]
+
+	if len(in.Response) > 0 && string(in.Response) != "null" {
+		if err := json.Unmarshal(in.Response, response); err != nil {
+			return err
+		}
+	}
+	return in.Error.err()
+}
+
+// recordError captures err in a form that can be replayed.
+func recordError(err error) *RecordedError {
+	if err == nil {
+		return nil
+	}
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		rec := &RecordedError{Status: httpErr.StatusCode, Message: scrub(httpErr.Message)}
+		if len(httpErr.Headers) > 0 {
+			rec.Headers = http.Header{}
+			for name, values := range httpErr.Headers {
+				for _, v := range values {
+					rec.Headers.Add(name, scrub(v))
+				}
+			}
+		}
+		return rec
+	}
+	var gqlErr *api.GraphQLError
+	if errors.As(err, &gqlErr) {
+		rec := &RecordedError{}
+		for _, item := range gqlErr.Errors {
+			rec.Errors = append(rec.Errors, RecordedGraphQLError{Type: item.Type, Message: scrub(item.Message), Path: item.Path})
+		}
+		return rec
+	}
+	return &RecordedError{Message: scrub(err.Error())}
+}
+
+// err rebuilds the recorded error with the types the go-gh client returns.
+func (e *RecordedError) err() error {
+	switch {
+	case e == nil:
+		return nil
+	case e.Status != 0:
+		return &api.HTTPError{StatusCode: e.Status, Message: e.Message, Headers: e.Headers}
+	case len(e.Errors) > 0:
+		gqlErr := &api.GraphQLError{}
+		for _, item := range e.Errors {
+			gqlErr.Errors = append(gqlErr.Errors, api.GraphQLErrorItem{Type: item.Type, Message: item.Message, Path: item.Path})
+		}
+		return gqlErr
+	default:
+		return errors.New(e.Message)
+	}
+}
+
+// interactionKey identifies a request regardless of query formatting and
+// variable order.
+func interactionKey(host, query string, variables map[string]interface{}) string {
+	vars, _ := json.Marshal(variables)
+	return host + "\n" + strings.Join(strings.Fields(query), " ") + "\n" + string(vars)
+}
+
+var operationPattern = regexp.MustCompile(`^\s*(query|mutation)\s+(\w+)`)
+
+// operationName returns the name of a GraphQL operation, or "" if it is
+// anonymous.
+func operationName(query string) string {
+	if m := operationPattern.FindStringSubmatch(query); m != nil {
+		return m[2]
+	}
+	return ""
+}
+
+func operationLabel(query string) string {
+	if name := operationName(query); name != "" {
+		return name
+	}
+	return "anonymous query"
+}
+
+// tokenPattern matches GitHub tokens: classic and fine-grained personal
+// access tokens, and OAuth, app, and refresh tokens.
+var tokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+const redacted = "[REDACTED]"
+
+func scrub(s string) string {
+	return tokenPattern.ReplaceAllString(s, redacted)
+}
+
+func scrubJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.RawMessage(tokenPattern.ReplaceAll(raw, []byte(redacted)))
+}
+
+// scrubVariables returns a copy of variables, as decoded from JSON, with
+// tokens scrubbed from every string.
+func scrubVariables(variables map[string]interface{}) map[string]interface{} {
+	if len(variables) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(variables)
+	if err != nil {
+		return nil
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(tokenPattern.ReplaceAll(b, []byte(redacted)), &out); err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	q := &scriptedQuerier{replies: []scriptedReply{
+		{response: map[string]interface{}{"repository": map[string]interface{}{"issues": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": false},
+			"nodes":    []interface{}{map[string]interface{}{"number": 7, "title": "Recorded", "author": map[string]interface{}{"login": "alice"}}},
+		}}}},
+		{response: map[string]interface{}{"repository": map[string]interface{}{"issue": nil}}, err: &api.GraphQLError{Errors: []api.GraphQLErrorItem{
+			{Type: "NOT_FOUND", Message: "Could not resolve to an Issue", Path: []interface{}{"repository", "issue"}},
+		}}},
+	}}
+
+	rec := NewCassette(path)
+	rec.SetRepository("github.com/octo/hello")
+	client := NewIssueClient(rec.Recorder("github.com", q), "octo", "hello")
+	if _, err := client.List(IssueListOptions{First: 10}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := client.Get(99); err == nil {
+		t.Fatal("expected Get to fail")
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	if cassette.Repository != "github.com/octo/hello" || len(cassette.Interactions) != 2 {
+		t.Fatalf("cassette = %+v", cassette)
+	}
+	if cassette.Interactions[0].Operation != "ListIssues" {
+		t.Errorf("operation = %q", cassette.Interactions[0].Operation)
+	}
+
+	replay := NewIssueClient(cassette.Player("github.com"), "octo", "hello")
+	result, err := replay.List(IssueListOptions{First: 10})
+	if err != nil {
+		t.Fatalf("replayed List: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Title != "Recorded" {
+		t.Fatalf("replayed issues = %+v", result.Issues)
+	}
+
+	_, err = replay.Get(99)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != ErrNotFound || apiErr.Resource != "issue" {
+		t.Fatalf("replayed Get err = %v, want issue not found", err)
+	}
+}
+
+func TestCassette_ReplaysRepeatedRequestsInOrder(t *testing.T) {
+	c := &Cassette{Version: cassetteVersion, path: "test.json", Interactions: []Interaction{
+		{Host: "github.com", Query: "query { viewer { login } }", Error: &RecordedError{Status: 502, Message: "Bad Gateway"}},
+		{Host: "github.com", Query: "query {\n  viewer { login }\n}", Response: []byte(`{"viewer":{"login":"octocat"}}`)},
+	}}
+	users := NewUserClient(c.Player("github.com"))
+
+	_, err := users.WhoAmI()
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 502 {
+		t.Fatalf("first WhoAmI err = %v, want HTTP 502", err)
+	}
+	for i := 0; i < 2; i++ {
+		login, err := users.WhoAmI()
+		if err != nil || login != "octocat" {
+			t.Fatalf("WhoAmI #%d = %q, %v", i+2, login, err)
+		}
+	}
+}
+
+func TestCassette_UnrecordedRequest(t *testing.T) {
+	c := &Cassette{Version: cassetteVersion, path: "bug-123.json"}
+	_, err := NewIssueClient(c.Player("github.com"), "octo", "hello").Get(1)
+	if err == nil || !strings.Contains(err.Error(), "bug-123.json has no recording of GetIssue") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestCassette_ScrubsTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	token := "ghp_" + strings.Repeat("a1B2", 9)
+	pat := "github_pat_" + strings.Repeat("x9", 20)
+	q := &scriptedQuerier{replies: []scriptedReply{
+		{response: map[string]interface{}{"viewer": map[string]interface{}{"login": "octocat", "bio": "leaked " + token}},
+			err: &api.HTTPError{StatusCode: 500, Message: "token " + pat + " rejected"}},
+	}}
+
+	rec := NewCassette(path)
+	vars := map[string]interface{}{"body": "my token is " + token}
+	var resp map[string]interface{}
+	_ = rec.Recorder("github.com", q).Do("mutation AddComment($body: String!) { x }", vars, &resp)
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	if strings.Contains(string(b), token) || strings.Contains(string(b), pat) {
+		t.Fatalf("cassette contains a token:\n%s", b)
+	}
+	if strings.Count(string(b), "[REDACTED]") != 3 {
+		t.Errorf("expected three redactions:\n%s", b)
+	}
+}
+
+func TestLoadCassette_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadCassette(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version": 99, "interactions": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCassette(future); err == nil || !strings.Contains(err.Error(), "unsupported version 99") {
+		t.Errorf("err = %v, want unsupported version", err)
+	}
+}
+
+func TestCassette_ReplaysErrorHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	headers := http.Header{}
+	headers.Set("Retry-After", "30")
+	q := &scriptedQuerier{replies: []scriptedReply{
+		{err: &api.HTTPError{StatusCode: http.StatusForbidden, Message: "You have exceeded a secondary rate limit", Headers: headers}},
+	}}
+
+	rec := NewCassette(path)
+	var resp map[string]interface{}
+	_ = rec.Recorder("github.com", q).Do("query { viewer { login } }", nil, &resp)
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	err = cassette.Player("github.com").Do("query { viewer { login } }", nil, &resp)
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Headers.Get("Retry-After") != "30" {
+		t.Fatalf("replayed err = %#v, want 403 with Retry-After", err)
+	}
+}
@@ -1,16 +1,38 @@
 package data
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Comment represents a GitHub issue comment.
 type Comment struct {
-	Author    string
-	Body      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Reactions int
+	ID             string // node ID
+	URL            string
+	Author         string
+	Body           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Reactions      int
+	ReactionGroups []ReactionGroup
+	// ViewerDidAuthor is true for the viewer's own comments.
+	ViewerDidAuthor bool
+	// ViewerCanUpdate, ViewerCanDelete, and ViewerCanMinimize say what the
+	// viewer may do to the comment: authors can edit and delete their own,
+	// and maintainers can also minimize others'.
+	ViewerCanUpdate   bool
+	ViewerCanDelete   bool
+	ViewerCanMinimize bool
+	// MinimizedReason is why the comment was hidden, such as "spam" or
+	// "off-topic", when IsMinimized is set.
+	IsMinimized     bool
+	MinimizedReason string
 }
 
+// MinimizeReasons are the classifiers a comment can be minimized with, in
+// the order GitHub offers them.
+var MinimizeReasons = []string{"SPAM", "ABUSE", "OFF_TOPIC", "OUTDATED", "DUPLICATE", "RESOLVED"}
+
 // CommentListResult is the result of listing comments.
 type CommentListResult struct {
 	Comments []Comment
@@ -31,6 +53,11 @@ func NewCommentClient(q Querier, owner, repo string) *CommentClient {
 
 // List fetches comments for an issue.
 func (c *CommentClient) List(issueNumber, first int, after string) (CommentListResult, error) {
+	return c.ListContext(context.Background(), issueNumber, first, after)
+}
+
+// ListContext is like List but can be cancelled through ctx.
+func (c *CommentClient) ListContext(ctx context.Context, issueNumber, first int, after string) (CommentListResult, error) {
 	if first == 0 {
 		first = 25
 	}
@@ -46,30 +73,162 @@ func (c *CommentClient) List(issueNumber, first int, after string) (CommentListR
 	}
 
 	var resp listCommentsResponse
-	if err := c.querier.Do(listCommentsQuery, vars, &resp); err != nil {
+	err := doContext(ctx, c.querier, listCommentsQuery, vars, &resp)
+	if err != nil && !IsPartialData(err) {
 		return CommentListResult{}, err
 	}
 
-	return resp.toResult(), nil
+	return resp.toResult(), err
+}
+
+// Add posts a comment with the given markdown body on the issue with node
+// ID subjectID.
+func (c *CommentClient) Add(subjectID, body string) (Comment, error) {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"subjectId": subjectID, "body": body},
+	}
+	var resp struct {
+		AddComment struct {
+			CommentEdge struct {
+				Node commentNode `json:"node"`
+			} `json:"commentEdge"`
+		} `json:"addComment"`
+	}
+	if err := do(c.querier, addCommentMutation, vars, &resp); err != nil {
+		return Comment{}, err
+	}
+	return resp.AddComment.CommentEdge.Node.toComment(), nil
+}
+
+// Update replaces the body of the comment with node ID id.
+func (c *CommentClient) Update(id, body string) (Comment, error) {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"id": id, "body": body},
+	}
+	var resp struct {
+		UpdateIssueComment struct {
+			IssueComment commentNode `json:"issueComment"`
+		} `json:"updateIssueComment"`
+	}
+	if err := do(c.querier, updateCommentMutation, vars, &resp); err != nil {
+		return Comment{}, err
+	}
+	return resp.UpdateIssueComment.IssueComment.toComment(), nil
+}
+
+// Delete deletes the comment with node ID id.
+func (c *CommentClient) Delete(id string) error {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"id": id},
+	}
+	var resp struct{}
+	return do(c.querier, deleteCommentMutation, vars, &resp)
+}
+
+// Minimize hides the comment with node ID id, for the reason given by
+// classifier, one of MinimizeReasons.
+func (c *CommentClient) Minimize(id, classifier string) (Comment, error) {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"subjectId": id, "classifier": classifier},
+	}
+	var resp struct {
+		MinimizeComment struct {
+			MinimizedComment commentNode `json:"minimizedComment"`
+		} `json:"minimizeComment"`
+	}
+	if err := do(c.querier, minimizeCommentMutation, vars, &resp); err != nil {
+		return Comment{}, err
+	}
+	return resp.MinimizeComment.MinimizedComment.toComment(), nil
 }
 
+// Unminimize shows the comment with node ID id again.
+func (c *CommentClient) Unminimize(id string) (Comment, error) {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"subjectId": id},
+	}
+	var resp struct {
+		UnminimizeComment struct {
+			UnminimizedComment commentNode `json:"unminimizedComment"`
+		} `json:"unminimizeComment"`
+	}
+	if err := do(c.querier, unminimizeCommentMutation, vars, &resp); err != nil {
+		return Comment{}, err
+	}
+	return resp.UnminimizeComment.UnminimizedComment.toComment(), nil
+}
+
+// commentFields is the selection of every comment the client returns.
+const commentFields = `id
+          url
+          author { login }
+          body
+          createdAt
+          updatedAt
+          viewerDidAuthor
+          viewerCanUpdate
+          viewerCanDelete
+          viewerCanMinimize
+          isMinimized
+          minimizedReason
+          reactions { totalCount }
+          reactionGroups { content viewerHasReacted reactors { totalCount } }`
+
 const listCommentsQuery = `query ListComments($owner: String!, $name: String!, $number: Int!, $first: Int!, $after: String) {
   repository(owner: $owner, name: $name) {
     issue(number: $number) {
       comments(first: $first, after: $after) {
         pageInfo { hasNextPage endCursor }
         nodes {
-          author { login }
-          body
-          createdAt
-          updatedAt
-          reactions { totalCount }
+          ` + commentFields + `
         }
       }
     }
   }
 }`
 
+const addCommentMutation = `mutation AddComment($input: AddCommentInput!) {
+  addComment(input: $input) {
+    commentEdge {
+      node {
+          ` + commentFields + `
+      }
+    }
+  }
+}`
+
+const updateCommentMutation = `mutation UpdateIssueComment($input: UpdateIssueCommentInput!) {
+  updateIssueComment(input: $input) {
+    issueComment {
+          ` + commentFields + `
+    }
+  }
+}`
+
+const deleteCommentMutation = `mutation DeleteIssueComment($input: DeleteIssueCommentInput!) {
+  deleteIssueComment(input: $input) { clientMutationId }
+}`
+
+const minimizeCommentMutation = `mutation MinimizeComment($input: MinimizeCommentInput!) {
+  minimizeComment(input: $input) {
+    minimizedComment {
+      ... on IssueComment {
+          ` + commentFields + `
+      }
+    }
+  }
+}`
+
+const unminimizeCommentMutation = `mutation UnminimizeComment($input: UnminimizeCommentInput!) {
+  unminimizeComment(input: $input) {
+    unminimizedComment {
+      ... on IssueComment {
+          ` + commentFields + `
+      }
+    }
+  }
+}`
+
 type listCommentsResponse struct {
 	Repository struct {
 		Issue struct {
@@ -84,17 +243,7 @@ type listCommentsResponse struct {
 func (r *listCommentsResponse) toResult() CommentListResult {
 	comments := make([]Comment, len(r.Repository.Issue.Comments.Nodes))
 	for i, n := range r.Repository.Issue.Comments.Nodes {
-		author := n.Author.Login
-		if author == "" {
-			author = "[deleted]"
-		}
-		comments[i] = Comment{
-			Author:    author,
-			Body:      n.Body,
-			CreatedAt: n.CreatedAt,
-			UpdatedAt: n.UpdatedAt,
-			Reactions: n.Reactions.TotalCount,
-		}
+		comments[i] = n.toComment()
 	}
 	pi := r.Repository.Issue.Comments.PageInfo
 	return CommentListResult{
@@ -104,13 +253,45 @@ func (r *listCommentsResponse) toResult() CommentListResult {
 }
 
 type commentNode struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
 	Author struct {
 		Login string `json:"login"`
 	} `json:"author"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	Reactions struct {
+	Body              string    `json:"body"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+	ViewerDidAuthor   bool      `json:"viewerDidAuthor"`
+	ViewerCanUpdate   bool      `json:"viewerCanUpdate"`
+	ViewerCanDelete   bool      `json:"viewerCanDelete"`
+	ViewerCanMinimize bool      `json:"viewerCanMinimize"`
+	IsMinimized       bool      `json:"isMinimized"`
+	MinimizedReason   string    `json:"minimizedReason"`
+	Reactions         struct {
 		TotalCount int `json:"totalCount"`
 	} `json:"reactions"`
+	reactableNode
+}
+
+func (n *commentNode) toComment() Comment {
+	author := n.Author.Login
+	if author == "" {
+		author = "[deleted]"
+	}
+	return Comment{
+		ID:                n.ID,
+		URL:               n.URL,
+		Author:            author,
+		Body:              n.Body,
+		CreatedAt:         n.CreatedAt,
+		UpdatedAt:         n.UpdatedAt,
+		Reactions:         n.Reactions.TotalCount,
+		ReactionGroups:    n.groups(),
+		ViewerDidAuthor:   n.ViewerDidAuthor,
+		ViewerCanUpdate:   n.ViewerCanUpdate,
+		ViewerCanDelete:   n.ViewerCanDelete,
+		ViewerCanMinimize: n.ViewerCanMinimize,
+		IsMinimized:       n.IsMinimized,
+		MinimizedReason:   n.MinimizedReason,
+	}
 }
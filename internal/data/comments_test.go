@@ -84,3 +84,61 @@ func TestCommentList_Error(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestCommentAdd_DecodesNewComment(t *testing.T) {
+	canned := map[string]interface{}{
+		"addComment": map[string]interface{}{
+			"commentEdge": map[string]interface{}{
+				"node": map[string]interface{}{
+					"id":              "IC_1",
+					"url":             "https://github.com/owner/repo/issues/1#issuecomment-1",
+					"author":          map[string]string{"login": "alice"},
+					"body":            "> quoted\n\nreply",
+					"createdAt":       "2025-01-01T00:00:00Z",
+					"updatedAt":       "2025-01-01T00:00:00Z",
+					"viewerDidAuthor": true,
+				},
+			},
+		},
+	}
+
+	client := NewCommentClient(&mockQuerier{response: canned}, "owner", "repo")
+	c, err := client.Add("I_1", "> quoted\n\nreply")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ID != "IC_1" || c.Author != "alice" || !c.ViewerDidAuthor || c.URL == "" {
+		t.Errorf("comment = %+v", c)
+	}
+}
+
+func TestCommentMinimize_DecodesMinimizedComment(t *testing.T) {
+	canned := map[string]interface{}{
+		"minimizeComment": map[string]interface{}{
+			"minimizedComment": map[string]interface{}{
+				"id":                "IC_2",
+				"author":            map[string]string{"login": "spammer"},
+				"body":              "Buy now",
+				"viewerCanMinimize": true,
+				"isMinimized":       true,
+				"minimizedReason":   "spam",
+			},
+		},
+	}
+
+	client := NewCommentClient(&mockQuerier{response: canned}, "owner", "repo")
+	c, err := client.Minimize("IC_2", "SPAM")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.IsMinimized || c.MinimizedReason != "spam" || !c.ViewerCanMinimize {
+		t.Errorf("comment = %+v", c)
+	}
+}
+
+func TestCommentDelete_Error(t *testing.T) {
+	client := NewCommentClient(&mockQuerier{err: errors.New("graphql error")}, "owner", "repo")
+	if err := client.Delete("IC_1"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
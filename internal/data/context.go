@@ -0,0 +1,85 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// ContextQuerier is a Querier that can also be cancelled through a context.
+// api.GraphQLClient satisfies it.
+type ContextQuerier interface {
+	Querier
+	DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error
+}
+
+// TimeoutQuerier wraps a ContextQuerier so that every request is bounded by a
+// timeout, whether or not the caller supplied a context.
+type TimeoutQuerier struct {
+	querier ContextQuerier
+	timeout time.Duration
+}
+
+// NewTimeoutQuerier creates a TimeoutQuerier. A timeout of zero or less
+// disables the limit.
+func NewTimeoutQuerier(q ContextQuerier, timeout time.Duration) *TimeoutQuerier {
+	return &TimeoutQuerier{querier: q, timeout: timeout}
+}
+
+// Do implements Querier.
+func (t *TimeoutQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return t.DoWithContext(context.Background(), query, variables, response)
+}
+
+// DoWithContext implements ContextQuerier.
+func (t *TimeoutQuerier) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	return t.querier.DoWithContext(ctx, query, variables, response)
+}
+
+// doContext runs a query through q with ctx and classifies any failure as
+// an *Error. Queriers without context support are called directly once ctx
+// has been checked, so a request that was cancelled before it started is
+// never sent.
+//
+// When the server reports errors for nested fields only, response is still
+// populated and the returned error has kind ErrPartialData.
+func doContext(ctx context.Context, q Querier, query string, variables map[string]interface{}, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	var err error
+	if cq, ok := q.(ContextQuerier); ok {
+		err = cq.DoWithContext(ctx, query, variables, &raw)
+	} else {
+		err = q.Do(query, variables, &raw)
+	}
+
+	var gqlErr *api.GraphQLError
+	partial := errors.As(err, &gqlErr) && isPartial(gqlErr, raw)
+	if err == nil || partial {
+		if len(raw) > 0 {
+			if uerr := json.Unmarshal(raw, response); uerr != nil {
+				return uerr
+			}
+		}
+	}
+	if partial {
+		return &Error{Kind: ErrPartialData, Message: gqlErr.Errors[0].Message, Err: err}
+	}
+	return ClassifyError(err)
+}
+
+// do is doContext without cancellation.
+func do(q Querier, query string, variables map[string]interface{}, response interface{}) error {
+	return doContext(context.Background(), q, query, variables, response)
+}
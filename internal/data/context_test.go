@@ -0,0 +1,76 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// contextQuerier records the context of each request.
+type contextQuerier struct {
+	mockQuerier
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (c *contextQuerier) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	c.deadline, c.hasDeadline = ctx.Deadline()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Do(query, variables, response)
+}
+
+func TestTimeoutQuerier_AppliesDeadline(t *testing.T) {
+	inner := &contextQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	q := NewTimeoutQuerier(inner, 5*time.Second)
+
+	var resp struct{}
+	if err := q.Do("query", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inner.hasDeadline {
+		t.Fatal("expected request to carry a deadline")
+	}
+	if remaining := time.Until(inner.deadline); remaining <= 0 || remaining > 5*time.Second {
+		t.Errorf("unexpected deadline %v from now", remaining)
+	}
+}
+
+func TestTimeoutQuerier_ZeroDisablesTimeout(t *testing.T) {
+	inner := &contextQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	q := NewTimeoutQuerier(inner, 0)
+
+	var resp struct{}
+	if err := q.Do("query", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inner.hasDeadline {
+		t.Fatal("expected no deadline when timeout is zero")
+	}
+}
+
+func TestGetContext_PassesContextToQuerier(t *testing.T) {
+	inner := &contextQuerier{}
+	client := NewIssueClient(inner, "owner", "repo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetContext(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestListContext_CancelledBeforeSending(t *testing.T) {
+	// mockQuerier has no context support, so cancellation must be checked
+	// before the request is made.
+	q := &mockQuerier{err: errors.New("should not be called")}
+	client := NewCommentClient(q, "owner", "repo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ListContext(ctx, 1, 10, ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
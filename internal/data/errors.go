@@ -0,0 +1,227 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// ErrorKind classifies API failures by what the user can do about them.
+type ErrorKind int
+
+const (
+	// ErrUnknown is any failure that does not fit another kind.
+	ErrUnknown ErrorKind = iota
+	// ErrAuth means the token is missing, expired, or revoked.
+	ErrAuth
+	// ErrPermission means the token is valid but lacks access or scopes.
+	ErrPermission
+	// ErrNotFound means the requested resource does not exist or is hidden.
+	ErrNotFound
+	// ErrRateLimited means the primary or secondary rate limit was hit.
+	ErrRateLimited
+	// ErrNetwork means the API could not be reached or did not answer.
+	ErrNetwork
+	// ErrValidation means the server rejected the input of a mutation.
+	ErrValidation
+	// ErrPartialData means the response was returned with some fields
+	// missing. The response has been populated with what was returned.
+	ErrPartialData
+	// ErrServer means GitHub failed to process the request.
+	ErrServer
+	// ErrConflict means the resource changed on GitHub since it was read,
+	// so an edit based on it was not made.
+	ErrConflict
+)
+
+// Error is an API failure classified by kind. It wraps the underlying error,
+// so errors.As still finds api.HTTPError and api.GraphQLError.
+type Error struct {
+	Kind ErrorKind
+	// Resource names what was not found, e.g. "issue" or "repository".
+	Resource string
+	// Message is the server's explanation, when it gave one.
+	Message string
+	// RetryAt is when a rate limited request may be retried, if known.
+	RetryAt time.Time
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsPartialData reports whether err only signals that some fields of an
+// otherwise usable response could not be loaded.
+func IsPartialData(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Kind == ErrPartialData
+}
+
+// ClassifyError converts err into an *Error. Errors that are already
+// classified are returned unchanged; nil stays nil.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	e := &Error{Kind: ErrUnknown, Err: err}
+
+	var httpErr *api.HTTPError
+	var gqlErr *api.GraphQLError
+	var netErr net.Error
+	var urlErr *url.Error
+
+	switch {
+	case errors.As(err, &httpErr):
+		classifyHTTPError(e, httpErr)
+	case errors.As(err, &gqlErr):
+		classifyGraphQLError(e, gqlErr)
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Kind = ErrNetwork
+		e.Message = "request timed out"
+	case errors.As(err, &netErr), errors.As(err, &urlErr):
+		e.Kind = ErrNetwork
+	}
+	return e
+}
+
+func classifyHTTPError(e *Error, httpErr *api.HTTPError) {
+	e.Message = strings.SplitN(httpErr.Message, "\n", 2)[0]
+
+	switch status := httpErr.StatusCode; {
+	case status == http.StatusUnauthorized:
+		e.Kind = ErrAuth
+	case status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		if retryAt, ok := rateLimitRetryAt(httpErr); ok {
+			e.Kind = ErrRateLimited
+			e.RetryAt = retryAt
+			return
+		}
+		if strings.Contains(strings.ToLower(httpErr.Message), "rate limit") {
+			e.Kind = ErrRateLimited
+			return
+		}
+		e.Kind = ErrPermission
+	case status == http.StatusNotFound:
+		e.Kind = ErrNotFound
+		e.Resource = resourceFromURL(httpErr.RequestURL)
+	case status == http.StatusUnprocessableEntity || status == http.StatusBadRequest:
+		e.Kind = ErrValidation
+	case status >= 500:
+		e.Kind = ErrServer
+	}
+}
+
+// rateLimitRetryAt reads the retry time from rate limit response headers.
+func rateLimitRetryAt(httpErr *api.HTTPError) (time.Time, bool) {
+	h := httpErr.Headers
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(secs) * time.Second), true
+	}
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resourceFromURL guesses the REST resource from a request URL such as
+// .../repos/owner/repo/milestones/3.
+func resourceFromURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err == nil {
+			continue
+		}
+		if (i >= 1 && parts[i-1] == "repos") || (i >= 2 && parts[i-2] == "repos") {
+			return "repository"
+		}
+		return strings.TrimSuffix(parts[i], "s")
+	}
+	return ""
+}
+
+func classifyGraphQLError(e *Error, gqlErr *api.GraphQLError) {
+	if len(gqlErr.Errors) == 0 {
+		return
+	}
+	first := gqlErr.Errors[0]
+	e.Message = first.Message
+
+	switch first.Type {
+	case "NOT_FOUND":
+		e.Kind = ErrNotFound
+		e.Resource = resourceFromPath(first.Path)
+	case "FORBIDDEN", "INSUFFICIENT_SCOPES":
+		e.Kind = ErrPermission
+	case "RATE_LIMITED", "RATE_LIMIT":
+		e.Kind = ErrRateLimited
+	case "UNPROCESSABLE", "ARGUMENT_ERROR", "INVALID_ARGUMENTS", "UNPROCESSABLE_ENTITY":
+		e.Kind = ErrValidation
+	case "SERVICE_UNAVAILABLE", "INTERNAL":
+		e.Kind = ErrServer
+	}
+}
+
+// isMissingScope reports whether err says the token lacks a scope that a
+// field of the query needs.
+func isMissingScope(err error) bool {
+	var gqlErr *api.GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+	return slices.ContainsFunc(gqlErr.Errors, func(item api.GraphQLErrorItem) bool {
+		return item.Type == "INSUFFICIENT_SCOPES"
+	})
+}
+
+// resourceFromPath names the field a GraphQL error refers to, skipping list
+// indexes, e.g. ["repository", "issue"] is an "issue".
+func resourceFromPath(path []interface{}) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if s, ok := path[i].(string); ok && s != "nodes" && s != "edges" && s != "node" {
+			return s
+		}
+	}
+	return ""
+}
+
+// isPartial reports whether a GraphQL error only affected fields nested
+// below the objects the query asked for, so that the rest of the data in
+// raw is usable.
+func isPartial(gqlErr *api.GraphQLError, raw json.RawMessage) bool {
+	if len(raw) == 0 || string(raw) == "null" || len(gqlErr.Errors) == 0 {
+		return false
+	}
+	for _, item := range gqlErr.Errors {
+		if len(item.Path) <= 2 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestClassifyError(t *testing.T) {
+	rateHeaders := http.Header{}
+	rateHeaders.Set("X-RateLimit-Remaining", "0")
+	rateHeaders.Set("X-RateLimit-Reset", "1748779200")
+	milestoneURL, _ := url.Parse("https://api.github.com/repos/owner/repo/milestones/3")
+
+	tests := []struct {
+		name     string
+		err      error
+		kind     ErrorKind
+		resource string
+	}{
+		{"http 401", &api.HTTPError{StatusCode: 401, Message: "Bad credentials"}, ErrAuth, ""},
+		{"http 403", &api.HTTPError{StatusCode: 403, Message: "Resource not accessible by integration"}, ErrPermission, ""},
+		{"http 403 rate limit", &api.HTTPError{StatusCode: 403, Headers: rateHeaders}, ErrRateLimited, ""},
+		{"http 404", &api.HTTPError{StatusCode: 404, RequestURL: milestoneURL}, ErrNotFound, "milestone"},
+		{"http 422", &api.HTTPError{StatusCode: 422, Message: "Validation Failed"}, ErrValidation, ""},
+		{"http 502", &api.HTTPError{StatusCode: 502}, ErrServer, ""},
+		{"graphql not found", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND", Path: []interface{}{"repository", "issue"}}}}, ErrNotFound, "issue"},
+		{"graphql scopes", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "INSUFFICIENT_SCOPES"}}}, ErrPermission, ""},
+		{"graphql rate limited", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "RATE_LIMITED"}}}, ErrRateLimited, ""},
+		{"wrapped", fmt.Errorf("loading: %w", &api.HTTPError{StatusCode: 401}), ErrAuth, ""},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ErrNetwork, ""},
+		{"deadline", context.DeadlineExceeded, ErrNetwork, ""},
+		{"other", errors.New("boom"), ErrUnknown, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e *Error
+			if !errors.As(ClassifyError(tt.err), &e) {
+				t.Fatalf("expected *Error, got %T", ClassifyError(tt.err))
+			}
+			if e.Kind != tt.kind || e.Resource != tt.resource {
+				t.Errorf("expected kind %d resource %q, got kind %d resource %q", tt.kind, tt.resource, e.Kind, e.Resource)
+			}
+			if !errors.Is(e, tt.err) {
+				t.Error("expected classified error to wrap the original")
+			}
+		})
+	}
+}
+
+func TestClassifyError_RateLimitReset(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-RateLimit-Remaining", "0")
+	headers.Set("X-RateLimit-Reset", "1748779200")
+
+	var e *Error
+	errors.As(ClassifyError(&api.HTTPError{StatusCode: 403, Headers: headers}), &e)
+	if !e.RetryAt.Equal(time.Unix(1748779200, 0)) {
+		t.Fatalf("expected retry time from reset header, got %v", e.RetryAt)
+	}
+}
+
+func TestClassifyError_LeavesCancellation(t *testing.T) {
+	if err := ClassifyError(context.Canceled); err != context.Canceled {
+		t.Fatalf("expected context.Canceled unchanged, got %v", err)
+	}
+	if ClassifyError(nil) != nil {
+		t.Fatal("expected nil to stay nil")
+	}
+}
+
+func TestGet_NotFoundIssue(t *testing.T) {
+	q := &scriptedQuerier{replies: []scriptedReply{{
+		response: map[string]interface{}{"repository": map[string]interface{}{"issue": nil}},
+		err: &api.GraphQLError{Errors: []api.GraphQLErrorItem{{
+			Type:    "NOT_FOUND",
+			Path:    []interface{}{"repository", "issue"},
+			Message: "Could not resolve to an issue or pull request with the number of 999.",
+		}}},
+	}}}
+	client := NewIssueClient(q, "owner", "repo")
+
+	_, err := client.Get(999)
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrNotFound || e.Resource != "issue" {
+		t.Fatalf("expected issue not found error, got %#v", err)
+	}
+}
+
+func TestList_PartialData(t *testing.T) {
+	q := &scriptedQuerier{replies: []scriptedReply{{
+		response: map[string]interface{}{
+			"repository": map[string]interface{}{
+				"issues": map[string]interface{}{
+					"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+					"nodes": []interface{}{
+						map[string]interface{}{"number": 1, "title": "Kept", "state": "OPEN", "author": nil},
+					},
+				},
+			},
+		},
+		err: &api.GraphQLError{Errors: []api.GraphQLErrorItem{{
+			Type:    "FORBIDDEN",
+			Path:    []interface{}{"repository", "issues", "nodes", 0, "author"},
+			Message: "Author is not accessible",
+		}}},
+	}}}
+	client := NewIssueClient(q, "owner", "repo")
+
+	result, err := client.List(IssueListOptions{})
+	if !IsPartialData(err) {
+		t.Fatalf("expected partial data error, got %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Title != "Kept" || result.Issues[0].Author != "[deleted]" {
+		t.Fatalf("expected partial result to be returned, got %+v", result)
+	}
+}
@@ -0,0 +1,44 @@
+package data
+
+// IssueFeatures are the optional issue fields a host's schema has. GitHub
+// Enterprise Server gained them in later releases than github.com, and a
+// query selecting a field the schema lacks fails as a whole, so the issue
+// queries select only the fields the host has.
+type IssueFeatures struct {
+	// SubIssues covers the parent, subIssues, and subIssuesSummary fields.
+	SubIssues bool
+	// IssueTypes covers the issueType field.
+	IssueTypes bool
+}
+
+// AllIssueFeatures are the features of github.com, which issue clients
+// assume until told otherwise.
+var AllIssueFeatures = IssueFeatures{SubIssues: true, IssueTypes: true}
+
+// DetectIssueFeatures asks the host which of the optional issue fields its
+// schema has.
+func DetectIssueFeatures(q Querier) (IssueFeatures, error) {
+	var resp struct {
+		Type *struct {
+			Fields []struct {
+				Name string `json:"name"`
+			} `json:"fields"`
+		} `json:"__type"`
+	}
+	if err := do(q, issueFeaturesQuery, nil, &resp); err != nil {
+		return IssueFeatures{}, err
+	}
+	if resp.Type == nil {
+		return IssueFeatures{}, nil
+	}
+
+	fields := make(map[string]bool, len(resp.Type.Fields))
+	for _, f := range resp.Type.Fields {
+		fields[f.Name] = true
+	}
+	return IssueFeatures{SubIssues: fields["subIssuesSummary"], IssueTypes: fields["issueType"]}, nil
+}
+
+const issueFeaturesQuery = `query IssueFeatures {
+  __type(name: "Issue") { fields { name } }
+}`
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectIssueFeatures(t *testing.T) {
+	fields := func(names ...string) map[string]interface{} {
+		list := make([]map[string]string, len(names))
+		for i, name := range names {
+			list[i] = map[string]string{"name": name}
+		}
+		return map[string]interface{}{"__type": map[string]interface{}{"fields": list}}
+	}
+
+	got, err := DetectIssueFeatures(&mockQuerier{response: fields("title", "subIssuesSummary", "parent", "issueType")})
+	if err != nil || got != AllIssueFeatures {
+		t.Errorf("DetectIssueFeatures = %+v, %v; want %+v", got, err, AllIssueFeatures)
+	}
+	got, err = DetectIssueFeatures(&mockQuerier{response: fields("title")})
+	if err != nil || got != (IssueFeatures{}) {
+		t.Errorf("DetectIssueFeatures = %+v, %v; want none", got, err)
+	}
+}
+
+func TestSetFeatures_LeavesOutMissingFields(t *testing.T) {
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	client := NewIssueClient(q, "owner", "repo")
+
+	if _, err := client.Get(1); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !strings.Contains(q.query, "subIssues(first: 50)") || !strings.Contains(q.query, "issueType") {
+		t.Errorf("query without features set lacks sub-issues or the type:\n%s", q.query)
+	}
+
+	client.SetFeatures(IssueFeatures{})
+	if _, err := client.Get(1); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	queries := []string{q.query}
+	if _, err := client.List(IssueListOptions{}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	queries = append(queries, q.query)
+	for _, query := range queries {
+		for _, field := range []string{"subIssuesSummary", "parent", "subIssues", "issueType"} {
+			if strings.Contains(query, field) {
+				t.Errorf("query selects %s:\n%s", field, query)
+			}
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package data
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
 
 // Querier abstracts a GraphQL client for testability.
 type Querier interface {
@@ -9,18 +15,67 @@ type Querier interface {
 
 // IssueClient fetches issue data via GraphQL.
 type IssueClient struct {
-	querier Querier
-	owner   string
-	repo    string
+	querier  Querier
+	owner    string
+	repo     string
+	features IssueFeatures
 }
 
-// NewIssueClient creates an IssueClient for the given repository.
+// NewIssueClient creates an IssueClient for the given repository, assuming
+// the host has all of AllIssueFeatures.
 func NewIssueClient(q Querier, owner, repo string) *IssueClient {
-	return &IssueClient{querier: q, owner: owner, repo: repo}
+	return &IssueClient{querier: q, owner: owner, repo: repo, features: AllIssueFeatures}
+}
+
+// SetFeatures limits the fields the client selects to those of a host with
+// the given features.
+func (c *IssueClient) SetFeatures(f IssueFeatures) {
+	c.features = f
+}
+
+// listFields returns the optional fields that lists of issues select.
+func (c *IssueClient) listFields() string {
+	var fields []string
+	if c.features.IssueTypes {
+		fields = append(fields, "issueType { id name color }")
+	}
+	if c.features.SubIssues {
+		fields = append(fields, "subIssuesSummary { total completed }")
+	}
+	return strings.Join(fields, "\n        ")
+}
+
+// issueFields returns the optional fields that a single issue selects.
+func (c *IssueClient) issueFields() string {
+	var fields []string
+	if c.features.IssueTypes {
+		fields = append(fields, "issueType { id name color }")
+	}
+	if c.features.SubIssues {
+		fields = append(fields, `subIssuesSummary { total completed }
+      parent { number title state repository { nameWithOwner } }
+      subIssues(first: 50) {
+        nodes { number title state repository { nameWithOwner } subIssuesSummary { total completed } }
+      }`)
+	}
+	return strings.Join(fields, "\n      ")
+}
+
+// Repo returns the client's repository as owner/name.
+func (c *IssueClient) Repo() string {
+	return c.owner + "/" + c.repo
 }
 
 // List fetches a page of issues matching the given options.
 func (c *IssueClient) List(opts IssueListOptions) (IssueListResult, error) {
+	return c.ListContext(context.Background(), opts)
+}
+
+// ListContext is like List but can be cancelled through ctx. When only some
+// fields could not be loaded, the partial result is returned together with
+// an error for which IsPartialData reports true; the same holds for the
+// other read methods.
+func (c *IssueClient) ListContext(ctx context.Context, opts IssueListOptions) (IssueListResult, error) {
 	if opts.First == 0 {
 		opts.First = 50
 	}
@@ -46,15 +101,21 @@ func (c *IssueClient) List(opts IssueListOptions) (IssueListResult, error) {
 	}
 
 	var resp listIssuesResponse
-	if err := c.querier.Do(listIssuesQuery, vars, &resp); err != nil {
+	err := doContext(ctx, c.querier, fmt.Sprintf(listIssuesQuery, c.listFields()), vars, &resp)
+	if err != nil && !IsPartialData(err) {
 		return IssueListResult{}, err
 	}
 
-	return resp.toResult(), nil
+	return resp.toResult(), err
 }
 
 // Get fetches a single issue by number, including its body.
 func (c *IssueClient) Get(number int) (Issue, error) {
+	return c.GetContext(context.Background(), number)
+}
+
+// GetContext is like Get but can be cancelled through ctx.
+func (c *IssueClient) GetContext(ctx context.Context, number int) (Issue, error) {
 	vars := map[string]interface{}{
 		"owner":  c.owner,
 		"name":   c.repo,
@@ -62,21 +123,202 @@ func (c *IssueClient) Get(number int) (Issue, error) {
 	}
 
 	var resp getIssueResponse
-	if err := c.querier.Do(getIssueQuery, vars, &resp); err != nil {
+	err := doContext(ctx, c.querier, fmt.Sprintf(getIssueQuery, c.issueFields()), vars, &resp)
+	if err != nil && !IsPartialData(err) {
 		return Issue{}, err
 	}
 
 	node := resp.Repository.Issue
-	return node.toIssue(), nil
+	return node.toIssue(), err
 }
 
-// GraphQL queries
+// Search fetches a page of issues in the repository matching a GitHub search
+// query. The query is scoped to this repository and to issues automatically.
+func (c *IssueClient) Search(query string, first int, after string) (IssueListResult, error) {
+	return c.SearchContext(context.Background(), query, first, after)
+}
+
+// SearchContext is like Search but can be cancelled through ctx.
+func (c *IssueClient) SearchContext(ctx context.Context, query string, first int, after string) (IssueListResult, error) {
+	if first == 0 {
+		first = 50
+	}
+
+	vars := map[string]interface{}{
+		"query": fmt.Sprintf("repo:%s/%s is:issue %s", c.owner, c.repo, query),
+		"first": first,
+	}
+	if after != "" {
+		vars["after"] = after
+	}
+
+	var resp searchIssuesResponse
+	err := doContext(ctx, c.querier, fmt.Sprintf(searchIssuesQuery, c.listFields()), vars, &resp)
+	if err != nil && !IsPartialData(err) {
+		return IssueListResult{}, err
+	}
+
+	return resp.toResult(), err
+}
+
+// LoadProjects fills in the Projects of the issues, which the other read
+// methods leave out: reading projects needs the read:project scope, which gh
+// does not ask for by default. Without the scope the issues are left without
+// projects and no error is returned.
+func (c *IssueClient) LoadProjects(issues []Issue) error {
+	return c.LoadProjectsContext(context.Background(), issues)
+}
+
+// LoadProjectsContext is like LoadProjects but can be cancelled through ctx.
+func (c *IssueClient) LoadProjectsContext(ctx context.Context, issues []Issue) error {
+	byID := make(map[string]*Issue, len(issues))
+	var ids []string
+	for i := range issues {
+		if issues[i].ID != "" {
+			byID[issues[i].ID] = &issues[i]
+			ids = append(ids, issues[i].ID)
+		}
+	}
+
+	var partial error
+	for chunk := range slices.Chunk(ids, maxNodeIDs) {
+		var resp issueProjectsResponse
+		err := doContext(ctx, c.querier, issueProjectsQuery, map[string]interface{}{"ids": chunk}, &resp)
+		if isMissingScope(err) {
+			return nil
+		}
+		if err != nil && !IsPartialData(err) {
+			return err
+		}
+		if err != nil {
+			partial = err
+		}
+		for _, n := range resp.Nodes {
+			issue := byID[n.ID]
+			if issue == nil {
+				continue
+			}
+			issue.Projects = nil
+			for _, item := range n.ProjectItems.Nodes {
+				issue.Projects = append(issue.Projects, item.toProject())
+			}
+		}
+	}
+	return partial
+}
+
+// maxNodeIDs is the most node IDs GitHub looks up in one nodes query.
+const maxNodeIDs = 100
+
+// RepositoryID returns the node ID of the repository, for creating several
+// issues or labels without looking it up for each.
+func (c *IssueClient) RepositoryID() (string, error) {
+	return repositoryID(c.querier, c.owner, c.repo)
+}
+
+// Create creates a new issue in the repository.
+func (c *IssueClient) Create(input IssueCreateInput) (Issue, error) {
+	repoID := input.RepositoryID
+	if repoID == "" {
+		var err error
+		if repoID, err = repositoryID(c.querier, c.owner, c.repo); err != nil {
+			return Issue{}, err
+		}
+	}
+
+	in := map[string]interface{}{
+		"repositoryId": repoID,
+		"title":        input.Title,
+		"body":         input.Body,
+	}
+	if len(input.LabelIDs) > 0 {
+		in["labelIds"] = input.LabelIDs
+	}
+	if len(input.AssigneeIDs) > 0 {
+		in["assigneeIds"] = input.AssigneeIDs
+	}
+	if input.MilestoneID != "" {
+		in["milestoneId"] = input.MilestoneID
+	}
+
+	var resp struct {
+		CreateIssue struct {
+			Issue issueNode `json:"issue"`
+		} `json:"createIssue"`
+	}
+	if err := do(c.querier, createIssueMutation, map[string]interface{}{"input": in}, &resp); err != nil {
+		return Issue{}, err
+	}
+
+	return resp.CreateIssue.Issue.toIssue(), nil
+}
+
+// EditBody replaces the body of the issue with the given number, provided
+// it is still oldBody on GitHub. If someone else changed it meanwhile, the
+// edit is not made and the current body is returned with an ErrConflict
+// error. On success the body as saved is returned.
+func (c *IssueClient) EditBody(number int, oldBody, newBody string) (string, error) {
+	current, err := c.Get(number)
+	if err != nil {
+		return "", err
+	}
+	if current.Body != oldBody {
+		return current.Body, &Error{
+			Kind:     ErrConflict,
+			Resource: "issue",
+			Message:  fmt.Sprintf("#%d was edited on GitHub since it was loaded; showing the new version", number),
+			Err:      fmt.Errorf("issue #%d body changed since it was loaded", number),
+		}
+	}
+
+	vars := map[string]interface{}{"input": map[string]interface{}{"id": current.ID, "body": newBody}}
+	var resp struct {
+		UpdateIssue struct {
+			Issue struct {
+				Body string `json:"body"`
+			} `json:"issue"`
+		} `json:"updateIssue"`
+	}
+	if err := do(c.querier, updateIssueMutation, vars, &resp); err != nil {
+		return "", err
+	}
+	return resp.UpdateIssue.Issue.Body, nil
+}
+
+// GraphQL queries. The %s in the issue queries takes the optional fields of
+// listFields or issueFields.
 
 const listIssuesQuery = `query ListIssues($owner: String!, $name: String!, $first: Int!, $after: String, $states: [IssueState!], $labels: [String!], $orderBy: IssueOrder!) {
   repository(owner: $owner, name: $name) {
     issues(first: $first, after: $after, states: $states, labels: $labels, orderBy: $orderBy) {
       pageInfo { hasNextPage endCursor }
       nodes {
+        id
+        number
+        title
+        state
+        createdAt
+        updatedAt
+        author { login }
+        labels(first: 10) { nodes { name color } }
+        assignees(first: 5) { nodes { login } }
+        milestone { title }
+        comments { totalCount }
+        reactions { totalCount }
+        url
+        %s
+      }
+    }
+  }
+}`
+
+const searchIssuesQuery = `query SearchIssues($query: String!, $first: Int!, $after: String) {
+  search(query: $query, type: ISSUE, first: $first, after: $after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Issue {
+        id
         number
         title
         state
@@ -88,6 +330,8 @@ const listIssuesQuery = `query ListIssues($owner: String!, $name: String!, $firs
         milestone { title }
         comments { totalCount }
         reactions { totalCount }
+        url
+        %s
       }
     }
   }
@@ -96,6 +340,7 @@ const listIssuesQuery = `query ListIssues($owner: String!, $name: String!, $firs
 const getIssueQuery = `query GetIssue($owner: String!, $name: String!, $number: Int!) {
   repository(owner: $owner, name: $name) {
     issue(number: $number) {
+      id
       number
       title
       state
@@ -107,11 +352,59 @@ const getIssueQuery = `query GetIssue($owner: String!, $name: String!, $number:
       milestone { title }
       comments { totalCount }
       reactions { totalCount }
+      reactionGroups { content viewerHasReacted reactors { totalCount } }
       body
+      url
+      %s
+    }
+  }
+}`
+
+const issueProjectsQuery = `query IssueProjects($ids: [ID!]!) {
+  nodes(ids: $ids) {
+    ... on Issue {
+      id
+      ` + issueProjectItems + `
     }
   }
 }`
 
+// issueProjectItems selects an issue's project items and the values of
+// their fields. Values of other kinds, such as the built-in Title and
+// Labels fields, decode without a field data type and are skipped.
+const issueProjectItems = `projectItems(first: 10) {
+        nodes {
+          id
+          project { id number title }
+          fieldValues(first: 20) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue { name optionId color field { ... on ProjectV2FieldCommon { id name dataType } } }
+              ... on ProjectV2ItemFieldIterationValue { title iterationId field { ... on ProjectV2FieldCommon { id name dataType } } }
+              ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { id name dataType } } }
+              ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { id name dataType } } }
+              ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { id name dataType } } }
+            }
+          }
+        }
+      }`
+
+const createIssueMutation = `mutation CreateIssue($input: CreateIssueInput!) {
+  createIssue(input: $input) {
+    issue {
+      number
+      title
+      state
+      createdAt
+      updatedAt
+      author { login }
+    }
+  }
+}`
+
+const updateIssueMutation = `mutation UpdateIssue($input: UpdateIssueInput!) {
+  updateIssue(input: $input) { issue { body updatedAt } }
+}`
+
 // Internal response structs mirroring GraphQL JSON shape.
 
 type listIssuesResponse struct {
@@ -135,6 +428,30 @@ func (r *listIssuesResponse) toResult() IssueListResult {
 	}
 }
 
+type searchIssuesResponse struct {
+	Search struct {
+		IssueCount int             `json:"issueCount"`
+		PageInfo   graphqlPageInfo `json:"pageInfo"`
+		Nodes      []issueNode     `json:"nodes"`
+	} `json:"search"`
+}
+
+func (r *searchIssuesResponse) toResult() IssueListResult {
+	issues := make([]Issue, 0, len(r.Search.Nodes))
+	for _, n := range r.Search.Nodes {
+		// Non-issue results decode as empty nodes.
+		if n.Number == 0 {
+			continue
+		}
+		issues = append(issues, n.toIssue())
+	}
+	return IssueListResult{
+		Issues:     issues,
+		PageInfo:   PageInfo(r.Search.PageInfo),
+		TotalCount: r.Search.IssueCount,
+	}
+}
+
 type getIssueResponse struct {
 	Repository struct {
 		Issue issueNode `json:"issue"`
@@ -142,6 +459,7 @@ type getIssueResponse struct {
 }
 
 type issueNode struct {
+	ID        string    `json:"id"`
 	Number    int       `json:"number"`
 	Title     string    `json:"title"`
 	State     string    `json:"state"`
@@ -164,13 +482,117 @@ type issueNode struct {
 	Milestone *struct {
 		Title string `json:"title"`
 	} `json:"milestone"`
-	Comments struct {
+	IssueType *issueTypeNode `json:"issueType"`
+	Comments  struct {
 		TotalCount int `json:"totalCount"`
 	} `json:"comments"`
 	Reactions struct {
 		TotalCount int `json:"totalCount"`
 	} `json:"reactions"`
-	Body string `json:"body"`
+	reactableNode
+	Body             string               `json:"body"`
+	URL              string               `json:"url"`
+	SubIssuesSummary subIssuesSummaryNode `json:"subIssuesSummary"`
+	Parent           *issueRefNode        `json:"parent"`
+	SubIssues        struct {
+		Nodes []issueRefNode `json:"nodes"`
+	} `json:"subIssues"`
+}
+
+type subIssuesSummaryNode struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+type issueRefNode struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	State      string `json:"state"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	SubIssuesSummary subIssuesSummaryNode `json:"subIssuesSummary"`
+}
+
+func (n issueRefNode) toRef() IssueRef {
+	return IssueRef{
+		Repo:             n.Repository.NameWithOwner,
+		Number:           n.Number,
+		Title:            n.Title,
+		State:            n.State,
+		SubIssueProgress: SubIssueProgress(n.SubIssuesSummary),
+	}
+}
+
+type issueProjectsResponse struct {
+	Nodes []struct {
+		ID           string `json:"id"`
+		ProjectItems struct {
+			Nodes []projectItemNode `json:"nodes"`
+		} `json:"projectItems"`
+	} `json:"nodes"`
+}
+
+type projectItemNode struct {
+	ID      string `json:"id"`
+	Project struct {
+		ID     string `json:"id"`
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	} `json:"project"`
+	FieldValues struct {
+		Nodes []projectValueNode `json:"nodes"`
+	} `json:"fieldValues"`
+}
+
+// projectValueNode is the union of the field value types the queries
+// select.
+type projectValueNode struct {
+	Name        string   `json:"name"`
+	OptionID    string   `json:"optionId"`
+	Color       string   `json:"color"`
+	Title       string   `json:"title"`
+	IterationID string   `json:"iterationId"`
+	Number      *float64 `json:"number"`
+	Date        string   `json:"date"`
+	Text        string   `json:"text"`
+	Field       struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		DataType string `json:"dataType"`
+	} `json:"field"`
+}
+
+func (n projectValueNode) toValue() (ProjectValue, bool) {
+	v := ProjectValue{FieldID: n.Field.ID, FieldName: n.Field.Name, DataType: n.Field.DataType}
+	switch n.Field.DataType {
+	case "SINGLE_SELECT":
+		v.Text, v.OptionID, v.Color = n.Name, n.OptionID, projectColor(n.Color)
+	case "ITERATION":
+		v.Text, v.OptionID = n.Title, n.IterationID
+	case "NUMBER":
+		if n.Number == nil {
+			return ProjectValue{}, false
+		}
+		v.Text = formatProjectNumber(*n.Number)
+	case "DATE":
+		v.Text = n.Date
+	case "TEXT":
+		v.Text = n.Text
+	default:
+		return ProjectValue{}, false
+	}
+	return v, true
+}
+
+func (n projectItemNode) toProject() IssueProject {
+	p := IssueProject{ProjectID: n.Project.ID, ProjectNumber: n.Project.Number, ProjectTitle: n.Project.Title, ItemID: n.ID}
+	for _, fv := range n.FieldValues.Nodes {
+		if v, ok := fv.toValue(); ok {
+			p.Values = append(p.Values, v)
+		}
+	}
+	return p
 }
 
 func (n *issueNode) toIssue() Issue {
@@ -189,24 +611,47 @@ func (n *issueNode) toIssue() Issue {
 		milestone = n.Milestone.Title
 	}
 
+	var issueType IssueType
+	if n.IssueType != nil {
+		issueType = n.IssueType.toType()
+	}
+
 	author := n.Author.Login
 	if author == "" {
 		author = "[deleted]"
 	}
 
+	var parent *IssueRef
+	if n.Parent != nil {
+		ref := n.Parent.toRef()
+		parent = &ref
+	}
+	var subIssues []IssueRef
+	for _, sub := range n.SubIssues.Nodes {
+		subIssues = append(subIssues, sub.toRef())
+	}
+
 	return Issue{
-		Number:        n.Number,
-		Title:         n.Title,
-		State:         n.State,
-		CreatedAt:     n.CreatedAt,
-		UpdatedAt:     n.UpdatedAt,
-		Author:        author,
-		Labels:        labels,
-		Assignees:     assignees,
-		Milestone:     milestone,
-		CommentCount:  n.Comments.TotalCount,
-		ReactionCount: n.Reactions.TotalCount,
-		Body:          n.Body,
+		ID:             n.ID,
+		Number:         n.Number,
+		Title:          n.Title,
+		State:          n.State,
+		CreatedAt:      n.CreatedAt,
+		UpdatedAt:      n.UpdatedAt,
+		Author:         author,
+		Labels:         labels,
+		Assignees:      assignees,
+		Milestone:      milestone,
+		Type:           issueType,
+		CommentCount:   n.Comments.TotalCount,
+		ReactionCount:  n.Reactions.TotalCount,
+		ReactionGroups: n.groups(),
+		Body:           n.Body,
+		URL:            n.URL,
+
+		SubIssueProgress: SubIssueProgress(n.SubIssuesSummary),
+		Parent:           parent,
+		SubIssues:        subIssues,
 	}
 }
 
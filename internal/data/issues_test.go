@@ -201,3 +201,160 @@ func TestGet_Error(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+// capturingQuerier records the query and variables of the last request.
+type capturingQuerier struct {
+	mockQuerier
+	query string
+	vars  map[string]interface{}
+}
+
+func (c *capturingQuerier) Do(query string, vars map[string]interface{}, resp interface{}) error {
+	c.query = query
+	c.vars = vars
+	return c.mockQuerier.Do(query, vars, resp)
+}
+
+func TestSearch_ScopesQueryAndSkipsNonIssues(t *testing.T) {
+	canned := map[string]interface{}{
+		"search": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": "c1"},
+			"nodes": []map[string]interface{}{
+				{
+					"number": 7, "title": "Crash on start", "state": "OPEN",
+					"createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-01-02T00:00:00Z",
+					"author":    map[string]string{"login": "alice"},
+					"labels":    map[string]interface{}{"nodes": []interface{}{}},
+					"assignees": map[string]interface{}{"nodes": []interface{}{}},
+					"comments":  map[string]int{"totalCount": 1},
+					"reactions": map[string]int{"totalCount": 0},
+				},
+				{},
+			},
+		},
+	}
+
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+	client := NewIssueClient(q, "owner", "repo")
+	result, err := client.Search("crash label:bug", 10, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := q.vars["query"]; got != "repo:owner/repo is:issue crash label:bug" {
+		t.Errorf("unexpected search query: %v", got)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Number != 7 {
+		t.Fatalf("expected only issue #7, got %+v", result.Issues)
+	}
+	if result.PageInfo.EndCursor != "c1" {
+		t.Errorf("expected EndCursor c1, got %s", result.PageInfo.EndCursor)
+	}
+}
+
+func TestCreate_SendsInput(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]string{"id": "R1"},
+		"createIssue": map[string]interface{}{
+			"issue": map[string]interface{}{
+				"number": 101, "title": "Imported", "state": "OPEN",
+				"createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-01-01T00:00:00Z",
+				"author": map[string]string{"login": "alice"},
+			},
+		},
+	}
+
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+	issue, err := NewIssueClient(q, "owner", "repo").Create(IssueCreateInput{
+		Title:       "Imported",
+		Body:        "From the old tracker",
+		LabelIDs:    []string{"L1"},
+		MilestoneID: "M1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issue.Number != 101 {
+		t.Errorf("expected issue 101, got %d", issue.Number)
+	}
+
+	input := q.vars["input"].(map[string]interface{})
+	if input["repositoryId"] != "R1" || input["milestoneId"] != "M1" {
+		t.Errorf("unexpected input: %+v", input)
+	}
+	if _, ok := input["assigneeIds"]; ok {
+		t.Error("expected empty assignees to be omitted")
+	}
+}
+
+func TestGet_SubIssues(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"issue": map[string]interface{}{
+				"number": 12, "title": "Epic",
+				"subIssuesSummary": map[string]int{"total": 2, "completed": 1},
+				"parent":           map[string]interface{}{"number": 3, "title": "Roadmap", "state": "OPEN", "repository": map[string]string{"nameWithOwner": "octo/plans"}},
+				"subIssues": map[string]interface{}{
+					"nodes": []map[string]interface{}{
+						{"number": 13, "title": "Parser", "state": "CLOSED", "repository": map[string]string{"nameWithOwner": "octo/hello"}},
+						{"number": 14, "title": "Docs", "state": "OPEN", "repository": map[string]string{"nameWithOwner": "octo/hello"}, "subIssuesSummary": map[string]int{"total": 3, "completed": 2}},
+					},
+				},
+			},
+		},
+	}
+
+	issue, err := NewIssueClient(&mockQuerier{response: canned}, "octo", "hello").Get(12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issue.SubIssueProgress != (SubIssueProgress{Total: 2, Completed: 1}) {
+		t.Errorf("progress = %+v", issue.SubIssueProgress)
+	}
+	if issue.Parent == nil || issue.Parent.Repo != "octo/plans" || issue.Parent.Number != 3 {
+		t.Errorf("parent = %+v", issue.Parent)
+	}
+	if len(issue.SubIssues) != 2 || issue.SubIssues[0].State != "CLOSED" || issue.SubIssues[1].SubIssueProgress.Completed != 2 {
+		t.Errorf("sub-issues = %+v", issue.SubIssues)
+	}
+}
+
+func TestEditBody_Conflict(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"issue": map[string]interface{}{"id": "I_1", "number": 1, "body": "- [ ] edited elsewhere"},
+		},
+	}
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+
+	current, err := NewIssueClient(q, "octo", "hello").EditBody(1, "- [ ] a", "- [x] a")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != ErrConflict {
+		t.Fatalf("err = %v, want a conflict", err)
+	}
+	if current != "- [ ] edited elsewhere" {
+		t.Errorf("current body = %q", current)
+	}
+	if _, ok := q.vars["input"]; ok {
+		t.Error("the body was written despite the conflict")
+	}
+}
+
+func TestEditBody_WritesWhenUnchanged(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"issue": map[string]interface{}{"id": "I_1", "number": 1, "body": "- [ ] a"},
+		},
+		"updateIssue": map[string]interface{}{"issue": map[string]string{"body": "- [x] a"}},
+	}
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+
+	body, err := NewIssueClient(q, "octo", "hello").EditBody(1, "- [ ] a", "- [x] a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	if input["id"] != "I_1" || input["body"] != "- [x] a" || body != "- [x] a" {
+		t.Errorf("input = %+v, body = %q", input, body)
+	}
+}
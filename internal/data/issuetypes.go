@@ -0,0 +1,86 @@
+package data
+
+// IssueTypeClient fetches the issue types of a repository's organization
+// and sets the type of issues via GraphQL.
+type IssueTypeClient struct {
+	querier Querier
+	owner   string
+	repo    string
+}
+
+// NewIssueTypeClient creates an IssueTypeClient for the given repository.
+func NewIssueTypeClient(q Querier, owner, repo string) *IssueTypeClient {
+	return &IssueTypeClient{querier: q, owner: owner, repo: repo}
+}
+
+// List fetches the issue types available in the repository. Repositories
+// of personal accounts have none.
+func (c *IssueTypeClient) List() ([]IssueType, error) {
+	vars := map[string]interface{}{"owner": c.owner, "name": c.repo}
+	var resp struct {
+		Repository struct {
+			IssueTypes struct {
+				Nodes []issueTypeNode `json:"nodes"`
+			} `json:"issueTypes"`
+		} `json:"repository"`
+	}
+	if err := do(c.querier, listIssueTypesQuery, vars, &resp); err != nil {
+		return nil, err
+	}
+
+	types := make([]IssueType, 0, len(resp.Repository.IssueTypes.Nodes))
+	for _, n := range resp.Repository.IssueTypes.Nodes {
+		types = append(types, n.toType())
+	}
+	return types, nil
+}
+
+// SetType sets the type of the issue with node ID issueID, or removes it
+// when typeID is empty, and returns the type as set.
+func (c *IssueTypeClient) SetType(issueID, typeID string) (IssueType, error) {
+	in := map[string]interface{}{"issueId": issueID, "issueTypeId": nil}
+	if typeID != "" {
+		in["issueTypeId"] = typeID
+	}
+	var resp struct {
+		UpdateIssueIssueType struct {
+			Issue struct {
+				IssueType *issueTypeNode `json:"issueType"`
+			} `json:"issue"`
+		} `json:"updateIssueIssueType"`
+	}
+	if err := do(c.querier, updateIssueIssueTypeMutation, map[string]interface{}{"input": in}, &resp); err != nil {
+		return IssueType{}, err
+	}
+	if t := resp.UpdateIssueIssueType.Issue.IssueType; t != nil {
+		return t.toType(), nil
+	}
+	return IssueType{}, nil
+}
+
+const listIssueTypesQuery = `query ListIssueTypes($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    issueTypes(first: 50) {
+      nodes { id name color description }
+    }
+  }
+}`
+
+const updateIssueIssueTypeMutation = `mutation UpdateIssueIssueType($input: UpdateIssueIssueTypeInput!) {
+  updateIssueIssueType(input: $input) {
+    issue { issueType { id name color description } }
+  }
+}`
+
+type issueTypeNode struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// toType converts the node, whose color is one of GitHub's named colors
+// like those of project options.
+func (n issueTypeNode) toType() IssueType {
+	return IssueType{ID: n.ID, Name: n.Name, Color: projectColor(n.Color), Description: n.Description}
+}
@@ -0,0 +1,60 @@
+package data
+
+import "testing"
+
+func TestIssueTypeList(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"issueTypes": map[string]interface{}{
+				"nodes": []map[string]string{
+					{"id": "IT1", "name": "Bug", "color": "RED", "description": "Something isn't working"},
+					{"id": "IT2", "name": "Feature", "color": "BLUE"},
+				},
+			},
+		},
+	}
+
+	types, err := NewIssueTypeClient(&mockQuerier{response: canned}, "owner", "repo").List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(types) != 2 || types[0].Name != "Bug" || types[0].Color != "cf222e" || types[1].Color != "0969da" {
+		t.Fatalf("unexpected types: %+v", types)
+	}
+}
+
+func TestIssueTypeSetAndClear(t *testing.T) {
+	canned := map[string]interface{}{
+		"updateIssueIssueType": map[string]interface{}{
+			"issue": map[string]interface{}{
+				"issueType": map[string]string{"id": "IT1", "name": "Bug", "color": "RED"},
+			},
+		},
+	}
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+	c := NewIssueTypeClient(q, "owner", "repo")
+
+	typ, err := c.SetType("I1", "IT1")
+	if err != nil {
+		t.Fatalf("SetType: %v", err)
+	}
+	if typ.Name != "Bug" {
+		t.Errorf("type = %+v", typ)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	if input["issueId"] != "I1" || input["issueTypeId"] != "IT1" {
+		t.Errorf("unexpected input: %+v", input)
+	}
+
+	q.response = map[string]interface{}{"updateIssueIssueType": map[string]interface{}{"issue": map[string]interface{}{"issueType": nil}}}
+	typ, err = c.SetType("I1", "")
+	if err != nil {
+		t.Fatalf("clearing: %v", err)
+	}
+	if typ != (IssueType{}) {
+		t.Errorf("cleared type = %+v", typ)
+	}
+	if input := q.vars["input"].(map[string]interface{}); input["issueTypeId"] != nil {
+		t.Errorf("clearing input = %+v", input)
+	}
+}
@@ -0,0 +1,138 @@
+package data
+
+// LabelClient fetches and creates repository labels via GraphQL.
+type LabelClient struct {
+	querier Querier
+	owner   string
+	repo    string
+}
+
+// NewLabelClient creates a LabelClient for the given repository.
+func NewLabelClient(q Querier, owner, repo string) *LabelClient {
+	return &LabelClient{querier: q, owner: owner, repo: repo}
+}
+
+// List fetches every label defined in the repository.
+func (c *LabelClient) List() ([]Label, error) {
+	paginator := NewPaginator(100)
+	var labels []Label
+
+	for req := paginator.NextPageRequest(); req != nil; req = paginator.NextPageRequest() {
+		vars := map[string]interface{}{
+			"owner": c.owner,
+			"name":  c.repo,
+			"first": req.First,
+		}
+		if req.After != "" {
+			vars["after"] = req.After
+		}
+
+		var resp listLabelsResponse
+		if err := do(c.querier, listLabelsQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		nodes := resp.Repository.Labels.Nodes
+		for _, n := range nodes {
+			labels = append(labels, Label(n))
+		}
+		paginator.Update(PageInfo(resp.Repository.Labels.PageInfo), len(nodes))
+	}
+
+	return labels, nil
+}
+
+// Create creates a label in the repository. Color is a hex string without
+// '#'. repoID is the repository's node ID, from IssueClient.RepositoryID; it
+// is looked up when empty.
+func (c *LabelClient) Create(repoID, name, color string) (Label, error) {
+	if repoID == "" {
+		var err error
+		if repoID, err = repositoryID(c.querier, c.owner, c.repo); err != nil {
+			return Label{}, err
+		}
+	}
+
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{
+			"repositoryId": repoID,
+			"name":         name,
+			"color":        color,
+		},
+	}
+
+	var resp struct {
+		CreateLabel struct {
+			Label labelNode `json:"label"`
+		} `json:"createLabel"`
+	}
+	if err := do(c.querier, createLabelMutation, vars, &resp); err != nil {
+		return Label{}, err
+	}
+
+	return Label(resp.CreateLabel.Label), nil
+}
+
+// AddToIssue adds labels to an issue, given their node IDs.
+func (c *LabelClient) AddToIssue(issueID string, labelIDs []string) error {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"labelableId": issueID, "labelIds": labelIDs},
+	}
+	var resp struct {
+		AddLabelsToLabelable struct {
+			ClientMutationID string `json:"clientMutationId"`
+		} `json:"addLabelsToLabelable"`
+	}
+	return do(c.querier, addLabelsMutation, vars, &resp)
+}
+
+// RemoveFromIssue removes labels from an issue, given their node IDs.
+func (c *LabelClient) RemoveFromIssue(issueID string, labelIDs []string) error {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{"labelableId": issueID, "labelIds": labelIDs},
+	}
+	var resp struct {
+		RemoveLabelsFromLabelable struct {
+			ClientMutationID string `json:"clientMutationId"`
+		} `json:"removeLabelsFromLabelable"`
+	}
+	return do(c.querier, removeLabelsMutation, vars, &resp)
+}
+
+const listLabelsQuery = `query ListLabels($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    labels(first: $first, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes { id name color }
+    }
+  }
+}`
+
+const createLabelMutation = `mutation CreateLabel($input: CreateLabelInput!) {
+  createLabel(input: $input) {
+    label { id name color }
+  }
+}`
+
+const addLabelsMutation = `mutation AddLabelsToLabelable($input: AddLabelsToLabelableInput!) {
+  addLabelsToLabelable(input: $input) { clientMutationId }
+}`
+
+const removeLabelsMutation = `mutation RemoveLabelsFromLabelable($input: RemoveLabelsFromLabelableInput!) {
+  removeLabelsFromLabelable(input: $input) { clientMutationId }
+}`
+
+type listLabelsResponse struct {
+	Repository struct {
+		Labels struct {
+			PageInfo graphqlPageInfo `json:"pageInfo"`
+			Nodes    []labelNode     `json:"nodes"`
+		} `json:"labels"`
+	} `json:"repository"`
+}
+
+type labelNode struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
@@ -0,0 +1,68 @@
+package data
+
+import "testing"
+
+func TestLabelList(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+				"nodes": []map[string]string{
+					{"id": "L1", "name": "bug", "color": "d73a4a"},
+					{"id": "L2", "name": "docs", "color": "0075ca"},
+				},
+			},
+		},
+	}
+
+	labels, err := NewLabelClient(&mockQuerier{response: canned}, "owner", "repo").List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(labels) != 2 || labels[0].ID != "L1" || labels[1].Name != "docs" {
+		t.Fatalf("unexpected labels: %+v", labels)
+	}
+}
+
+func TestLabelCreate(t *testing.T) {
+	// The canned response answers both the repository ID lookup and the mutation.
+	canned := map[string]interface{}{
+		"repository": map[string]string{"id": "R1"},
+		"createLabel": map[string]interface{}{
+			"label": map[string]string{"id": "L9", "name": "area/ui", "color": "ededed"},
+		},
+	}
+
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+	label, err := NewLabelClient(q, "owner", "repo").Create("", "area/ui", "ededed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if label.ID != "L9" || label.Name != "area/ui" {
+		t.Fatalf("unexpected label: %+v", label)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	if input["repositoryId"] != "R1" || input["color"] != "ededed" {
+		t.Errorf("unexpected mutation input: %+v", input)
+	}
+}
+
+func TestLabelAddAndRemoveFromIssue(t *testing.T) {
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	c := NewLabelClient(q, "owner", "repo")
+
+	if err := c.AddToIssue("I1", []string{"L1"}); err != nil {
+		t.Fatalf("AddToIssue: %v", err)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	if input["labelableId"] != "I1" || input["labelIds"].([]string)[0] != "L1" {
+		t.Errorf("unexpected add input: %+v", input)
+	}
+
+	if err := c.RemoveFromIssue("I1", []string{"L2"}); err != nil {
+		t.Fatalf("RemoveFromIssue: %v", err)
+	}
+	if input := q.vars["input"].(map[string]interface{}); input["labelIds"].([]string)[0] != "L2" {
+		t.Errorf("unexpected remove input: %+v", input)
+	}
+}
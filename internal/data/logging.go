@@ -0,0 +1,60 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// LoggingQuerier is querier middleware that logs every request with its
+// operation name, variables, duration, point cost, and error. Tokens are
+// scrubbed from the variables, as in cassettes.
+type LoggingQuerier struct {
+	querier ContextQuerier
+	logger  *slog.Logger
+	now     func() time.Time
+}
+
+// NewLoggingQuerier wraps q so that requests are logged to logger.
+func NewLoggingQuerier(q ContextQuerier, logger *slog.Logger) *LoggingQuerier {
+	return &LoggingQuerier{querier: q, logger: logger, now: time.Now}
+}
+
+// Do implements Querier.
+func (l *LoggingQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return l.DoWithContext(context.Background(), query, variables, response)
+}
+
+// DoWithContext implements ContextQuerier.
+func (l *LoggingQuerier) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	start := l.now()
+	var raw json.RawMessage
+	err := l.querier.DoWithContext(ctx, query, variables, &raw)
+	elapsed := l.now().Sub(start)
+	if len(raw) > 0 {
+		if uerr := json.Unmarshal(raw, response); uerr != nil && err == nil {
+			err = uerr
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", operationLabel(query)),
+		slog.Any("variables", scrubVariables(variables)),
+		slog.Int64("duration_ms", elapsed.Milliseconds()),
+	}
+	var resp struct {
+		RateLimit *graphqlRateLimit `json:"rateLimit"`
+	}
+	if json.Unmarshal(raw, &resp) == nil && resp.RateLimit != nil {
+		attrs = append(attrs, slog.Int("cost", resp.RateLimit.Cost), slog.Int("remaining", resp.RateLimit.Remaining))
+	}
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", scrub(err.Error())))
+	}
+	l.logger.LogAttrs(ctx, level, "graphql request", attrs...)
+	return err
+}
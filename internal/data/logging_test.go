@@ -0,0 +1,65 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestLoggingQuerier(t *testing.T) {
+	q := &scriptedQuerier{replies: []scriptedReply{
+		{response: map[string]interface{}{
+			"rateLimit":  map[string]interface{}{"limit": 5000, "cost": 1, "remaining": 4990},
+			"repository": map[string]interface{}{"issue": map[string]interface{}{"number": 7, "title": "Logged"}},
+		}},
+		{err: &api.HTTPError{StatusCode: 502, Message: "Bad Gateway"}},
+	}}
+
+	var buf bytes.Buffer
+	l := NewLoggingQuerier(q, slog.New(slog.NewJSONHandler(&buf, nil)))
+	ticks := []time.Time{time.Unix(0, 0), time.Unix(0, int64(250*time.Millisecond))}
+	l.now = func() time.Time {
+		now := ticks[0]
+		ticks = append(ticks[1:], ticks[0])
+		return now
+	}
+
+	issue, err := NewIssueClient(l, "octo", "hello").Get(7)
+	if err != nil || issue.Title != "Logged" {
+		t.Fatalf("Get = %+v, %v", issue, err)
+	}
+	if _, err := NewIssueClient(l, "octo", "hello").Get(8); err == nil {
+		t.Fatal("expected second Get to fail")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two log lines:\n%s", buf.String())
+	}
+
+	var ok, failed map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok["level"] != "INFO" || ok["operation"] != "GetIssue" || ok["duration_ms"] != 250.0 || ok["cost"] != 1.0 || ok["remaining"] != 4990.0 {
+		t.Errorf("success entry = %v", ok)
+	}
+	if vars, _ := ok["variables"].(map[string]interface{}); vars["number"] != 7.0 {
+		t.Errorf("variables = %v", ok["variables"])
+	}
+	if failed["level"] != "WARN" || !strings.Contains(failed["error"].(string), "502") {
+		t.Errorf("failure entry = %v", failed)
+	}
+	if _, ok := failed["cost"]; ok {
+		t.Errorf("failure entry has a cost: %v", failed)
+	}
+}
@@ -0,0 +1,107 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RESTDoer abstracts a REST client for testability. GitHub's GraphQL API has
+// no mutation for creating milestones, so MilestoneClient uses REST for it.
+type RESTDoer interface {
+	Do(method string, path string, body io.Reader, response interface{}) error
+}
+
+// MilestoneClient fetches milestones via GraphQL and creates them via REST.
+type MilestoneClient struct {
+	querier Querier
+	rest    RESTDoer
+	owner   string
+	repo    string
+}
+
+// NewMilestoneClient creates a MilestoneClient for the given repository.
+func NewMilestoneClient(q Querier, r RESTDoer, owner, repo string) *MilestoneClient {
+	return &MilestoneClient{querier: q, rest: r, owner: owner, repo: repo}
+}
+
+// List fetches every open and closed milestone in the repository.
+func (c *MilestoneClient) List() ([]Milestone, error) {
+	paginator := NewPaginator(100)
+	var milestones []Milestone
+
+	for req := paginator.NextPageRequest(); req != nil; req = paginator.NextPageRequest() {
+		vars := map[string]interface{}{
+			"owner": c.owner,
+			"name":  c.repo,
+			"first": req.First,
+		}
+		if req.After != "" {
+			vars["after"] = req.After
+		}
+
+		var resp listMilestonesResponse
+		if err := do(c.querier, listMilestonesQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		nodes := resp.Repository.Milestones.Nodes
+		for _, n := range nodes {
+			milestones = append(milestones, Milestone(n))
+		}
+		paginator.Update(PageInfo(resp.Repository.Milestones.PageInfo), len(nodes))
+	}
+
+	return milestones, nil
+}
+
+// Create creates an open milestone with the given title.
+func (c *MilestoneClient) Create(title string) (Milestone, error) {
+	if c.rest == nil {
+		return Milestone{}, fmt.Errorf("creating milestones requires a REST client")
+	}
+
+	body, err := json.Marshal(map[string]string{"title": title})
+	if err != nil {
+		return Milestone{}, err
+	}
+
+	var resp struct {
+		NodeID string `json:"node_id"`
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+	}
+	path := fmt.Sprintf("repos/%s/%s/milestones", c.owner, c.repo)
+	if err := c.rest.Do("POST", path, bytes.NewReader(body), &resp); err != nil {
+		return Milestone{}, ClassifyError(err)
+	}
+
+	return Milestone{ID: resp.NodeID, Number: resp.Number, Title: resp.Title, State: "OPEN"}, nil
+}
+
+const listMilestonesQuery = `query ListMilestones($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    milestones(first: $first, after: $after, states: [OPEN, CLOSED]) {
+      pageInfo { hasNextPage endCursor }
+      nodes { id number title state }
+    }
+  }
+}`
+
+type listMilestonesResponse struct {
+	Repository struct {
+		Milestones struct {
+			PageInfo graphqlPageInfo `json:"pageInfo"`
+			Nodes    []milestoneNode `json:"nodes"`
+		} `json:"milestones"`
+	} `json:"repository"`
+}
+
+type milestoneNode struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+}
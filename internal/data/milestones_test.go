@@ -0,0 +1,75 @@
+package data
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+// mockREST records a single REST request and returns a canned response.
+type mockREST struct {
+	method   string
+	path     string
+	body     map[string]interface{}
+	response interface{}
+}
+
+func (m *mockREST) Do(method, path string, body io.Reader, resp interface{}) error {
+	m.method = method
+	m.path = path
+	if body != nil {
+		if err := json.NewDecoder(body).Decode(&m.body); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(m.response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, resp)
+}
+
+func TestMilestoneList(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"milestones": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+				"nodes": []map[string]interface{}{
+					{"id": "M1", "number": 1, "title": "v1.0", "state": "CLOSED"},
+					{"id": "M2", "number": 2, "title": "v2.0", "state": "OPEN"},
+				},
+			},
+		},
+	}
+
+	milestones, err := NewMilestoneClient(&mockQuerier{response: canned}, nil, "owner", "repo").List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(milestones) != 2 || milestones[1].Title != "v2.0" || milestones[1].Number != 2 {
+		t.Fatalf("unexpected milestones: %+v", milestones)
+	}
+}
+
+func TestMilestoneCreate_UsesREST(t *testing.T) {
+	rest := &mockREST{response: map[string]interface{}{
+		"node_id": "M3", "number": 3, "title": "v3.0", "state": "open",
+	}}
+
+	m, err := NewMilestoneClient(&mockQuerier{}, rest, "owner", "repo").Create("v3.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rest.method != "POST" || rest.path != "repos/owner/repo/milestones" || rest.body["title"] != "v3.0" {
+		t.Errorf("unexpected request: %s %s %v", rest.method, rest.path, rest.body)
+	}
+	if m.ID != "M3" || m.Number != 3 {
+		t.Errorf("unexpected milestone: %+v", m)
+	}
+}
+
+func TestMilestoneCreate_WithoutREST(t *testing.T) {
+	if _, err := NewMilestoneClient(&mockQuerier{}, nil, "owner", "repo").Create("v1"); err == nil {
+		t.Fatal("expected error without a REST client")
+	}
+}
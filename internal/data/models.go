@@ -4,6 +4,7 @@ import "time"
 
 // Issue represents a GitHub issue.
 type Issue struct {
+	ID            string // node ID
 	Number        int
 	Title         string
 	State         string
@@ -13,13 +14,73 @@ type Issue struct {
 	Labels        []Label
 	Assignees     []string
 	Milestone     string
+	Type          IssueType // zero when the issue has no type
 	CommentCount  int
 	ReactionCount int
-	Body          string
+	// ReactionGroups is only fetched for a single issue.
+	ReactionGroups []ReactionGroup
+	Body           string
+	URL            string
+	// Projects are the issue's items on Projects v2 projects, with the
+	// values of their fields that are set.
+	Projects []IssueProject
+	// SubIssueProgress counts the issue's sub-issues. Parent and SubIssues
+	// are only fetched for a single issue.
+	SubIssueProgress SubIssueProgress
+	Parent           *IssueRef
+	SubIssues        []IssueRef
+}
+
+// IssueRef is an issue in this or another repository, as shown in an
+// issue hierarchy.
+type IssueRef struct {
+	Repo             string // owner/name
+	Number           int
+	Title            string
+	State            string
+	SubIssueProgress SubIssueProgress
+}
+
+// SubIssueProgress counts an issue's sub-issues and how many are closed.
+type SubIssueProgress struct {
+	Total     int
+	Completed int
+}
+
+// IssueProject is an issue's item on a Projects v2 project.
+type IssueProject struct {
+	ProjectID     string
+	ProjectNumber int
+	ProjectTitle  string
+	ItemID        string // the item's node ID, not the issue's
+	Values        []ProjectValue
+}
+
+// ProjectValue is an item's value of one project field.
+type ProjectValue struct {
+	FieldID   string
+	FieldName string
+	DataType  string // "SINGLE_SELECT", "ITERATION", "NUMBER", "DATE", or "TEXT"
+	// Text is the value as GitHub shows it: the option or iteration title,
+	// the number, the date as YYYY-MM-DD, or the text.
+	Text string
+	// OptionID is the ID of a single-select option or iteration.
+	OptionID string
+	Color    string // hex color of a single-select option, without '#'
+}
+
+// IssueType is one of an organization's issue types, such as Bug or
+// Feature.
+type IssueType struct {
+	ID          string
+	Name        string
+	Color       string // hex color without '#'
+	Description string
 }
 
 // Label represents a GitHub label.
 type Label struct {
+	ID    string
 	Name  string
 	Color string // hex color without '#'
 }
@@ -34,6 +95,9 @@ type PageInfo struct {
 type IssueListResult struct {
 	Issues   []Issue
 	PageInfo PageInfo
+	// TotalCount is the number of issues matching a search, which can be
+	// more than the search returns. It is zero for lists.
+	TotalCount int
 }
 
 // IssueListOptions configures an issue list query.
@@ -50,3 +114,24 @@ type IssueOrder struct {
 	Field     string // "CREATED_AT", "UPDATED_AT", "COMMENTS"
 	Direction string // "ASC", "DESC"
 }
+
+// Milestone represents a GitHub milestone.
+type Milestone struct {
+	ID     string
+	Number int
+	Title  string
+	State  string // "OPEN", "CLOSED"
+}
+
+// IssueCreateInput describes a new issue. Labels, assignees, and the
+// milestone are referenced by node ID.
+type IssueCreateInput struct {
+	// RepositoryID is the node ID of the repository, from
+	// IssueClient.RepositoryID. It is looked up when empty.
+	RepositoryID string
+	Title        string
+	Body         string
+	LabelIDs     []string
+	AssigneeIDs  []string
+	MilestoneID  string
+}
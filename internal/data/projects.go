@@ -0,0 +1,422 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProjectField is a field of a Projects v2 project, such as Status.
+type ProjectField struct {
+	ProjectID    string
+	ProjectTitle string
+	ID           string
+	Name         string
+	DataType     string // "SINGLE_SELECT", "ITERATION", "NUMBER", "DATE", or "TEXT"
+	// Options are the choices of a single-select field, or the iterations
+	// of an iteration field.
+	Options []ProjectFieldOption
+}
+
+// ProjectFieldOption is one choice of a single-select field or one
+// iteration of an iteration field, which has no color.
+type ProjectFieldOption struct {
+	ID    string
+	Name  string
+	Color string // hex color without '#'
+}
+
+// ProjectItem is an issue on a project, with its value of one field.
+type ProjectItem struct {
+	ID       string // the item's node ID, not the issue's
+	Issue    Issue
+	OptionID string // empty when the field is not set
+}
+
+// ProjectClient reads and updates Projects v2 projects owned by the
+// repository's owner.
+type ProjectClient struct {
+	querier Querier
+	owner   string
+	repo    string
+}
+
+// NewProjectClient creates a ProjectClient for the given repository.
+func NewProjectClient(q Querier, owner, repo string) *ProjectClient {
+	return &ProjectClient{querier: q, owner: owner, repo: repo}
+}
+
+// Field fetches the single-select field with the given name from the
+// owner's project with the given number.
+func (c *ProjectClient) Field(number int, name string) (ProjectField, error) {
+	vars := map[string]interface{}{"owner": c.owner, "number": number, "field": name}
+	var resp struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+				Field *struct {
+					ID      string `json:"id"`
+					Name    string `json:"name"`
+					Options []struct {
+						ID    string `json:"id"`
+						Name  string `json:"name"`
+						Color string `json:"color"`
+					} `json:"options"`
+				} `json:"field"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	if err := do(c.querier, projectFieldQuery, vars, &resp); err != nil {
+		return ProjectField{}, err
+	}
+	if resp.RepositoryOwner == nil || resp.RepositoryOwner.ProjectV2 == nil {
+		return ProjectField{}, &Error{Kind: ErrNotFound, Resource: "project", Err: fmt.Errorf("project %d of %s not found", number, c.owner)}
+	}
+	project := resp.RepositoryOwner.ProjectV2
+	// Fields of other types decode without an ID.
+	if project.Field == nil || project.Field.ID == "" {
+		return ProjectField{}, &Error{Kind: ErrNotFound, Resource: "project field", Err: fmt.Errorf("project %q has no single-select field %q", project.Title, name)}
+	}
+
+	field := ProjectField{ProjectID: project.ID, ProjectTitle: project.Title, ID: project.Field.ID, Name: project.Field.Name, DataType: "SINGLE_SELECT"}
+	for _, o := range project.Field.Options {
+		field.Options = append(field.Options, ProjectFieldOption{ID: o.ID, Name: o.Name, Color: projectColor(o.Color)})
+	}
+	return field, nil
+}
+
+// Items fetches the project's issues from this repository with their value
+// of field, looking at no more than limit of the project's items. Draft
+// issues, pull requests, and issues from other repositories are skipped.
+func (c *ProjectClient) Items(field ProjectField, limit int) ([]ProjectItem, error) {
+	return c.ItemsContext(context.Background(), field, limit)
+}
+
+// ItemsContext is like Items but can be cancelled through ctx.
+func (c *ProjectClient) ItemsContext(ctx context.Context, field ProjectField, limit int) ([]ProjectItem, error) {
+	paginator := NewPaginator(min(limit, 100))
+	var items []ProjectItem
+
+	for seen := 0; seen < limit; {
+		req := paginator.NextPageRequest()
+		if req == nil {
+			break
+		}
+		vars := map[string]interface{}{
+			"id":    field.ProjectID,
+			"field": field.Name,
+			"first": min(req.First, limit-seen),
+		}
+		if req.After != "" {
+			vars["after"] = req.After
+		}
+
+		var resp struct {
+			Node struct {
+				Items struct {
+					PageInfo graphqlPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						ID         string `json:"id"`
+						FieldValue *struct {
+							OptionID string `json:"optionId"`
+						} `json:"fieldValueByName"`
+						Content struct {
+							issueNode
+							Repository struct {
+								NameWithOwner string `json:"nameWithOwner"`
+							} `json:"repository"`
+						} `json:"content"`
+					} `json:"nodes"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		if err := doContext(ctx, c.querier, projectItemsQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		nodes := resp.Node.Items.Nodes
+		seen += len(nodes)
+		for _, n := range nodes {
+			if n.Content.Number == 0 || !strings.EqualFold(n.Content.Repository.NameWithOwner, c.owner+"/"+c.repo) {
+				continue
+			}
+			item := ProjectItem{ID: n.ID, Issue: n.Content.toIssue()}
+			if n.FieldValue != nil {
+				item.OptionID = n.FieldValue.OptionID
+			}
+			items = append(items, item)
+		}
+		paginator.Update(PageInfo(resp.Node.Items.PageInfo), len(nodes))
+	}
+
+	return items, nil
+}
+
+// Fields fetches the fields of the project with node ID projectID whose
+// values can be set: single-select, iteration, number, date, and text
+// fields.
+func (c *ProjectClient) Fields(projectID string) ([]ProjectField, error) {
+	vars := map[string]interface{}{"id": projectID}
+	var resp struct {
+		Node *struct {
+			ID     string `json:"id"`
+			Title  string `json:"title"`
+			Fields struct {
+				Nodes []struct {
+					ID       string `json:"id"`
+					Name     string `json:"name"`
+					DataType string `json:"dataType"`
+					Options  []struct {
+						ID    string `json:"id"`
+						Name  string `json:"name"`
+						Color string `json:"color"`
+					} `json:"options"`
+					Configuration struct {
+						Iterations []struct {
+							ID    string `json:"id"`
+							Title string `json:"title"`
+						} `json:"iterations"`
+					} `json:"configuration"`
+				} `json:"nodes"`
+			} `json:"fields"`
+		} `json:"node"`
+	}
+	if err := do(c.querier, projectFieldsQuery, vars, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Node == nil {
+		return nil, &Error{Kind: ErrNotFound, Resource: "project", Err: fmt.Errorf("project %s not found", projectID)}
+	}
+
+	var fields []ProjectField
+	for _, n := range resp.Node.Fields.Nodes {
+		field := ProjectField{ProjectID: resp.Node.ID, ProjectTitle: resp.Node.Title, ID: n.ID, Name: n.Name, DataType: n.DataType}
+		switch n.DataType {
+		case "SINGLE_SELECT":
+			for _, o := range n.Options {
+				field.Options = append(field.Options, ProjectFieldOption{ID: o.ID, Name: o.Name, Color: projectColor(o.Color)})
+			}
+		case "ITERATION":
+			for _, it := range n.Configuration.Iterations {
+				field.Options = append(field.Options, ProjectFieldOption{ID: it.ID, Name: it.Title})
+			}
+		case "NUMBER", "DATE", "TEXT":
+		default:
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// SetOption sets an item's value of a single-select field.
+func (c *ProjectClient) SetOption(field ProjectField, itemID, optionID string) error {
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{
+			"projectId": field.ProjectID,
+			"itemId":    itemID,
+			"fieldId":   field.ID,
+			"value":     map[string]interface{}{"singleSelectOptionId": optionID},
+		},
+	}
+	var resp struct {
+		UpdateProjectV2ItemFieldValue struct {
+			ProjectV2Item struct {
+				ID string `json:"id"`
+			} `json:"projectV2Item"`
+		} `json:"updateProjectV2ItemFieldValue"`
+	}
+	return do(c.querier, updateProjectFieldMutation, vars, &resp)
+}
+
+// SetValue sets an item's value of field and returns the new value. The
+// value is an option or iteration ID for single-select and iteration
+// fields, a number, a date as YYYY-MM-DD, or text. An empty value clears
+// the field.
+func (c *ProjectClient) SetValue(field ProjectField, itemID, value string) (ProjectValue, error) {
+	input := map[string]interface{}{
+		"projectId": field.ProjectID,
+		"itemId":    itemID,
+		"fieldId":   field.ID,
+	}
+	if value == "" {
+		var resp struct {
+			ClearProjectV2ItemFieldValue struct {
+				ProjectV2Item struct {
+					ID string `json:"id"`
+				} `json:"projectV2Item"`
+			} `json:"clearProjectV2ItemFieldValue"`
+		}
+		err := do(c.querier, clearProjectFieldMutation, map[string]interface{}{"input": input}, &resp)
+		return ProjectValue{FieldID: field.ID, FieldName: field.Name, DataType: field.DataType}, err
+	}
+
+	v, fieldValue, err := projectFieldValue(field, value)
+	if err != nil {
+		return ProjectValue{}, err
+	}
+	input["value"] = fieldValue
+	var resp struct {
+		UpdateProjectV2ItemFieldValue struct {
+			ProjectV2Item struct {
+				ID string `json:"id"`
+			} `json:"projectV2Item"`
+		} `json:"updateProjectV2ItemFieldValue"`
+	}
+	if err := do(c.querier, updateProjectFieldMutation, map[string]interface{}{"input": input}, &resp); err != nil {
+		return ProjectValue{}, err
+	}
+	return v, nil
+}
+
+// projectFieldValue checks value against the field's type and returns it
+// as shown and as the mutation's input.
+func projectFieldValue(field ProjectField, value string) (ProjectValue, map[string]interface{}, error) {
+	v := ProjectValue{FieldID: field.ID, FieldName: field.Name, DataType: field.DataType, Text: value}
+	invalid := func(format string, args ...interface{}) error {
+		msg := fmt.Sprintf(format, args...)
+		return &Error{Kind: ErrValidation, Resource: "project field", Message: msg, Err: fmt.Errorf("%s", msg)}
+	}
+
+	switch field.DataType {
+	case "SINGLE_SELECT", "ITERATION":
+		for _, o := range field.Options {
+			if o.ID == value {
+				v.Text, v.OptionID, v.Color = o.Name, o.ID, o.Color
+				if field.DataType == "SINGLE_SELECT" {
+					return v, map[string]interface{}{"singleSelectOptionId": value}, nil
+				}
+				return v, map[string]interface{}{"iterationId": value}, nil
+			}
+		}
+		return ProjectValue{}, nil, invalid("%s has no option %s", field.Name, value)
+	case "NUMBER":
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return ProjectValue{}, nil, invalid("%s must be a number, not %q", field.Name, value)
+		}
+		v.Text = formatProjectNumber(n)
+		return v, map[string]interface{}{"number": n}, nil
+	case "DATE":
+		if _, err := time.Parse(time.DateOnly, strings.TrimSpace(value)); err != nil {
+			return ProjectValue{}, nil, invalid("%s must be a date such as 2024-01-31, not %q", field.Name, value)
+		}
+		v.Text = strings.TrimSpace(value)
+		return v, map[string]interface{}{"date": v.Text}, nil
+	case "TEXT":
+		return v, map[string]interface{}{"text": value}, nil
+	}
+	return ProjectValue{}, nil, invalid("%s fields can't be set", strings.ToLower(field.DataType))
+}
+
+func formatProjectNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// ProjectValue returns the issue's value of the project field named field,
+// from the first of its projects that has one.
+func (i Issue) ProjectValue(field string) (ProjectValue, bool) {
+	for _, p := range i.Projects {
+		for _, v := range p.Values {
+			if strings.EqualFold(v.FieldName, field) {
+				return v, true
+			}
+		}
+	}
+	return ProjectValue{}, false
+}
+
+// projectColors are the hex colors of the named colors of single-select
+// options, as GitHub shows them.
+var projectColors = map[string]string{
+	"GRAY":   "6e7781",
+	"BLUE":   "0969da",
+	"GREEN":  "1a7f37",
+	"YELLOW": "9a6700",
+	"ORANGE": "bc4c00",
+	"RED":    "cf222e",
+	"PINK":   "bf3989",
+	"PURPLE": "8250df",
+}
+
+func projectColor(name string) string {
+	if hex, ok := projectColors[name]; ok {
+		return hex
+	}
+	return projectColors["GRAY"]
+}
+
+const projectFieldQuery = `query ProjectField($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        title
+        field(name: $field) {
+          ... on ProjectV2SingleSelectField { id name options { id name color } }
+        }
+      }
+    }
+  }
+}`
+
+const projectItemsQuery = `query ProjectItems($id: ID!, $field: String!, $first: Int!, $after: String) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      items(first: $first, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          fieldValueByName(name: $field) {
+            ... on ProjectV2ItemFieldSingleSelectValue { optionId }
+          }
+          content {
+            ... on Issue {
+              id
+              number
+              title
+              state
+              createdAt
+              updatedAt
+              author { login }
+              labels(first: 10) { nodes { name color } }
+              assignees(first: 5) { nodes { login } }
+              milestone { title }
+              comments { totalCount }
+              reactions { totalCount }
+              url
+              repository { nameWithOwner }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const projectFieldsQuery = `query ProjectFields($id: ID!) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      id
+      title
+      fields(first: 50) {
+        nodes {
+          ... on ProjectV2FieldCommon { id name dataType }
+          ... on ProjectV2SingleSelectField { options { id name color } }
+          ... on ProjectV2IterationField { configuration { iterations { id title } } }
+        }
+      }
+    }
+  }
+}`
+
+const updateProjectFieldMutation = `mutation UpdateProjectV2ItemFieldValue($input: UpdateProjectV2ItemFieldValueInput!) {
+  updateProjectV2ItemFieldValue(input: $input) { projectV2Item { id } }
+}`
+
+const clearProjectFieldMutation = `mutation ClearProjectV2ItemFieldValue($input: ClearProjectV2ItemFieldValueInput!) {
+  clearProjectV2ItemFieldValue(input: $input) { projectV2Item { id } }
+}`
@@ -0,0 +1,269 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestProjectField_ConvertsOptionColors(t *testing.T) {
+	canned := map[string]interface{}{
+		"repositoryOwner": map[string]interface{}{
+			"projectV2": map[string]interface{}{
+				"id": "PVT_1", "title": "Roadmap",
+				"field": map[string]interface{}{
+					"id": "F1", "name": "Status",
+					"options": []map[string]string{
+						{"id": "o1", "name": "Todo", "color": "GRAY"},
+						{"id": "o2", "name": "Done", "color": "GREEN"},
+					},
+				},
+			},
+		},
+	}
+
+	field, err := NewProjectClient(&mockQuerier{response: canned}, "octo", "hello").Field(1, "Status")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field.ProjectID != "PVT_1" || field.ID != "F1" || len(field.Options) != 2 {
+		t.Fatalf("unexpected field: %+v", field)
+	}
+	if field.Options[1].Color != "1a7f37" {
+		t.Errorf("Done color = %q, want hex green", field.Options[1].Color)
+	}
+}
+
+func TestProjectField_NotSingleSelect(t *testing.T) {
+	canned := map[string]interface{}{
+		"repositoryOwner": map[string]interface{}{
+			"projectV2": map[string]interface{}{"id": "PVT_1", "title": "Roadmap", "field": map[string]interface{}{}},
+		},
+	}
+
+	_, err := NewProjectClient(&mockQuerier{response: canned}, "octo", "hello").Field(1, "Estimate")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != ErrNotFound {
+		t.Fatalf("err = %v, want not found", err)
+	}
+}
+
+func TestProjectItems_SkipsOtherContent(t *testing.T) {
+	canned := map[string]interface{}{
+		"node": map[string]interface{}{
+			"items": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+				"nodes": []map[string]interface{}{
+					{
+						"id":               "PVTI_1",
+						"fieldValueByName": map[string]string{"optionId": "o2"},
+						"content":          map[string]interface{}{"id": "I_1", "number": 7, "title": "Crash", "repository": map[string]string{"nameWithOwner": "Octo/Hello"}},
+					},
+					{"id": "PVTI_2", "fieldValueByName": nil, "content": map[string]interface{}{}},
+					{"id": "PVTI_3", "content": map[string]interface{}{"number": 9, "repository": map[string]string{"nameWithOwner": "octo/other"}}},
+				},
+			},
+		},
+	}
+
+	items, err := NewProjectClient(&mockQuerier{response: canned}, "octo", "hello").Items(ProjectField{ProjectID: "PVT_1", Name: "Status"}, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "PVTI_1" || items[0].OptionID != "o2" || items[0].Issue.Number != 7 || items[0].Issue.ID != "I_1" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestProjectItems_StopsAtLimit(t *testing.T) {
+	node := func(n int) map[string]interface{} {
+		return map[string]interface{}{"id": fmt.Sprintf("PVTI_%d", n), "content": map[string]interface{}{"number": n, "repository": map[string]string{"nameWithOwner": "octo/hello"}}}
+	}
+	q := &scriptedQuerier{replies: []scriptedReply{{response: map[string]interface{}{
+		"node": map[string]interface{}{"items": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "next"},
+			"nodes":    []map[string]interface{}{node(1), node(2)},
+		}},
+	}}}}
+
+	items, err := NewProjectClient(q, "octo", "hello").Items(ProjectField{ProjectID: "PVT_1", Name: "Status"}, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The scripted pages ignore first, so the third one overshoots.
+	if len(q.queries) != 3 || len(items) != 6 {
+		t.Fatalf("got %d requests and %d items, want to stop after the page that reaches the limit", len(q.queries), len(items))
+	}
+}
+
+func TestProjectSetOption(t *testing.T) {
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	field := ProjectField{ProjectID: "PVT_1", ID: "F1"}
+	if err := NewProjectClient(q, "octo", "hello").SetOption(field, "PVTI_1", "o2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	value := input["value"].(map[string]interface{})
+	if input["projectId"] != "PVT_1" || input["itemId"] != "PVTI_1" || input["fieldId"] != "F1" || value["singleSelectOptionId"] != "o2" {
+		t.Errorf("unexpected mutation input: %+v", input)
+	}
+}
+
+func TestLoadProjects_Values(t *testing.T) {
+	field := func(id, name, dataType string) map[string]string {
+		return map[string]string{"id": id, "name": name, "dataType": dataType}
+	}
+	canned := map[string]interface{}{
+		"nodes": []interface{}{
+			map[string]interface{}{
+				"id": "I_7",
+				"projectItems": map[string]interface{}{
+					"nodes": []map[string]interface{}{{
+						"id":      "PVTI_1",
+						"project": map[string]interface{}{"id": "PVT_1", "number": 1, "title": "Roadmap"},
+						"fieldValues": map[string]interface{}{
+							"nodes": []map[string]interface{}{
+								{"text": "Crash", "field": field("F0", "Title", "TITLE")},
+								{"name": "P1", "optionId": "o1", "color": "RED", "field": field("F1", "Priority", "SINGLE_SELECT")},
+								{"title": "Sprint 3", "iterationId": "it3", "field": field("F2", "Iteration", "ITERATION")},
+								{"number": 2.5, "field": field("F3", "Estimate", "NUMBER")},
+								{"date": "2026-11-01", "field": field("F4", "Due", "DATE")},
+								{"text": "needs design", "field": field("F5", "Notes", "TEXT")},
+								{},
+							},
+						},
+					}},
+				},
+			},
+			nil,
+		},
+	}
+
+	issues := []Issue{{ID: "I_7", Number: 7}, {ID: "I_8", Number: 8}}
+	if err := NewIssueClient(&mockQuerier{response: canned}, "octo", "hello").LoadProjects(issues); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues[1].Projects != nil {
+		t.Errorf("issue 8 projects = %+v, want none", issues[1].Projects)
+	}
+	issue := issues[0]
+	if len(issue.Projects) != 1 || issue.Projects[0].ItemID != "PVTI_1" || issue.Projects[0].ProjectTitle != "Roadmap" {
+		t.Fatalf("unexpected projects: %+v", issue.Projects)
+	}
+	var got []string
+	for _, v := range issue.Projects[0].Values {
+		got = append(got, v.FieldName+"="+v.Text)
+	}
+	want := []string{"Priority=P1", "Iteration=Sprint 3", "Estimate=2.5", "Due=2026-11-01", "Notes=needs design"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("values = %v, want %v", got, want)
+	}
+	if v, ok := issue.ProjectValue("priority"); !ok || v.OptionID != "o1" || v.Color != "cf222e" {
+		t.Errorf("ProjectValue(priority) = %+v, %v", v, ok)
+	}
+}
+
+func TestLoadProjects_WithoutScope(t *testing.T) {
+	err := &api.GraphQLError{Errors: []api.GraphQLErrorItem{{
+		Type:    "INSUFFICIENT_SCOPES",
+		Message: "Your token has not been granted the required scopes to execute this query.",
+	}}}
+	issues := []Issue{{ID: "I_7", Number: 7}}
+	if err := NewIssueClient(&mockQuerier{err: err}, "octo", "hello").LoadProjects(issues); err != nil {
+		t.Fatalf("err = %v, want none", err)
+	}
+	if issues[0].Projects != nil {
+		t.Errorf("projects = %+v, want none", issues[0].Projects)
+	}
+}
+
+func TestProjectFields_KeepsSettableFields(t *testing.T) {
+	canned := map[string]interface{}{
+		"node": map[string]interface{}{
+			"id": "PVT_1", "title": "Roadmap",
+			"fields": map[string]interface{}{
+				"nodes": []map[string]interface{}{
+					{"id": "F0", "name": "Title", "dataType": "TITLE"},
+					{"id": "F1", "name": "Priority", "dataType": "SINGLE_SELECT", "options": []map[string]string{{"id": "o1", "name": "P1", "color": "RED"}}},
+					{"id": "F2", "name": "Iteration", "dataType": "ITERATION", "configuration": map[string]interface{}{"iterations": []map[string]string{{"id": "it3", "title": "Sprint 3"}}}},
+					{"id": "F3", "name": "Estimate", "dataType": "NUMBER"},
+				},
+			},
+		},
+	}
+
+	fields, err := NewProjectClient(&mockQuerier{response: canned}, "octo", "hello").Fields("PVT_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fields) != 3 || fields[0].Name != "Priority" || fields[1].Options[0].Name != "Sprint 3" || fields[2].DataType != "NUMBER" {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+}
+
+func TestProjectSetValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     ProjectField
+		value     string
+		wantInput map[string]interface{}
+		wantText  string
+	}{
+		{"iteration", ProjectField{DataType: "ITERATION", Options: []ProjectFieldOption{{ID: "it3", Name: "Sprint 3"}}}, "it3", map[string]interface{}{"iterationId": "it3"}, "Sprint 3"},
+		{"number", ProjectField{DataType: "NUMBER"}, " 3 ", map[string]interface{}{"number": 3.0}, "3"},
+		{"date", ProjectField{DataType: "DATE"}, "2026-11-01", map[string]interface{}{"date": "2026-11-01"}, "2026-11-01"},
+		{"text", ProjectField{DataType: "TEXT"}, "needs design", map[string]interface{}{"text": "needs design"}, "needs design"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &capturingQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+			v, err := NewProjectClient(q, "octo", "hello").SetValue(tt.field, "PVTI_1", tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			value := q.vars["input"].(map[string]interface{})["value"].(map[string]interface{})
+			for k, want := range tt.wantInput {
+				if value[k] != want {
+					t.Errorf("value[%s] = %v, want %v", k, value[k], want)
+				}
+			}
+			if v.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", v.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestProjectSetValue_RejectsInvalidInput(t *testing.T) {
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	client := NewProjectClient(q, "octo", "hello")
+	for _, field := range []ProjectField{{Name: "Estimate", DataType: "NUMBER"}, {Name: "Due", DataType: "DATE"}, {Name: "Priority", DataType: "SINGLE_SELECT"}} {
+		_, err := client.SetValue(field, "PVTI_1", "soon")
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Kind != ErrValidation {
+			t.Errorf("%s: err = %v, want validation error", field.Name, err)
+		}
+	}
+	if q.vars != nil {
+		t.Errorf("invalid values were sent: %+v", q.vars)
+	}
+}
+
+func TestProjectSetValue_EmptyClears(t *testing.T) {
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: map[string]interface{}{}}}
+	field := ProjectField{ProjectID: "PVT_1", ID: "F3", Name: "Estimate", DataType: "NUMBER"}
+	v, err := NewProjectClient(q, "octo", "hello").SetValue(field, "PVTI_1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	if _, ok := input["value"]; ok || input["fieldId"] != "F3" {
+		t.Errorf("unexpected clear input: %+v", input)
+	}
+	if v.FieldName != "Estimate" || v.Text != "" {
+		t.Errorf("cleared value = %+v", v)
+	}
+}
@@ -0,0 +1,44 @@
+package data
+
+import "time"
+
+// RateLimit describes the GraphQL point budget for the authenticated user.
+type RateLimit struct {
+	Limit     int
+	Cost      int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RateLimitClient reports the current GraphQL rate limit status.
+type RateLimitClient struct {
+	querier Querier
+}
+
+// NewRateLimitClient creates a RateLimitClient.
+func NewRateLimitClient(q Querier) *RateLimitClient {
+	return &RateLimitClient{querier: q}
+}
+
+// Get fetches the current rate limit status. The query itself costs no
+// points.
+func (c *RateLimitClient) Get() (RateLimit, error) {
+	var resp struct {
+		RateLimit graphqlRateLimit `json:"rateLimit"`
+	}
+
+	if err := do(c.querier, rateLimitQuery, nil, &resp); err != nil {
+		return RateLimit{}, err
+	}
+
+	return RateLimit(resp.RateLimit), nil
+}
+
+const rateLimitQuery = `query RateLimit { rateLimit { limit cost remaining resetAt } }`
+
+type graphqlRateLimit struct {
+	Limit     int       `json:"limit"`
+	Cost      int       `json:"cost"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
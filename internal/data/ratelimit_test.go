@@ -0,0 +1,25 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitGet(t *testing.T) {
+	q := &mockQuerier{response: map[string]interface{}{
+		"rateLimit": map[string]interface{}{
+			"limit": 5000, "cost": 1, "remaining": 4321, "resetAt": "2025-01-01T01:00:00Z",
+		},
+	}}
+
+	rl, err := NewRateLimitClient(q).Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rl.Limit != 5000 || rl.Remaining != 4321 {
+		t.Errorf("unexpected rate limit: %+v", rl)
+	}
+	if !rl.ResetAt.Equal(time.Date(2025, time.January, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected reset time: %v", rl.ResetAt)
+	}
+}
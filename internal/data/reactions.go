@@ -0,0 +1,123 @@
+package data
+
+// ReactionContents lists the reactions GitHub supports, in the order it
+// shows them.
+var ReactionContents = []string{"THUMBS_UP", "THUMBS_DOWN", "LAUGH", "HOORAY", "CONFUSED", "HEART", "ROCKET", "EYES"}
+
+var reactionEmoji = map[string]string{
+	"THUMBS_UP":   "👍",
+	"THUMBS_DOWN": "👎",
+	"LAUGH":       "😄",
+	"HOORAY":      "🎉",
+	"CONFUSED":    "😕",
+	"HEART":       "❤️",
+	"ROCKET":      "🚀",
+	"EYES":        "👀",
+}
+
+// ReactionEmoji returns the emoji for a reaction content such as
+// "THUMBS_UP", or the content itself if it is unknown.
+func ReactionEmoji(content string) string {
+	if e, ok := reactionEmoji[content]; ok {
+		return e
+	}
+	return content
+}
+
+// ReactionGroup counts the reactions of one kind on an issue or comment.
+type ReactionGroup struct {
+	Content          string // e.g. "THUMBS_UP"
+	Count            int
+	ViewerHasReacted bool
+}
+
+// ReactionClient adds and removes the viewer's reactions via GraphQL.
+type ReactionClient struct {
+	querier Querier
+}
+
+// NewReactionClient creates a ReactionClient.
+func NewReactionClient(q Querier) *ReactionClient {
+	return &ReactionClient{querier: q}
+}
+
+// Add reacts to the issue or comment with node ID subjectID and returns its
+// updated reaction groups.
+func (c *ReactionClient) Add(subjectID, content string) ([]ReactionGroup, error) {
+	var resp struct {
+		AddReaction struct {
+			Subject reactableNode `json:"subject"`
+		} `json:"addReaction"`
+	}
+	if err := do(c.querier, addReactionMutation, reactionVars(subjectID, content), &resp); err != nil {
+		return nil, err
+	}
+	return resp.AddReaction.Subject.groups(), nil
+}
+
+// Remove withdraws the viewer's reaction from the issue or comment with
+// node ID subjectID and returns its updated reaction groups.
+func (c *ReactionClient) Remove(subjectID, content string) ([]ReactionGroup, error) {
+	var resp struct {
+		RemoveReaction struct {
+			Subject reactableNode `json:"subject"`
+		} `json:"removeReaction"`
+	}
+	if err := do(c.querier, removeReactionMutation, reactionVars(subjectID, content), &resp); err != nil {
+		return nil, err
+	}
+	return resp.RemoveReaction.Subject.groups(), nil
+}
+
+// Toggle removes the viewer's reaction of group's kind if there is one and
+// adds it otherwise.
+func (c *ReactionClient) Toggle(subjectID string, group ReactionGroup) ([]ReactionGroup, error) {
+	if group.ViewerHasReacted {
+		return c.Remove(subjectID, group.Content)
+	}
+	return c.Add(subjectID, group.Content)
+}
+
+func reactionVars(subjectID, content string) map[string]interface{} {
+	return map[string]interface{}{
+		"input": map[string]interface{}{"subjectId": subjectID, "content": content},
+	}
+}
+
+const addReactionMutation = `mutation AddReaction($input: AddReactionInput!) {
+  addReaction(input: $input) {
+    subject { reactionGroups { content viewerHasReacted reactors { totalCount } } }
+  }
+}`
+
+const removeReactionMutation = `mutation RemoveReaction($input: RemoveReactionInput!) {
+  removeReaction(input: $input) {
+    subject { reactionGroups { content viewerHasReacted reactors { totalCount } } }
+  }
+}`
+
+// reactableNode is the reactionGroups selection of an issue or comment.
+type reactableNode struct {
+	ReactionGroups []reactionGroupNode `json:"reactionGroups"`
+}
+
+type reactionGroupNode struct {
+	Content          string `json:"content"`
+	ViewerHasReacted bool   `json:"viewerHasReacted"`
+	Reactors         struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"reactors"`
+}
+
+// groups returns the groups that have reactions. GitHub also lists the
+// kinds nobody has used.
+func (n reactableNode) groups() []ReactionGroup {
+	var groups []ReactionGroup
+	for _, g := range n.ReactionGroups {
+		if g.Reactors.TotalCount == 0 && !g.ViewerHasReacted {
+			continue
+		}
+		groups = append(groups, ReactionGroup{Content: g.Content, Count: g.Reactors.TotalCount, ViewerHasReacted: g.ViewerHasReacted})
+	}
+	return groups
+}
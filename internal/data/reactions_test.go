@@ -0,0 +1,57 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func reactionGroupsJSON(groups ...map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"reactionGroups": groups}
+}
+
+func group(content string, count int, viewer bool) map[string]interface{} {
+	return map[string]interface{}{"content": content, "viewerHasReacted": viewer, "reactors": map[string]int{"totalCount": count}}
+}
+
+func TestReactionToggle(t *testing.T) {
+	canned := map[string]interface{}{
+		"addReaction": map[string]interface{}{"subject": reactionGroupsJSON(
+			group("THUMBS_UP", 2, true), group("THUMBS_DOWN", 0, false), group("HEART", 1, false),
+		)},
+	}
+	q := &capturingQuerier{mockQuerier: mockQuerier{response: canned}}
+	groups, err := NewReactionClient(q).Toggle("I_1", ReactionGroup{Content: "THUMBS_UP", Count: 1})
+	if err != nil {
+		t.Fatalf("Toggle: %v", err)
+	}
+	if len(groups) != 2 || groups[0] != (ReactionGroup{Content: "THUMBS_UP", Count: 2, ViewerHasReacted: true}) || groups[1].Content != "HEART" {
+		t.Errorf("groups = %+v", groups)
+	}
+	input := q.vars["input"].(map[string]interface{})
+	if input["subjectId"] != "I_1" || input["content"] != "THUMBS_UP" {
+		t.Errorf("input = %+v", input)
+	}
+}
+
+func TestReactionToggle_RemovesOwnReaction(t *testing.T) {
+	canned := map[string]interface{}{
+		"removeReaction": map[string]interface{}{"subject": reactionGroupsJSON(group("HEART", 0, false))},
+	}
+	q := &scriptedQuerier{replies: []scriptedReply{{response: canned}}}
+	groups, err := NewReactionClient(q).Toggle("IC_1", ReactionGroup{Content: "HEART", Count: 1, ViewerHasReacted: true})
+	if err != nil || len(groups) != 0 {
+		t.Fatalf("Toggle = %+v, %v", groups, err)
+	}
+	if !strings.Contains(q.queries[0], "removeReaction") {
+		t.Errorf("query = %s", q.queries[0])
+	}
+}
+
+func TestReactionEmoji(t *testing.T) {
+	if got := ReactionEmoji("ROCKET"); got != "🚀" {
+		t.Errorf("ReactionEmoji(ROCKET) = %q", got)
+	}
+	if got := ReactionEmoji("PARTY_PARROT"); got != "PARTY_PARROT" {
+		t.Errorf("ReactionEmoji(unknown) = %q", got)
+	}
+}
@@ -0,0 +1,199 @@
+package data
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReferenceKind says what a Reference points at.
+type ReferenceKind int
+
+const (
+	// RefIssue is an issue or pull request, such as #12 or owner/repo#12,
+	// or a URL of one.
+	RefIssue ReferenceKind = iota
+	// RefMention is a user or organization, such as @octocat.
+	RefMention
+	// RefCommit is a commit SHA, such as a1b2c3d or owner/repo@a1b2c3d.
+	RefCommit
+	// RefURL is any other http or https URL.
+	RefURL
+)
+
+// Reference is something a markdown body links to, written the way GitHub
+// turns into a link.
+type Reference struct {
+	Kind ReferenceKind
+	Text string // as written in the body
+	// Repo is the owner/name of an issue or commit in another repository,
+	// or of an issue URL; empty for the body's own repository.
+	Repo   string
+	Number int    // of an issue
+	Name   string // the login of a mention, or the SHA of a commit
+	URL    string // of a URL, including an issue URL
+}
+
+var (
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+	issuePattern   = regexp.MustCompile(`(?:([A-Za-z0-9][\w.-]*/[\w.-]+))?#(\d+)`)
+	mentionPattern = regexp.MustCompile(`@([A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38})`)
+	commitPattern  = regexp.MustCompile(`(?:([A-Za-z0-9][\w.-]*/[\w.-]+)@)?([0-9a-f]{7,40})`)
+	codeSpan       = regexp.MustCompile("`+[^`]*`+")
+)
+
+// ParseReferences returns the references in a markdown body in the order
+// they first appear, each once. Code blocks and code spans are skipped, as
+// GitHub doesn't link inside them.
+func ParseReferences(body string) []Reference {
+	var refs []Reference
+	seen := map[string]bool{}
+	add := func(r Reference) {
+		if !seen[r.Text] {
+			seen[r.Text] = true
+			refs = append(refs, r)
+		}
+	}
+
+	fence := ""
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		for _, r := range lineReferences(blank(line, codeSpan)) {
+			add(r)
+		}
+	}
+	return refs
+}
+
+// lineReferences finds the references of one line, in order. URLs are
+// found first and blanked, so that their paths and anchors aren't read as
+// other references.
+func lineReferences(line string) []Reference {
+	type found struct {
+		at  int
+		ref Reference
+	}
+	var all []found
+
+	for _, m := range urlPattern.FindAllStringIndex(line, -1) {
+		u := strings.TrimRight(line[m[0]:m[1]], ".,;:!?")
+		all = append(all, found{m[0], urlReference(u)})
+	}
+	line = blank(line, urlPattern)
+
+	for _, m := range issuePattern.FindAllStringSubmatchIndex(line, -1) {
+		if !boundaryBefore(line, m[0]) || !boundaryAfter(line, m[1]) {
+			continue
+		}
+		n, _ := strconv.Atoi(line[m[4]:m[5]])
+		r := Reference{Kind: RefIssue, Text: line[m[0]:m[1]], Number: n}
+		if m[2] >= 0 {
+			r.Repo = line[m[2]:m[3]]
+		}
+		all = append(all, found{m[0], r})
+	}
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(line, -1) {
+		// A slash after the login makes a team mention; a word character
+		// before the @ makes an email address.
+		if !boundaryBefore(line, m[0]) || (m[1] < len(line) && (line[m[1]] == '/' || isWordByte(line[m[1]]))) {
+			continue
+		}
+		all = append(all, found{m[0], Reference{Kind: RefMention, Text: line[m[0]:m[1]], Name: line[m[2]:m[3]]}})
+	}
+	for _, m := range commitPattern.FindAllStringSubmatchIndex(line, -1) {
+		sha := line[m[4]:m[5]]
+		// Hex-only words such as "deadbeef" or dates read as numbers are
+		// not worth linking; GitHub only links SHAs of existing commits.
+		if !boundaryBefore(line, m[0]) || !boundaryAfter(line, m[1]) || !strings.ContainsAny(sha, "0123456789") || !strings.ContainsAny(sha, "abcdef") {
+			continue
+		}
+		r := Reference{Kind: RefCommit, Text: line[m[0]:m[1]], Name: sha}
+		if m[2] >= 0 {
+			r.Repo = line[m[2]:m[3]]
+		}
+		all = append(all, found{m[0], r})
+	}
+
+	slices.SortStableFunc(all, func(a, b found) int { return cmp.Compare(a.at, b.at) })
+	refs := make([]Reference, len(all))
+	for i, f := range all {
+		refs[i] = f.ref
+	}
+	return refs
+}
+
+// urlReference makes an issue reference of a URL to an issue or pull
+// request, and a URL reference of any other.
+func urlReference(u string) Reference {
+	r := Reference{Kind: RefURL, Text: u, URL: u}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return r
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) == 4 && (parts[2] == "issues" || parts[2] == "pull") && parsed.Fragment == "" {
+		if n, err := strconv.Atoi(parts[3]); err == nil {
+			r.Kind, r.Repo, r.Number = RefIssue, parts[0]+"/"+parts[1], n
+		}
+	}
+	return r
+}
+
+// Link returns the URL of the reference on the GitHub host at base, such as
+// https://github.com, for a body in repo (owner/name).
+func (r Reference) Link(base, repo string) string {
+	if r.URL != "" {
+		return r.URL
+	}
+	if r.Repo != "" {
+		repo = r.Repo
+	}
+	switch r.Kind {
+	case RefIssue:
+		return fmt.Sprintf("%s/%s/issues/%d", base, repo, r.Number)
+	case RefMention:
+		return base + "/" + r.Name
+	case RefCommit:
+		return fmt.Sprintf("%s/%s/commit/%s", base, repo, r.Name)
+	}
+	return ""
+}
+
+// blank replaces the matches of pattern in s with spaces, keeping the
+// offsets of the rest.
+func blank(s string, pattern *regexp.Regexp) string {
+	return pattern.ReplaceAllStringFunc(s, func(m string) string { return strings.Repeat(" ", len(m)) })
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// boundaryBefore reports whether a reference may start at i: not within a
+// word, path, or other reference.
+func boundaryBefore(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	b := s[i-1]
+	return !isWordByte(b) && !strings.ContainsRune("/#@.-", rune(b))
+}
+
+// boundaryAfter reports whether a reference may end at i.
+func boundaryAfter(s string, i int) bool {
+	return i == len(s) || !isWordByte(s[i])
+}
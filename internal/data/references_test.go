@@ -0,0 +1,51 @@
+package data
+
+import "testing"
+
+func TestParseReferences(t *testing.T) {
+	body := "Duplicate of #12, see octo/other#4 and https://github.com/octo/hello/issues/7.\n" +
+		"cc @alice and @org/team, not bob@example.com or #12 again.\n" +
+		"Fixed in a1b2c3d and octo/other@0f9e8d7c6b; not deadbeef, 1234567 or issue#3.\n" +
+		"Docs: <https://example.com/a?b=1#top> and `#99 in code`\n" +
+		"```\n@nobody #98\n```\n" +
+		"https://github.com/octo/hello/issues/7#issuecomment-1"
+
+	refs := ParseReferences(body)
+	want := []Reference{
+		{Kind: RefIssue, Text: "#12", Number: 12},
+		{Kind: RefIssue, Text: "octo/other#4", Repo: "octo/other", Number: 4},
+		{Kind: RefIssue, Text: "https://github.com/octo/hello/issues/7", Repo: "octo/hello", Number: 7, URL: "https://github.com/octo/hello/issues/7"},
+		{Kind: RefMention, Text: "@alice", Name: "alice"},
+		{Kind: RefCommit, Text: "a1b2c3d", Name: "a1b2c3d"},
+		{Kind: RefCommit, Text: "octo/other@0f9e8d7c6b", Repo: "octo/other", Name: "0f9e8d7c6b"},
+		{Kind: RefURL, Text: "https://example.com/a?b=1#top", URL: "https://example.com/a?b=1#top"},
+		{Kind: RefURL, Text: "https://github.com/octo/hello/issues/7#issuecomment-1", URL: "https://github.com/octo/hello/issues/7#issuecomment-1"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("refs = %+v\nwant %+v", refs, want)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("ref %d = %+v, want %+v", i, refs[i], want[i])
+		}
+	}
+}
+
+func TestReferenceLink(t *testing.T) {
+	const base, repo = "https://github.com", "octo/hello"
+	tests := []struct {
+		ref  Reference
+		want string
+	}{
+		{Reference{Kind: RefIssue, Number: 12}, "https://github.com/octo/hello/issues/12"},
+		{Reference{Kind: RefIssue, Repo: "octo/other", Number: 4}, "https://github.com/octo/other/issues/4"},
+		{Reference{Kind: RefMention, Name: "alice"}, "https://github.com/alice"},
+		{Reference{Kind: RefCommit, Name: "a1b2c3d"}, "https://github.com/octo/hello/commit/a1b2c3d"},
+		{Reference{Kind: RefURL, URL: "https://example.com"}, "https://example.com"},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.Link(base, repo); got != tt.want {
+			t.Errorf("Link(%+v) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package data
+
+import "fmt"
+
+// repositoryID fetches the GraphQL node ID of a repository, which mutations
+// that create objects in the repository require.
+func repositoryID(q Querier, owner, repo string) (string, error) {
+	var resp struct {
+		Repository *struct {
+			ID string `json:"id"`
+		} `json:"repository"`
+	}
+
+	vars := map[string]interface{}{"owner": owner, "name": repo}
+	if err := do(q, repositoryIDQuery, vars, &resp); err != nil {
+		return "", err
+	}
+	if resp.Repository == nil || resp.Repository.ID == "" {
+		return "", &Error{Kind: ErrNotFound, Resource: "repository", Err: fmt.Errorf("repository %s/%s not found", owner, repo)}
+	}
+
+	return resp.Repository.ID, nil
+}
+
+const repositoryIDQuery = `query RepositoryID($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) { id }
+}`
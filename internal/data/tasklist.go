@@ -0,0 +1,79 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Task is an item of a markdown task list, such as "- [x] Write docs".
+type Task struct {
+	Line    int // zero-based line of the body
+	Text    string
+	Checked bool
+	Depth   int // nesting level, from the item's indentation
+}
+
+// taskPattern matches a list item starting with a checkbox. The third group
+// is the box's mark.
+var taskPattern = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*?)\r?$`)
+
+// ParseTasks returns the task list items of a markdown body, skipping
+// fenced code blocks.
+func ParseTasks(body string) []Task {
+	var tasks []Task
+	fence := ""
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		m := taskPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent := strings.ReplaceAll(m[1], "\t", "    ")
+		tasks = append(tasks, Task{Line: i, Text: m[3], Checked: m[2] != " ", Depth: len(indent) / 2})
+	}
+	return tasks
+}
+
+// ToggleTask returns body with the checkbox of task checked or unchecked,
+// leaving every other byte as it was. The task must come from ParseTasks
+// on the same body.
+func ToggleTask(body string, task Task) (string, error) {
+	lines := strings.Split(body, "\n")
+	if task.Line < 0 || task.Line >= len(lines) {
+		return "", fmt.Errorf("task on line %d is not in the body", task.Line+1)
+	}
+	line := lines[task.Line]
+	m := taskPattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return "", fmt.Errorf("line %d is not a task", task.Line+1)
+	}
+	mark := "x"
+	if line[m[4]:m[5]] != " " {
+		mark = " "
+	}
+	lines[task.Line] = line[:m[4]] + mark + line[m[5]:]
+	return strings.Join(lines, "\n"), nil
+}
+
+// TaskProgress counts the tasks of a body and how many are checked.
+func TaskProgress(body string) (done, total int) {
+	for _, t := range ParseTasks(body) {
+		total++
+		if t.Checked {
+			done++
+		}
+	}
+	return done, total
+}
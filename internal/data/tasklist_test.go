@@ -0,0 +1,59 @@
+package data
+
+import "testing"
+
+const taskBody = "Steps:\r\n" +
+	"- [x] Reproduce\r\n" +
+	"- [ ] Fix the parser\r\n" +
+	"  * [X] Nested item\r\n" +
+	"1. [ ] Numbered\r\n" +
+	"```\r\n" +
+	"- [ ] not a task, in a fence\r\n" +
+	"```\r\n" +
+	"- [] not a task either"
+
+func TestParseTasks(t *testing.T) {
+	tasks := ParseTasks(taskBody)
+	want := []Task{
+		{Line: 1, Text: "Reproduce", Checked: true},
+		{Line: 2, Text: "Fix the parser"},
+		{Line: 3, Text: "Nested item", Checked: true, Depth: 1},
+		{Line: 4, Text: "Numbered"},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("tasks = %+v, want %+v", tasks, want)
+	}
+	for i := range want {
+		if tasks[i] != want[i] {
+			t.Errorf("task %d = %+v, want %+v", i, tasks[i], want[i])
+		}
+	}
+	if done, total := TaskProgress(taskBody); done != 2 || total != 4 {
+		t.Errorf("progress = %d/%d, want 2/4", done, total)
+	}
+}
+
+func TestToggleTask(t *testing.T) {
+	tasks := ParseTasks(taskBody)
+
+	checked, err := ToggleTask(taskBody, tasks[1])
+	if err != nil {
+		t.Fatalf("ToggleTask: %v", err)
+	}
+	want := "Steps:\r\n- [x] Reproduce\r\n- [x] Fix the parser\r\n"
+	if checked[:len(want)] != want || len(checked) != len(taskBody) {
+		t.Errorf("checked body = %q", checked)
+	}
+
+	unchecked, err := ToggleTask(checked, tasks[1])
+	if err != nil {
+		t.Fatalf("ToggleTask: %v", err)
+	}
+	if unchecked != taskBody {
+		t.Errorf("toggling twice changed the body: %q", unchecked)
+	}
+
+	if _, err := ToggleTask(taskBody, Task{Line: 0}); err == nil {
+		t.Error("toggling a line without a task succeeded")
+	}
+}
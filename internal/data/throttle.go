@@ -0,0 +1,265 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// RateLimitQuerier is querier middleware that keeps track of the GraphQL
+// point budget, slows down when the budget runs low, and retries requests
+// that fail transiently.
+//
+// Every query operation is extended with a rateLimit selection so that the
+// budget is known after each request. Mutations cannot select rateLimit, so
+// they only benefit from retries and throttling. A mutation that failed with
+// a server error or timeout may still have been applied, so mutations are
+// retried only when they were rejected for exceeding a rate limit.
+type RateLimitQuerier struct {
+	querier ContextQuerier
+
+	// OnRateLimit, if set, is called with the budget reported by each
+	// query. It may be called from any goroutine.
+	OnRateLimit func(RateLimit)
+
+	// LowWater is the remaining budget below which requests are spread
+	// out over the time left until the budget resets.
+	LowWater int
+	// MaxRetries is the number of times a transiently failing request is
+	// retried.
+	MaxRetries int
+	// BaseDelay and MaxDelay bound the exponential backoff between retries.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+
+	mu     sync.Mutex
+	latest RateLimit
+	known  bool
+}
+
+// NewRateLimitQuerier wraps q with rate limit tracking and retries.
+func NewRateLimitQuerier(q ContextQuerier) *RateLimitQuerier {
+	return &RateLimitQuerier{
+		querier:    q,
+		LowWater:   100,
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+		now:        time.Now,
+		sleep:      sleepContext,
+		jitter:     func(d time.Duration) time.Duration { return rand.N(d + 1) },
+	}
+}
+
+// Do implements Querier.
+func (r *RateLimitQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return r.DoWithContext(context.Background(), query, variables, response)
+}
+
+// DoWithContext implements ContextQuerier.
+func (r *RateLimitQuerier) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	if err := r.throttle(ctx); err != nil {
+		return err
+	}
+
+	mutation := isMutation(query)
+	tracked := query
+	if shouldTrackRateLimit(query) {
+		tracked = withRateLimit(query)
+	}
+
+	for attempt := 0; ; attempt++ {
+		var raw json.RawMessage
+		err := r.querier.DoWithContext(ctx, tracked, variables, &raw)
+		if len(raw) > 0 {
+			r.record(raw)
+			if uerr := json.Unmarshal(raw, response); uerr != nil && err == nil {
+				err = uerr
+			}
+		}
+		if err == nil {
+			return nil
+		}
+
+		delay, ok := r.retryDelay(err, attempt, mutation)
+		if !ok || attempt >= r.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+		if serr := r.sleep(ctx, delay); serr != nil {
+			return serr
+		}
+	}
+}
+
+// throttle delays the next request when the budget is low, spreading the
+// remaining points over the time left until reset. Once the budget is spent
+// it waits for the reset.
+func (r *RateLimitQuerier) throttle(ctx context.Context) error {
+	latest, known := r.Latest()
+	if !known || latest.Remaining >= r.LowWater {
+		return nil
+	}
+
+	untilReset := latest.ResetAt.Sub(r.now())
+	if untilReset <= 0 {
+		return nil
+	}
+
+	delay := untilReset
+	if latest.Remaining > 0 {
+		delay = untilReset / time.Duration(latest.Remaining+1)
+	}
+	return r.sleep(ctx, delay)
+}
+
+// Latest returns the most recently reported budget, and whether any query
+// has reported one yet.
+func (r *RateLimitQuerier) Latest() (RateLimit, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.latest, r.known
+}
+
+// record extracts the rateLimit selection from a raw response.
+func (r *RateLimitQuerier) record(raw json.RawMessage) {
+	var resp struct {
+		RateLimit *graphqlRateLimit `json:"rateLimit"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil || resp.RateLimit == nil {
+		return
+	}
+
+	latest := RateLimit(*resp.RateLimit)
+	r.mu.Lock()
+	r.latest = latest
+	r.known = true
+	r.mu.Unlock()
+
+	if r.OnRateLimit != nil {
+		r.OnRateLimit(latest)
+	}
+}
+
+// retryDelay reports whether err is worth retrying and how long to wait
+// first. Server hints such as Retry-After and the rate limit reset time take
+// precedence over the jittered exponential backoff. Mutations are retried
+// only after explicit rate limit rejections, which guarantee that nothing was
+// written.
+func (r *RateLimitQuerier) retryDelay(err error, attempt int, mutation bool) (time.Duration, bool) {
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusTooManyRequests:
+			if d, ok := retryAfter(httpErr.Headers); ok {
+				return d, true
+			}
+			if httpErr.Headers.Get("X-RateLimit-Remaining") == "0" {
+				if reset, err := strconv.ParseInt(httpErr.Headers.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+					return r.untilReset(time.Unix(reset, 0)), true
+				}
+			}
+			if !mutation && strings.Contains(strings.ToLower(httpErr.Message), "secondary rate limit") {
+				return r.backoff(attempt), true
+			}
+			return 0, false
+		case httpErr.StatusCode >= 500 && !mutation:
+			return r.backoff(attempt), true
+		}
+		return 0, false
+	}
+
+	var gqlErr *api.GraphQLError
+	if errors.As(err, &gqlErr) {
+		for _, e := range gqlErr.Errors {
+			if e.Type == "RATE_LIMITED" || e.Type == "RATE_LIMIT" {
+				if latest, known := r.Latest(); known {
+					return r.untilReset(latest.ResetAt), true
+				}
+				return r.backoff(attempt), true
+			}
+		}
+		return 0, false
+	}
+
+	var netErr net.Error
+	if !mutation && errors.As(err, &netErr) && netErr.Timeout() && !errors.Is(err, context.DeadlineExceeded) {
+		return r.backoff(attempt), true
+	}
+	return 0, false
+}
+
+func (r *RateLimitQuerier) backoff(attempt int) time.Duration {
+	d := r.BaseDelay << attempt
+	if d <= 0 || d > r.MaxDelay {
+		d = r.MaxDelay
+	}
+	return d/2 + r.jitter(d/2)
+}
+
+func (r *RateLimitQuerier) untilReset(resetAt time.Time) time.Duration {
+	d := resetAt.Sub(r.now()) + time.Second
+	if d < r.BaseDelay {
+		d = r.BaseDelay
+	}
+	return d
+}
+
+func retryAfter(h http.Header) (time.Duration, bool) {
+	secs, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// shouldTrackRateLimit reports whether query is a query operation that does
+// not already select rateLimit.
+func shouldTrackRateLimit(query string) bool {
+	q := strings.TrimSpace(query)
+	if !strings.HasPrefix(q, "query") && !strings.HasPrefix(q, "{") {
+		return false
+	}
+	return !strings.Contains(q, "rateLimit")
+}
+
+// isMutation reports whether query is a mutation operation.
+func isMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
+
+// withRateLimit adds a rateLimit selection to the operation's top-level
+// selection set.
+func withRateLimit(query string) string {
+	i := strings.Index(query, "{")
+	if i < 0 {
+		return query
+	}
+	return query[:i+1] + " rateLimit { limit cost remaining resetAt }" + query[i+1:]
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,253 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+type scriptedReply struct {
+	response interface{}
+	err      error
+}
+
+// scriptedQuerier replies to successive requests from a script and records
+// the queries it was sent.
+type scriptedQuerier struct {
+	replies []scriptedReply
+	queries []string
+}
+
+func (s *scriptedQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return s.DoWithContext(context.Background(), query, variables, response)
+}
+
+func (s *scriptedQuerier) DoWithContext(_ context.Context, query string, _ map[string]interface{}, response interface{}) error {
+	s.queries = append(s.queries, query)
+	reply := s.replies[0]
+	if len(s.replies) > 1 {
+		s.replies = s.replies[1:]
+	}
+	if reply.response != nil {
+		b, err := json.Marshal(reply.response)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, response); err != nil {
+			return err
+		}
+	}
+	return reply.err
+}
+
+func newTestRateLimitQuerier(q ContextQuerier, now time.Time) (*RateLimitQuerier, *[]time.Duration) {
+	var sleeps []time.Duration
+	r := NewRateLimitQuerier(q)
+	r.now = func() time.Time { return now }
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	r.jitter = func(d time.Duration) time.Duration { return d }
+	return r, &sleeps
+}
+
+func TestRateLimitQuerier_TracksBudget(t *testing.T) {
+	resetAt := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	inner := &scriptedQuerier{replies: []scriptedReply{{response: map[string]interface{}{
+		"rateLimit":  map[string]interface{}{"limit": 5000, "cost": 1, "remaining": 4999, "resetAt": resetAt},
+		"repository": map[string]interface{}{"id": "R_1"},
+	}}}}
+	r, _ := newTestRateLimitQuerier(inner, resetAt.Add(-time.Hour))
+
+	var reported []RateLimit
+	r.OnRateLimit = func(rl RateLimit) { reported = append(reported, rl) }
+
+	var resp struct {
+		Repository struct {
+			ID string `json:"id"`
+		} `json:"repository"`
+	}
+	if err := r.Do(`query RepositoryID($owner: String!) { repository(owner: $owner) { id } }`, nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Repository.ID != "R_1" {
+		t.Errorf("expected response to be decoded, got %+v", resp)
+	}
+	if !strings.Contains(inner.queries[0], "{ rateLimit { limit cost remaining resetAt } repository") {
+		t.Errorf("expected rateLimit selection in query, got %s", inner.queries[0])
+	}
+	if len(reported) != 1 || reported[0].Remaining != 4999 || !reported[0].ResetAt.Equal(resetAt) {
+		t.Errorf("unexpected reported budget: %+v", reported)
+	}
+}
+
+func TestRateLimitQuerier_LeavesMutationsAlone(t *testing.T) {
+	inner := &scriptedQuerier{replies: []scriptedReply{{response: map[string]interface{}{}}}}
+	r, _ := newTestRateLimitQuerier(inner, time.Now())
+
+	mutation := `mutation CreateLabel($input: CreateLabelInput!) { createLabel(input: $input) { label { id } } }`
+	var resp struct{}
+	if err := r.Do(mutation, nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inner.queries[0] != mutation {
+		t.Errorf("expected mutation to be sent unchanged, got %s", inner.queries[0])
+	}
+}
+
+func TestRateLimitQuerier_RetriesServerErrors(t *testing.T) {
+	inner := &scriptedQuerier{replies: []scriptedReply{
+		{err: &api.HTTPError{StatusCode: http.StatusBadGateway}},
+		{err: &api.HTTPError{StatusCode: http.StatusServiceUnavailable}},
+		{response: map[string]interface{}{}},
+	}}
+	r, sleeps := newTestRateLimitQuerier(inner, time.Now())
+
+	var resp struct{}
+	if err := r.Do("query Q { viewer { login } }", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inner.queries) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(inner.queries))
+	}
+	want := []time.Duration{time.Second, 2 * time.Second}
+	if len(*sleeps) != 2 || (*sleeps)[0] != want[0] || (*sleeps)[1] != want[1] {
+		t.Errorf("expected exponential backoff %v, got %v", want, *sleeps)
+	}
+}
+
+func TestRateLimitQuerier_GivesUpAfterMaxRetries(t *testing.T) {
+	inner := &scriptedQuerier{replies: []scriptedReply{{err: &api.HTTPError{StatusCode: http.StatusBadGateway}}}}
+	r, _ := newTestRateLimitQuerier(inner, time.Now())
+	r.MaxRetries = 2
+
+	var resp struct{}
+	err := r.Do("query Q { viewer { login } }", nil, &resp)
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected final HTTP error, got %v", err)
+	}
+	if len(inner.queries) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(inner.queries))
+	}
+}
+
+func TestRateLimitQuerier_HonorsRetryAfter(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Retry-After", "7")
+	inner := &scriptedQuerier{replies: []scriptedReply{
+		{err: &api.HTTPError{StatusCode: http.StatusForbidden, Message: "You have exceeded a secondary rate limit", Headers: headers}},
+		{response: map[string]interface{}{}},
+	}}
+	r, sleeps := newTestRateLimitQuerier(inner, time.Now())
+
+	var resp struct{}
+	if err := r.Do("query Q { viewer { login } }", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 7*time.Second {
+		t.Errorf("expected to wait 7s, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitQuerier_DoesNotRetryMutationsAfterServerErrors(t *testing.T) {
+	inner := &scriptedQuerier{replies: []scriptedReply{
+		{err: &api.HTTPError{StatusCode: http.StatusBadGateway}},
+		{response: map[string]interface{}{}},
+	}}
+	r, _ := newTestRateLimitQuerier(inner, time.Now())
+
+	var resp struct{}
+	err := r.Do(`mutation AddComment($input: AddCommentInput!) { addComment(input: $input) { clientMutationId } }`, nil, &resp)
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected the 502 to be returned, got %v", err)
+	}
+	if len(inner.queries) != 1 {
+		t.Fatalf("expected the mutation to be attempted once, got %d attempts", len(inner.queries))
+	}
+}
+
+func TestRateLimitQuerier_RetriesRateLimitedMutations(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Retry-After", "3")
+	inner := &scriptedQuerier{replies: []scriptedReply{
+		{err: &api.HTTPError{StatusCode: http.StatusTooManyRequests, Headers: headers}},
+		{err: &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "RATE_LIMITED"}}}},
+		{response: map[string]interface{}{}},
+	}}
+	r, sleeps := newTestRateLimitQuerier(inner, time.Now())
+
+	var resp struct{}
+	if err := r.Do(`mutation AddComment($input: AddCommentInput!) { addComment(input: $input) { clientMutationId } }`, nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inner.queries) != 3 || (*sleeps)[0] != 3*time.Second {
+		t.Errorf("expected two retries after waiting 3s, got %d attempts and sleeps %v", len(inner.queries), *sleeps)
+	}
+}
+
+func TestRateLimitQuerier_DoesNotRetryPermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		&api.HTTPError{StatusCode: http.StatusNotFound},
+		&api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not accessible by integration"},
+		&api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND", Message: "Could not resolve"}}},
+	} {
+		inner := &scriptedQuerier{replies: []scriptedReply{{err: err}}}
+		r, _ := newTestRateLimitQuerier(inner, time.Now())
+
+		var resp struct{}
+		if got := r.Do("query Q { viewer { login } }", nil, &resp); got != err {
+			t.Errorf("expected %v to be returned as is, got %v", err, got)
+		}
+		if len(inner.queries) != 1 {
+			t.Errorf("expected %v not to be retried, got %d attempts", err, len(inner.queries))
+		}
+	}
+}
+
+func TestRateLimitQuerier_ThrottlesWhenBudgetLow(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 11, 0, 0, 0, time.UTC)
+	resetAt := now.Add(10 * time.Minute)
+	inner := &scriptedQuerier{replies: []scriptedReply{{response: map[string]interface{}{
+		"rateLimit": map[string]interface{}{"limit": 5000, "cost": 1, "remaining": 9, "resetAt": resetAt},
+	}}}}
+	r, sleeps := newTestRateLimitQuerier(inner, now)
+
+	var resp struct{}
+	if err := r.Do("query Q { viewer { login } }", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*sleeps) != 0 {
+		t.Fatalf("expected no delay before the budget is known, got %v", *sleeps)
+	}
+
+	if err := r.Do("query Q { viewer { login } }", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != time.Minute {
+		t.Errorf("expected the remaining budget to be spread over the reset window, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitQuerier_StopsOnCancel(t *testing.T) {
+	inner := &scriptedQuerier{replies: []scriptedReply{{err: &api.HTTPError{StatusCode: http.StatusBadGateway}}}}
+	r := NewRateLimitQuerier(inner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var resp struct{}
+	if err := r.DoWithContext(ctx, "query Q { viewer { login } }", nil, &resp); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(inner.queries) != 1 {
+		t.Fatalf("expected no retries after cancellation, got %d attempts", len(inner.queries))
+	}
+}
@@ -0,0 +1,216 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimelineEvent represents a single event in an issue's timeline, such as a
+// label change, assignment, or cross-reference.
+type TimelineEvent struct {
+	Type          string // GraphQL type name, e.g. "LabeledEvent"
+	Actor         string
+	CreatedAt     time.Time
+	Label         *Label
+	Assignee      string
+	Milestone     string
+	StateReason   string
+	PreviousTitle string
+	CurrentTitle  string
+	Source        string // "owner/repo#number" for cross-references
+	SourceTitle   string
+}
+
+// Description returns a short human-readable summary of the event, without
+// the actor or timestamp.
+func (e TimelineEvent) Description() string {
+	label := ""
+	if e.Label != nil {
+		label = e.Label.Name
+	}
+
+	switch e.Type {
+	case "LabeledEvent":
+		return fmt.Sprintf("added label %s", label)
+	case "UnlabeledEvent":
+		return fmt.Sprintf("removed label %s", label)
+	case "AssignedEvent":
+		return fmt.Sprintf("assigned %s", e.Assignee)
+	case "UnassignedEvent":
+		return fmt.Sprintf("unassigned %s", e.Assignee)
+	case "MilestonedEvent":
+		return fmt.Sprintf("added to milestone %s", e.Milestone)
+	case "DemilestonedEvent":
+		return fmt.Sprintf("removed from milestone %s", e.Milestone)
+	case "ClosedEvent":
+		if e.StateReason != "" {
+			return fmt.Sprintf("closed as %s", strings.ToLower(strings.ReplaceAll(e.StateReason, "_", " ")))
+		}
+		return "closed"
+	case "ReopenedEvent":
+		return "reopened"
+	case "RenamedTitleEvent":
+		return fmt.Sprintf("changed the title from %q to %q", e.PreviousTitle, e.CurrentTitle)
+	case "CrossReferencedEvent":
+		return fmt.Sprintf("referenced this from %s", e.Source)
+	default:
+		return e.Type
+	}
+}
+
+// TimelineListResult is the result of listing timeline events.
+type TimelineListResult struct {
+	Events   []TimelineEvent
+	PageInfo PageInfo
+}
+
+// TimelineClient fetches issue timeline events via GraphQL.
+type TimelineClient struct {
+	querier Querier
+	owner   string
+	repo    string
+}
+
+// NewTimelineClient creates a TimelineClient for the given repository.
+func NewTimelineClient(q Querier, owner, repo string) *TimelineClient {
+	return &TimelineClient{querier: q, owner: owner, repo: repo}
+}
+
+// List fetches timeline events for an issue.
+func (c *TimelineClient) List(issueNumber, first int, after string) (TimelineListResult, error) {
+	return c.ListContext(context.Background(), issueNumber, first, after)
+}
+
+// ListContext is like List but can be cancelled through ctx.
+func (c *TimelineClient) ListContext(ctx context.Context, issueNumber, first int, after string) (TimelineListResult, error) {
+	if first == 0 {
+		first = 50
+	}
+
+	vars := map[string]interface{}{
+		"owner":  c.owner,
+		"name":   c.repo,
+		"number": issueNumber,
+		"first":  first,
+	}
+	if after != "" {
+		vars["after"] = after
+	}
+
+	var resp listTimelineResponse
+	err := doContext(ctx, c.querier, listTimelineQuery, vars, &resp)
+	if err != nil && !IsPartialData(err) {
+		return TimelineListResult{}, err
+	}
+
+	return resp.toResult(), err
+}
+
+const listTimelineQuery = `query ListTimeline($owner: String!, $name: String!, $number: Int!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    issue(number: $number) {
+      timelineItems(first: $first, after: $after, itemTypes: [LABELED_EVENT, UNLABELED_EVENT, ASSIGNED_EVENT, UNASSIGNED_EVENT, MILESTONED_EVENT, DEMILESTONED_EVENT, CLOSED_EVENT, REOPENED_EVENT, RENAMED_TITLE_EVENT, CROSS_REFERENCED_EVENT]) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          __typename
+          ... on LabeledEvent { createdAt actor { login } label { name color } }
+          ... on UnlabeledEvent { createdAt actor { login } label { name color } }
+          ... on AssignedEvent { createdAt actor { login } assignee { ... on User { login } } }
+          ... on UnassignedEvent { createdAt actor { login } assignee { ... on User { login } } }
+          ... on MilestonedEvent { createdAt actor { login } milestoneTitle }
+          ... on DemilestonedEvent { createdAt actor { login } milestoneTitle }
+          ... on ClosedEvent { createdAt actor { login } stateReason }
+          ... on ReopenedEvent { createdAt actor { login } }
+          ... on RenamedTitleEvent { createdAt actor { login } previousTitle currentTitle }
+          ... on CrossReferencedEvent {
+            createdAt
+            actor { login }
+            source {
+              ... on Issue { number title repository { nameWithOwner } }
+              ... on PullRequest { number title repository { nameWithOwner } }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type listTimelineResponse struct {
+	Repository struct {
+		Issue struct {
+			TimelineItems struct {
+				PageInfo graphqlPageInfo `json:"pageInfo"`
+				Nodes    []timelineNode  `json:"nodes"`
+			} `json:"timelineItems"`
+		} `json:"issue"`
+	} `json:"repository"`
+}
+
+func (r *listTimelineResponse) toResult() TimelineListResult {
+	nodes := r.Repository.Issue.TimelineItems.Nodes
+	events := make([]TimelineEvent, len(nodes))
+	for i, n := range nodes {
+		events[i] = n.toEvent()
+	}
+	return TimelineListResult{
+		Events:   events,
+		PageInfo: PageInfo(r.Repository.Issue.TimelineItems.PageInfo),
+	}
+}
+
+type timelineNode struct {
+	Typename  string    `json:"__typename"`
+	CreatedAt time.Time `json:"createdAt"`
+	Actor     *struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	Label *struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"label"`
+	Assignee *struct {
+		Login string `json:"login"`
+	} `json:"assignee"`
+	MilestoneTitle string `json:"milestoneTitle"`
+	StateReason    string `json:"stateReason"`
+	PreviousTitle  string `json:"previousTitle"`
+	CurrentTitle   string `json:"currentTitle"`
+	Source         *struct {
+		Number     int    `json:"number"`
+		Title      string `json:"title"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	} `json:"source"`
+}
+
+func (n *timelineNode) toEvent() TimelineEvent {
+	actor := "[deleted]"
+	if n.Actor != nil && n.Actor.Login != "" {
+		actor = n.Actor.Login
+	}
+
+	e := TimelineEvent{
+		Type:          n.Typename,
+		Actor:         actor,
+		CreatedAt:     n.CreatedAt,
+		Milestone:     n.MilestoneTitle,
+		StateReason:   n.StateReason,
+		PreviousTitle: n.PreviousTitle,
+		CurrentTitle:  n.CurrentTitle,
+	}
+	if n.Label != nil {
+		e.Label = &Label{Name: n.Label.Name, Color: n.Label.Color}
+	}
+	if n.Assignee != nil {
+		e.Assignee = n.Assignee.Login
+	}
+	if n.Source != nil && n.Source.Number != 0 {
+		e.Source = fmt.Sprintf("%s#%d", n.Source.Repository.NameWithOwner, n.Source.Number)
+		e.SourceTitle = n.Source.Title
+	}
+	return e
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTimelineList_Events(t *testing.T) {
+	canned := map[string]interface{}{
+		"repository": map[string]interface{}{
+			"issue": map[string]interface{}{
+				"timelineItems": map[string]interface{}{
+					"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "t2"},
+					"nodes": []map[string]interface{}{
+						{
+							"__typename": "LabeledEvent",
+							"createdAt":  "2025-01-01T00:00:00Z",
+							"actor":      map[string]string{"login": "alice"},
+							"label":      map[string]string{"name": "bug", "color": "d73a4a"},
+						},
+						{
+							"__typename":  "ClosedEvent",
+							"createdAt":   "2025-01-02T00:00:00Z",
+							"actor":       nil,
+							"stateReason": "NOT_PLANNED",
+						},
+						{
+							"__typename": "CrossReferencedEvent",
+							"createdAt":  "2025-01-03T00:00:00Z",
+							"actor":      map[string]string{"login": "bob"},
+							"source": map[string]interface{}{
+								"number":     9,
+								"title":      "Fix crash",
+								"repository": map[string]string{"nameWithOwner": "octo/other"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	client := NewTimelineClient(&mockQuerier{response: canned}, "owner", "repo")
+	result, err := client.List(1, 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(result.Events))
+	}
+	if !result.PageInfo.HasNextPage || result.PageInfo.EndCursor != "t2" {
+		t.Errorf("unexpected page info: %+v", result.PageInfo)
+	}
+
+	want := []struct{ actor, desc string }{
+		{"alice", "added label bug"},
+		{"[deleted]", "closed as not planned"},
+		{"bob", "referenced this from octo/other#9"},
+	}
+	for i, w := range want {
+		e := result.Events[i]
+		if e.Actor != w.actor || e.Description() != w.desc {
+			t.Errorf("event %d: got actor=%q desc=%q, want actor=%q desc=%q", i, e.Actor, e.Description(), w.actor, w.desc)
+		}
+	}
+}
+
+func TestTimelineList_Error(t *testing.T) {
+	client := NewTimelineClient(&mockQuerier{err: errors.New("boom")}, "owner", "repo")
+	if _, err := client.List(1, 10, ""); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
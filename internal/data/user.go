@@ -1,5 +1,7 @@
 package data
 
+import "fmt"
+
 // UserClient resolves user-related data via GraphQL.
 type UserClient struct {
 	querier Querier
@@ -18,9 +20,28 @@ func (c *UserClient) WhoAmI() (string, error) {
 		} `json:"viewer"`
 	}
 
-	if err := c.querier.Do("query { viewer { login } }", nil, &resp); err != nil {
+	if err := do(c.querier, "query { viewer { login } }", nil, &resp); err != nil {
 		return "", err
 	}
 
 	return resp.Viewer.Login, nil
 }
+
+// ID returns the node ID of the user with the given login.
+func (c *UserClient) ID(login string) (string, error) {
+	var resp struct {
+		User *struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+
+	vars := map[string]interface{}{"login": login}
+	if err := do(c.querier, "query UserID($login: String!) { user(login: $login) { id } }", vars, &resp); err != nil {
+		return "", err
+	}
+	if resp.User == nil || resp.User.ID == "" {
+		return "", &Error{Kind: ErrNotFound, Resource: "user", Err: fmt.Errorf("user %q not found", login)}
+	}
+
+	return resp.User.ID, nil
+}
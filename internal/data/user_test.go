@@ -29,3 +29,23 @@ func TestWhoAmI_Error(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestUserID(t *testing.T) {
+	client := NewUserClient(&mockQuerier{response: map[string]interface{}{
+		"user": map[string]string{"id": "U1"},
+	}})
+	id, err := client.ID("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "U1" {
+		t.Errorf("expected U1, got %s", id)
+	}
+}
+
+func TestUserID_NotFound(t *testing.T) {
+	client := NewUserClient(&mockQuerier{response: map[string]interface{}{"user": nil}})
+	if _, err := client.ID("ghost"); err == nil {
+		t.Fatal("expected error for unknown user")
+	}
+}
@@ -0,0 +1,151 @@
+package fakegithub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+func (r *Repo) commentJSON(i *Issue, c *Comment) obj {
+	viewer := r.store.viewer
+	maintainer := r.isMaintainer(viewer)
+	var reason interface{}
+	if c.MinimizedReason != "" {
+		reason = strings.ToLower(strings.ReplaceAll(c.MinimizedReason, "_", "-"))
+	}
+	return obj{
+		"id":                c.ID,
+		"url":               r.issueURL(i) + "#issuecomment-" + strings.TrimPrefix(c.ID, "IC_"),
+		"author":            actorJSON(c.Author),
+		"body":              c.Body,
+		"createdAt":         c.CreatedAt,
+		"updatedAt":         c.UpdatedAt,
+		"viewerDidAuthor":   c.Author == viewer,
+		"viewerCanUpdate":   c.Author == viewer || maintainer,
+		"viewerCanDelete":   c.Author == viewer || maintainer,
+		"viewerCanMinimize": maintainer,
+		"isMinimized":       c.MinimizedReason != "",
+		"minimizedReason":   reason,
+		"reactions":         obj{"totalCount": len(c.Reactions)},
+		"reactionGroups":    r.store.reactionGroupsJSON(c.Reactions),
+	}
+}
+
+// isMaintainer reports whether login may moderate the repository.
+func (r *Repo) isMaintainer(login string) bool {
+	return strings.EqualFold(login, r.Owner) || slices.ContainsFunc(r.Maintainers, func(m string) bool {
+		return strings.EqualFold(m, login)
+	})
+}
+
+// issueByID returns the issue with the given node ID and its repository.
+func (s *Store) issueByID(id string) (*Repo, *Issue) {
+	for _, r := range s.repos {
+		for _, i := range r.Issues {
+			if i.ID == id {
+				return r, i
+			}
+		}
+	}
+	return nil, nil
+}
+
+// commentByID returns the comment with the given node ID, its issue, and
+// its repository.
+func (s *Store) commentByID(id string) (*Repo, *Issue, *Comment) {
+	for _, r := range s.repos {
+		for _, i := range r.Issues {
+			for _, c := range i.Comments {
+				if c.ID == id {
+					return r, i, c
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+func resolveAddComment(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r, i := s.issueByID(in.string("subjectId"))
+	if i == nil {
+		return obj{"addComment": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("subjectId")), "addComment")
+	}
+	if strings.TrimSpace(in.string("body")) == "" {
+		return obj{"addComment": nil}, unprocessable("Body can't be blank", "addComment")
+	}
+
+	now := s.now()
+	c := &Comment{ID: s.newID("IC"), Author: s.viewer, Body: in.string("body"), CreatedAt: now, UpdatedAt: now}
+	i.Comments = append(i.Comments, c)
+	i.UpdatedAt = now
+	return obj{"addComment": obj{"commentEdge": obj{"node": r.commentJSON(i, c)}}}, nil
+}
+
+func resolveUpdateIssueComment(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r, i, c, errs := s.editableComment(in.string("id"), false, "updateIssueComment")
+	if c == nil {
+		return obj{"updateIssueComment": nil}, errs
+	}
+	if strings.TrimSpace(in.string("body")) == "" {
+		return obj{"updateIssueComment": nil}, unprocessable("Body can't be blank", "updateIssueComment")
+	}
+
+	c.Body = in.string("body")
+	c.UpdatedAt = s.now()
+	return obj{"updateIssueComment": obj{"issueComment": r.commentJSON(i, c)}}, nil
+}
+
+func resolveDeleteIssueComment(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	_, i, c, errs := s.editableComment(in.string("id"), false, "deleteIssueComment")
+	if c == nil {
+		return obj{"deleteIssueComment": nil}, errs
+	}
+
+	i.Comments = slices.DeleteFunc(i.Comments, func(other *Comment) bool { return other == c })
+	return obj{"deleteIssueComment": obj{"clientMutationId": nil}}, nil
+}
+
+func resolveMinimizeComment(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r, i, c, errs := s.editableComment(in.string("subjectId"), true, "minimizeComment")
+	if c == nil {
+		return obj{"minimizeComment": nil}, errs
+	}
+	classifier := in.string("classifier")
+	if !slices.Contains(minimizeClassifiers, classifier) {
+		return obj{"minimizeComment": nil}, unprocessable(fmt.Sprintf("Unknown classifier %q", classifier), "minimizeComment")
+	}
+
+	c.MinimizedReason = classifier
+	return obj{"minimizeComment": obj{"minimizedComment": r.commentJSON(i, c)}}, nil
+}
+
+func resolveUnminimizeComment(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r, i, c, errs := s.editableComment(in.string("subjectId"), true, "unminimizeComment")
+	if c == nil {
+		return obj{"unminimizeComment": nil}, errs
+	}
+
+	c.MinimizedReason = ""
+	return obj{"unminimizeComment": obj{"unminimizedComment": r.commentJSON(i, c)}}, nil
+}
+
+var minimizeClassifiers = []string{"SPAM", "ABUSE", "OFF_TOPIC", "OUTDATED", "DUPLICATE", "RESOLVED"}
+
+// editableComment looks up a comment that the viewer may change, reporting
+// errors under field. Authors may change their own comments unless
+// moderate is set; maintainers may change any.
+func (s *Store) editableComment(id string, moderate bool, field string) (*Repo, *Issue, *Comment, []gqlError) {
+	r, i, c := s.commentByID(id)
+	if c == nil {
+		return nil, nil, nil, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id), field)
+	}
+	if !r.isMaintainer(s.viewer) && (moderate || c.Author != s.viewer) {
+		return nil, nil, nil, []gqlError{{Type: "FORBIDDEN", Message: fmt.Sprintf("%s does not have the correct permissions to execute `%s`", s.viewer, field), Path: []interface{}{field}}}
+	}
+	return r, i, c, nil
+}
@@ -0,0 +1,294 @@
+package fakegithub
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// DemoOwner and DemoName name the repository created by NewDemoStore.
+const (
+	DemoOwner = "gh-problemas"
+	DemoName  = "demo"
+)
+
+// DemoIssueCount is the number of issues in the demo repository.
+const DemoIssueCount = 320
+
+var demoUsers = []string{
+	"octocat", "mona", "hubot", "ada-l", "grace-h", "linus-t", "margaret-h",
+	"ken-t", "barbara-l", "dennis-r", "frances-a", "edsger-d", "radia-p",
+}
+
+var demoLabels = []struct{ name, color string }{
+	{"bug", "d73a4a"},
+	{"enhancement", "a2eeef"},
+	{"documentation", "0075ca"},
+	{"good first issue", "7057ff"},
+	{"help wanted", "008672"},
+	{"question", "d876e3"},
+	{"duplicate", "cfd3d7"},
+	{"wontfix", "ffffff"},
+	{"performance", "fbca04"},
+	{"security", "b60205"},
+	{"area/ui", "c5def5"},
+	{"area/api", "bfdadc"},
+	{"area/cli", "d4c5f9"},
+	{"priority/high", "e11d21"},
+	{"priority/low", "0e8a16"},
+}
+
+var demoIssueTypes = []struct{ name, color, description string }{
+	{"Bug", "RED", "An unexpected problem or behavior"},
+	{"Feature", "BLUE", "A request, idea, or new functionality"},
+	{"Task", "YELLOW", "A specific piece of work"},
+}
+
+var demoMilestones = []string{"v1.0", "v1.1", "v2.0"}
+
+var (
+	demoSubjects = []string{
+		"dashboard", "detail view", "status bar", "search", "label picker",
+		"pagination", "config loader", "markdown renderer", "export", "import",
+		"rate limiter", "keyboard shortcuts", "theme", "timeline", "comment editor",
+	}
+	demoProblems = []string{
+		"crashes when there are no %s", "flickers while %s are loading",
+		"ignores %s on narrow terminals", "is slow with over 1000 %s",
+		"shows stale %s after refresh", "truncates %s in the middle of a word",
+		"drops %s on resize", "renders %s with the wrong colors",
+	}
+	demoRequests = []string{
+		"Show %s in the %s", "Support %s in the %s", "Allow sorting the %[2]s by %[1]s",
+		"Remember hidden %s in the %s", "Add a setting for %s in the %s",
+	}
+	demoFeatures = []string{
+		"milestones", "assignees", "emoji", "reaction counts", "relative dates",
+		"long titles", "issue types", "draft issues", "CJK characters",
+	}
+)
+
+// NewDemoStore creates a store with one generated repository, DemoOwner/
+// DemoName, holding DemoIssueCount issues with labels, milestones,
+// markdown bodies, comment threads, and timeline events. The data is the
+// same for every call; timestamps are spread over the year before now, and
+// objects created later are stamped with the wall clock.
+func NewDemoStore(now time.Time) *Store {
+	s := NewStore()
+	offset := now.Sub(time.Now())
+	s.SetClock(func() time.Time { return time.Now().Add(offset) })
+	for _, login := range demoUsers {
+		s.AddUser(login)
+	}
+	s.SetViewer("octocat")
+
+	r := s.AddRepo(DemoOwner, DemoName)
+	r.Maintainers = []string{"octocat"}
+	for _, l := range demoLabels {
+		r.AddLabel(l.name, l.color)
+	}
+	for _, title := range demoMilestones {
+		r.AddMilestone(title)
+	}
+	r.Milestones[0].State = "CLOSED"
+	for _, t := range demoIssueTypes {
+		s.AddIssueType(DemoOwner, t.name, t.color, t.description)
+	}
+
+	rng := rand.New(rand.NewPCG(2025, 1))
+	// Who reacted with what comes from a stream of its own, so that the
+	// rest of the demo data does not depend on it.
+	reactions := rand.New(rand.NewPCG(2025, 2))
+	start := now.Add(-365 * 24 * time.Hour)
+	step := 365 * 24 * time.Hour / DemoIssueCount
+
+	for n := 1; n <= DemoIssueCount; n++ {
+		created := start.Add(time.Duration(n-1)*step + time.Duration(rng.Int64N(int64(step))))
+		issue := demoIssue(rng, reactions, n, created)
+		r.AddIssue(issue)
+		demoThread(rng, reactions, r, n, issue, created, now)
+	}
+	return s
+}
+
+func demoIssue(rng, reactions *rand.Rand, n int, created time.Time) Issue {
+	subject := pick(rng, demoSubjects)
+	issue := Issue{
+		Number:    n,
+		Author:    pick(rng, demoUsers),
+		CreatedAt: created,
+		UpdatedAt: created,
+		Reactions: demoReactions(reactions, rng.IntN(3)*rng.IntN(12)),
+	}
+
+	switch kind := rng.IntN(10); {
+	case kind < 5:
+		issue.Title = strings.ToUpper(subject[:1]) + subject[1:] + " " + fmt.Sprintf(pick(rng, demoProblems), pick(rng, demoFeatures))
+		issue.Labels = []string{"bug"}
+		issue.Type = "Bug"
+		issue.Body = demoBugBody(rng, subject)
+	case kind < 8:
+		issue.Title = fmt.Sprintf(pick(rng, demoRequests), pick(rng, demoFeatures), subject)
+		issue.Labels = []string{"enhancement"}
+		issue.Type = "Feature"
+		issue.Body = demoFeatureBody(rng, subject)
+	default:
+		issue.Title = "Document how the " + subject + " handles " + pick(rng, demoFeatures)
+		issue.Labels = []string{"documentation"}
+		issue.Type = "Task"
+		issue.Body = demoDocsBody(rng, subject)
+	}
+
+	for _, extra := range []string{"good first issue", "help wanted", "performance", "security", "priority/high", "priority/low"} {
+		if rng.IntN(8) == 0 {
+			issue.Labels = append(issue.Labels, extra)
+		}
+	}
+	issue.Labels = append(issue.Labels, pick(rng, []string{"area/ui", "area/api", "area/cli"}))
+
+	if rng.IntN(3) > 0 {
+		issue.Assignees = []string{pick(rng, demoUsers)}
+		if rng.IntN(5) == 0 {
+			issue.Assignees = append(issue.Assignees, pick(rng, demoUsers))
+		}
+	}
+	if rng.IntN(2) == 0 {
+		issue.Milestone = pick(rng, demoMilestones)
+	}
+	if rng.IntN(4) == 0 {
+		issue.State = "CLOSED"
+	}
+	if n%97 == 0 {
+		issue.Author = Ghost
+	}
+	return issue
+}
+
+// demoThread adds comments and timeline events to issue n and moves its
+// update time to the latest of them.
+func demoThread(rng, reactions *rand.Rand, r *Repo, n int, issue Issue, created, now time.Time) {
+	at := created
+	later := func() time.Time {
+		at = at.Add(time.Duration(rng.Int64N(int64(72 * time.Hour))))
+		if at.After(now) {
+			at = now
+		}
+		return at
+	}
+
+	for _, label := range issue.Labels {
+		r.AddEvent(n, Event{Type: "LabeledEvent", Actor: pick(rng, demoUsers), Label: label, CreatedAt: later()})
+	}
+	for _, login := range issue.Assignees {
+		r.AddEvent(n, Event{Type: "AssignedEvent", Actor: pick(rng, demoUsers), Assignee: login, CreatedAt: later()})
+	}
+	if issue.Milestone != "" {
+		r.AddEvent(n, Event{Type: "MilestonedEvent", Actor: pick(rng, demoUsers), Milestone: issue.Milestone, CreatedAt: later()})
+	}
+
+	comments := rng.IntN(4) * rng.IntN(4)
+	for c := 0; c < comments; c++ {
+		r.AddComment(n, Comment{
+			Author:    pick(rng, demoUsers),
+			Body:      demoComment(rng, c),
+			CreatedAt: later(),
+			Reactions: demoReactions(reactions, rng.IntN(2)*rng.IntN(6)),
+		})
+	}
+
+	if issue.State == "CLOSED" {
+		reason := "COMPLETED"
+		if rng.IntN(4) == 0 {
+			reason = "NOT_PLANNED"
+		}
+		r.AddEvent(n, Event{Type: "ClosedEvent", Actor: pick(rng, demoUsers), StateReason: reason, CreatedAt: later()})
+	}
+
+	i := r.Issue(n)
+	r.store.mu.Lock()
+	i.UpdatedAt = at
+	r.store.mu.Unlock()
+}
+
+func demoBugBody(rng *rand.Rand, subject string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Description\n\nThe %s misbehaves after upgrading to **v1.%d.%d**. ", subject, rng.IntN(9), rng.IntN(20))
+	b.WriteString("It happens every time on my machine, and a teammate sees it too 😕\n\n")
+	b.WriteString("## Steps to reproduce\n\n1. Run `gh problemas` in a repository with many issues\n")
+	fmt.Fprintf(&b, "2. Open the %s\n3. Resize the terminal to about %d columns\n4. See the error\n\n", subject, 60+rng.IntN(80))
+	b.WriteString("## Logs\n\n```text\n")
+	fmt.Fprintf(&b, "panic: runtime error: index out of range [%d] with length %d\n\n", rng.IntN(50)+1, rng.IntN(10))
+	b.WriteString("goroutine 1 [running]:\n")
+	fmt.Fprintf(&b, "github.com/cboone/gh-problemas/internal/ui/views.renderRow(0x%x, ...)\n", 0xc000010000+rng.IntN(0xffff))
+	b.WriteString("```\n\n")
+	b.WriteString("## Environment\n\n| Component | Version |\n| --- | --- |\n")
+	fmt.Fprintf(&b, "| OS | %s |\n| Terminal | %s |\n| gh | 2.%d.0 |\n\n",
+		pick(rng, []string{"macOS 15.1", "Ubuntu 24.04", "Windows 11", "Fedora 41"}),
+		pick(rng, []string{"iTerm2", "Ghostty", "Windows Terminal", "kitty", "Alacritty"}),
+		40+rng.IntN(30))
+	if rng.IntN(2) == 0 {
+		b.WriteString("> [!NOTE]\n> Setting `NO_COLOR=1` makes it go away.\n")
+	}
+	return b.String()
+}
+
+func demoFeatureBody(rng *rand.Rand, subject string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "It would be great if the %s could do a little more ✨\n\n", subject)
+	b.WriteString("### Proposal\n\n- [ ] Add a config option\n- [ ] Wire up a key binding\n- [x] Agree on the design\n\n")
+	b.WriteString("```yaml\n# ~/.config/gh-problemas/config.yml\n")
+	fmt.Fprintf(&b, "%s:\n  enabled: true\n  limit: %d\n```\n\n", strings.ReplaceAll(subject, " ", "_"), 10*(1+rng.IntN(10)))
+	b.WriteString("### Alternatives\n\nUsing `gh issue list` with `--json` and `jq`, which works but is clunky:\n\n")
+	b.WriteString("```sh\ngh issue list --json number,title | jq -r '.[] | \"#\\(.number) \\(.title)\"'\n```\n")
+	if rng.IntN(3) == 0 {
+		b.WriteString("\nRelated to #" + fmt.Sprint(1+rng.IntN(DemoIssueCount)) + " 🙏\n")
+	}
+	return b.String()
+}
+
+func demoDocsBody(rng *rand.Rand, subject string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The README doesn't explain how the %s works. A short section with an example would help newcomers 📚\n\n", subject)
+	b.WriteString("Things to cover:\n\n* default behavior\n* configuration keys\n* known limitations\n\n")
+	b.WriteString("| Key | Default | Description |\n| --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| `page_size` | `%d` | Issues per page |\n| `date_format` | `relative` | How dates are shown |\n", 25*(1+rng.IntN(4)))
+	return b.String()
+}
+
+var demoReplies = []string{
+	"I can reproduce this on main.",
+	"Thanks for the report! Could you share your config file?",
+	"This looks like a duplicate, but the stack trace is different 🤔",
+	"I'd be happy to take this one.",
+	"Fixed in the latest release, please try again 🎉",
+	"+1, this bites me every day.",
+	"Here's a minimal reproduction:\n\n```go\npackage main\n\nfunc main() {\n\tvar items []string\n\t_ = items[0]\n}\n```",
+	"> It happens every time on my machine\n\nSame here with a 4K monitor and a tiny font.",
+	"Could this be related to the terminal width? Mine is only 80 columns.",
+	"Closing as completed, feel free to reopen if it comes back.",
+}
+
+func demoComment(rng *rand.Rand, index int) string {
+	if index == 0 && rng.IntN(3) == 0 {
+		return demoReplies[1]
+	}
+	return pick(rng, demoReplies)
+}
+
+// demoReactions returns n reactions, mostly thumbs up, from distinct users.
+func demoReactions(rng *rand.Rand, n int) []Reaction {
+	var reactions []Reaction
+	for _, i := range rng.Perm(len(demoUsers))[:min(n, len(demoUsers))] {
+		content := "THUMBS_UP"
+		if rng.IntN(3) == 0 {
+			content = reactionContents[rng.IntN(len(reactionContents))]
+		}
+		reactions = append(reactions, Reaction{Content: content, User: demoUsers[i]})
+	}
+	return reactions
+}
+
+func pick(rng *rand.Rand, list []string) string {
+	return list[rng.IntN(len(list))]
+}
@@ -0,0 +1,68 @@
+package fakegithub
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+)
+
+func TestNewDemoStore(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	store := NewDemoStore(now)
+	repo := store.Repo(DemoOwner, DemoName)
+	if repo == nil || len(repo.Issues) != DemoIssueCount {
+		t.Fatalf("demo repo = %+v", repo)
+	}
+
+	var comments, closed int
+	var table, code, emoji bool
+	for _, issue := range repo.Issues {
+		if issue.UpdatedAt.After(now) || issue.CreatedAt.Before(now.AddDate(-1, 0, 0)) {
+			t.Errorf("#%d has timestamps outside the last year: %v, %v", issue.Number, issue.CreatedAt, issue.UpdatedAt)
+		}
+		if len(issue.Labels) == 0 {
+			t.Errorf("#%d has no labels", issue.Number)
+		}
+		comments += len(issue.Comments)
+		if issue.State == "CLOSED" {
+			closed++
+		}
+		table = table || strings.Contains(issue.Body, "| --- |")
+		code = code || strings.Contains(issue.Body, "```")
+		emoji = emoji || strings.ContainsAny(issue.Body, "✨📚😕🙏")
+	}
+	if comments == 0 || closed == 0 || !table || !code || !emoji {
+		t.Errorf("comments = %d, closed = %d, table = %v, code = %v, emoji = %v", comments, closed, table, code, emoji)
+	}
+
+	again := NewDemoStore(now).Repo(DemoOwner, DemoName)
+	if !reflect.DeepEqual(repo.Issues[41], again.Issues[41]) {
+		t.Error("demo data differs between calls")
+	}
+}
+
+func TestNewDemoStore_Mutations(t *testing.T) {
+	srv := NewServer(NewDemoStore(time.Now()))
+	issues := data.NewIssueClient(srv, DemoOwner, DemoName)
+
+	created, err := issues.Create(data.IssueCreateInput{Title: "Try the demo"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.Number != DemoIssueCount+1 {
+		t.Errorf("number = %d", created.Number)
+	}
+
+	page, err := issues.List(data.IssueListOptions{First: 1, OrderBy: data.IssueOrder{Field: "CREATED_AT", Direction: "DESC"}})
+	if err != nil || len(page.Issues) != 1 || page.Issues[0].Number != created.Number {
+		t.Fatalf("newest issue = %+v, %v", page.Issues, err)
+	}
+
+	milestone, err := data.NewMilestoneClient(srv, srv.LocalREST(), DemoOwner, DemoName).Create("v1.0")
+	if err == nil {
+		t.Fatalf("expected duplicate milestone to fail, got %+v", milestone)
+	}
+}
@@ -0,0 +1,81 @@
+package fakegithub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueType is an issue type of an organization. Color is one of GitHub's
+// named colors, such as "RED".
+type IssueType struct {
+	ID          string
+	Owner       string
+	Name        string
+	Color       string
+	Description string
+}
+
+// AddIssueType adds an issue type to the organization owner, available to
+// the issues of its repositories.
+func (s *Store) AddIssueType(owner, name, color, description string) *IssueType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &IssueType{ID: s.newID("IT"), Owner: owner, Name: name, Color: color, Description: description}
+	s.issueTypes = append(s.issueTypes, t)
+	return t
+}
+
+// ownerIssueTypes returns the issue types of owner.
+func (s *Store) ownerIssueTypes(owner string) []*IssueType {
+	var types []*IssueType
+	for _, t := range s.issueTypes {
+		if strings.EqualFold(t.Owner, owner) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// issueTypeJSON serves owner's type with the given name, or null.
+func (s *Store) issueTypeJSON(owner, name string) interface{} {
+	for _, t := range s.ownerIssueTypes(owner) {
+		if strings.EqualFold(t.Name, name) {
+			return obj{"id": t.ID, "name": t.Name, "color": t.Color, "description": t.Description}
+		}
+	}
+	return nil
+}
+
+func resolveListIssueTypes(s *Store, v variables) (obj, []gqlError) {
+	r, errs := s.repository(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+	nodes := []obj{}
+	for _, t := range s.ownerIssueTypes(r.Owner) {
+		nodes = append(nodes, obj{"id": t.ID, "name": t.Name, "color": t.Color, "description": t.Description})
+	}
+	return obj{"repository": obj{"issueTypes": obj{"nodes": nodes}}}, nil
+}
+
+func resolveUpdateIssueIssueType(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r, i := s.issueByID(in.string("issueId"))
+	if i == nil {
+		return obj{"updateIssueIssueType": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("issueId")), "updateIssueIssueType")
+	}
+	typeID := in.string("issueTypeId")
+	if typeID == "" {
+		i.Type = ""
+		i.UpdatedAt = s.now()
+		return obj{"updateIssueIssueType": obj{"issue": r.issueJSON(i)}}, nil
+	}
+	for _, t := range s.ownerIssueTypes(r.Owner) {
+		if t.ID == typeID {
+			i.Type = t.Name
+			i.UpdatedAt = s.now()
+			return obj{"updateIssueIssueType": obj{"issue": r.issueJSON(i)}}, nil
+		}
+	}
+	return obj{"updateIssueIssueType": nil}, notFound(fmt.Sprintf("Could not resolve to an IssueType with the global id of '%s'", typeID), "updateIssueIssueType")
+}
@@ -0,0 +1,349 @@
+package fakegithub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Project is a Projects v2 project owned by a user or organization.
+type Project struct {
+	ID     string
+	Owner  string
+	Number int
+	Title  string
+	Fields []*ProjectField
+	Items  []*ProjectItem
+
+	store *Store
+}
+
+// ProjectField is a field of a project. DataType is "SINGLE_SELECT",
+// "TEXT", "NUMBER", "DATE", or "ITERATION". The Options of an iteration
+// field are its iterations.
+type ProjectField struct {
+	ID       string
+	Name     string
+	DataType string
+	Options  []*ProjectOption
+}
+
+// ProjectOption is a choice of a single-select field. Color is one of
+// GitHub's named colors, such as "GREEN".
+type ProjectOption struct {
+	ID    string
+	Name  string
+	Color string
+}
+
+// ProjectItem is an issue on a project. Values are keyed by field name; a
+// single-select or iteration value is the option's or iteration's name, a
+// date is formatted as YYYY-MM-DD.
+type ProjectItem struct {
+	ID     string
+	Repo   *Repo
+	Issue  *Issue
+	Values map[string]string
+}
+
+// AddProject adds a project owned by owner, numbered after the owner's
+// other projects.
+func (s *Store) AddProject(owner, title string) *Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	number := 1
+	for _, p := range s.projects {
+		if strings.EqualFold(p.Owner, owner) {
+			number = max(number, p.Number+1)
+		}
+	}
+	p := &Project{ID: s.newID("PVT"), Owner: owner, Number: number, Title: title, store: s}
+	s.projects = append(s.projects, p)
+	return p
+}
+
+// AddField adds a field. The options of a single-select field are gray;
+// those of an iteration field name its iterations.
+func (p *Project) AddField(name, dataType string, options ...string) *ProjectField {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+	f := &ProjectField{ID: p.store.newID("PVTF"), Name: name, DataType: dataType}
+	for _, o := range options {
+		f.Options = append(f.Options, &ProjectOption{ID: p.store.newID("PVTO"), Name: o, Color: "GRAY"})
+	}
+	p.Fields = append(p.Fields, f)
+	return f
+}
+
+// AddItem adds the issue with the given number in r to the project, with
+// its field values.
+func (p *Project) AddItem(r *Repo, number int, values map[string]string) *ProjectItem {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+	issue := r.issue(number)
+	if issue == nil {
+		panic(fmt.Sprintf("fakegithub: no issue #%d in %s/%s", number, r.Owner, r.Name))
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	item := &ProjectItem{ID: p.store.newID("PVTI"), Repo: r, Issue: issue, Values: values}
+	p.Items = append(p.Items, item)
+	return item
+}
+
+func (p *Project) field(name string) *ProjectField {
+	for _, f := range p.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (p *Project) fieldByID(id string) *ProjectField {
+	for _, f := range p.Fields {
+		if f.ID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+func (p *Project) item(id string) *ProjectItem {
+	for _, i := range p.Items {
+		if i.ID == id {
+			return i
+		}
+	}
+	return nil
+}
+
+func (f *ProjectField) option(name string) *ProjectOption {
+	for _, o := range f.Options {
+		if o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+func (f *ProjectField) optionByID(id string) *ProjectOption {
+	for _, o := range f.Options {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
+
+func (s *Store) projectByID(id string) *Project {
+	for _, p := range s.projects {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// resolveIssueProjects serves the project items of the issues with the
+// given node IDs. Other IDs are served as null nodes.
+func resolveIssueProjects(s *Store, v variables) (obj, []gqlError) {
+	ids := v.strings("ids")
+	nodes := make([]interface{}, len(ids))
+	for n, id := range ids {
+		if _, i := s.issueByID(id); i != nil {
+			nodes[n] = obj{"id": i.ID, "projectItems": s.projectItemsJSON(i)}
+		}
+	}
+	return obj{"nodes": nodes}, nil
+}
+
+// projectItemsJSON serves the project items of issue i with the values of
+// their fields.
+func (s *Store) projectItemsJSON(i *Issue) obj {
+	nodes := []obj{}
+	for _, p := range s.projects {
+		for _, item := range p.Items {
+			if item.Issue != i {
+				continue
+			}
+			values := []obj{}
+			for _, f := range p.Fields {
+				if v, ok := item.Values[f.Name]; ok {
+					values = append(values, f.valueJSON(v))
+				}
+			}
+			nodes = append(nodes, obj{
+				"id":          item.ID,
+				"project":     obj{"id": p.ID, "number": p.Number, "title": p.Title},
+				"fieldValues": obj{"nodes": values},
+			})
+		}
+	}
+	return obj{"nodes": nodes}
+}
+
+// valueJSON serves a value of the field as the union member of its type.
+func (f *ProjectField) valueJSON(v string) obj {
+	value := obj{"field": obj{"id": f.ID, "name": f.Name, "dataType": f.DataType}}
+	switch f.DataType {
+	case "SINGLE_SELECT":
+		if o := f.option(v); o != nil {
+			value["name"], value["optionId"], value["color"] = o.Name, o.ID, o.Color
+		}
+	case "ITERATION":
+		if o := f.option(v); o != nil {
+			value["title"], value["iterationId"] = o.Name, o.ID
+		}
+	case "NUMBER":
+		n, _ := strconv.ParseFloat(v, 64)
+		value["number"] = n
+	case "DATE":
+		value["date"] = v
+	default:
+		value["text"] = v
+	}
+	return value
+}
+
+func resolveProjectField(s *Store, v variables) (obj, []gqlError) {
+	owner := v.string("owner")
+	var project *Project
+	for _, p := range s.projects {
+		if strings.EqualFold(p.Owner, owner) && p.Number == v.int("number") {
+			project = p
+		}
+	}
+	if project == nil {
+		return obj{"repositoryOwner": obj{"projectV2": nil}}, notFound(fmt.Sprintf("Could not resolve to a ProjectV2 with the number %d.", v.int("number")), "repositoryOwner", "projectV2")
+	}
+
+	var field interface{}
+	if f := project.field(v.string("field")); f != nil {
+		// Only single-select fields match the query's fragment.
+		if f.DataType == "SINGLE_SELECT" {
+			options := make([]obj, len(f.Options))
+			for i, o := range f.Options {
+				options[i] = obj{"id": o.ID, "name": o.Name, "color": o.Color}
+			}
+			field = obj{"id": f.ID, "name": f.Name, "options": options}
+		} else {
+			field = obj{}
+		}
+	}
+	return obj{"repositoryOwner": obj{"projectV2": obj{"id": project.ID, "title": project.Title, "field": field}}}, nil
+}
+
+func resolveProjectItems(s *Store, v variables) (obj, []gqlError) {
+	project := s.projectByID(v.string("id"))
+	if project == nil {
+		return obj{"node": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", v.string("id")), "node")
+	}
+
+	start, end, pageInfo, errs := paginate(len(project.Items), v, "node", "items")
+	if errs != nil {
+		return obj{"node": nil}, errs
+	}
+	field := project.field(v.string("field"))
+	nodes := make([]obj, 0, end-start)
+	for _, item := range project.Items[start:end] {
+		var value interface{}
+		if field != nil && field.DataType == "SINGLE_SELECT" {
+			if o := field.option(item.Values[field.Name]); o != nil {
+				value = obj{"optionId": o.ID}
+			}
+		}
+		content := item.Repo.issueJSON(item.Issue)
+		content["repository"] = obj{"nameWithOwner": item.Repo.Owner + "/" + item.Repo.Name}
+		nodes = append(nodes, obj{"id": item.ID, "fieldValueByName": value, "content": content})
+	}
+	return obj{"node": obj{"items": obj{"pageInfo": pageInfo, "nodes": nodes}}}, nil
+}
+
+func resolveProjectFields(s *Store, v variables) (obj, []gqlError) {
+	project := s.projectByID(v.string("id"))
+	if project == nil {
+		return obj{"node": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", v.string("id")), "node")
+	}
+
+	nodes := []obj{{"id": project.ID + "_TITLE", "name": "Title", "dataType": "TITLE"}}
+	for _, f := range project.Fields {
+		node := obj{"id": f.ID, "name": f.Name, "dataType": f.DataType}
+		options := make([]obj, len(f.Options))
+		for i, o := range f.Options {
+			options[i] = obj{"id": o.ID, "name": o.Name, "color": o.Color, "title": o.Name}
+		}
+		switch f.DataType {
+		case "SINGLE_SELECT":
+			node["options"] = options
+		case "ITERATION":
+			node["configuration"] = obj{"iterations": options}
+		}
+		nodes = append(nodes, node)
+	}
+	return obj{"node": obj{"id": project.ID, "title": project.Title, "fields": obj{"nodes": nodes}}}, nil
+}
+
+// projectItemField looks up the project, item, and field named by the
+// input of a field value mutation.
+func (s *Store) projectItemField(in variables, path string) (*ProjectItem, *ProjectField, []gqlError) {
+	project := s.projectByID(in.string("projectId"))
+	if project == nil {
+		return nil, nil, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("projectId")), path)
+	}
+	item := project.item(in.string("itemId"))
+	field := project.fieldByID(in.string("fieldId"))
+	if item == nil || field == nil {
+		return nil, nil, notFound("Could not resolve to a project item or field.", path)
+	}
+	return item, field, nil
+}
+
+func resolveUpdateProjectV2ItemFieldValue(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	item, field, errs := s.projectItemField(in, "updateProjectV2ItemFieldValue")
+	if errs != nil {
+		return obj{"updateProjectV2ItemFieldValue": nil}, errs
+	}
+
+	value := in.object("value")
+	switch field.DataType {
+	case "SINGLE_SELECT", "ITERATION":
+		id := value.string("singleSelectOptionId")
+		if field.DataType == "ITERATION" {
+			id = value.string("iterationId")
+		}
+		o := field.optionByID(id)
+		if o == nil {
+			return obj{"updateProjectV2ItemFieldValue": nil}, unprocessable(fmt.Sprintf("The %s Id does not belong to the field", strings.ToLower(strings.ReplaceAll(field.DataType, "_", " "))), "updateProjectV2ItemFieldValue")
+		}
+		item.Values[field.Name] = o.Name
+	case "NUMBER":
+		n, ok := value["number"].(float64)
+		if !ok {
+			return obj{"updateProjectV2ItemFieldValue": nil}, unprocessable("A number value is required", "updateProjectV2ItemFieldValue")
+		}
+		item.Values[field.Name] = strconv.FormatFloat(n, 'f', -1, 64)
+	case "DATE":
+		if _, err := time.Parse(time.DateOnly, value.string("date")); err != nil {
+			return obj{"updateProjectV2ItemFieldValue": nil}, unprocessable("A date value is required", "updateProjectV2ItemFieldValue")
+		}
+		item.Values[field.Name] = value.string("date")
+	case "TEXT":
+		item.Values[field.Name] = value.string("text")
+	default:
+		return obj{"updateProjectV2ItemFieldValue": nil}, unprocessable(fmt.Sprintf("Updating %s fields is not supported", field.DataType), "updateProjectV2ItemFieldValue")
+	}
+	return obj{"updateProjectV2ItemFieldValue": obj{"projectV2Item": obj{"id": item.ID}}}, nil
+}
+
+func resolveClearProjectV2ItemFieldValue(s *Store, v variables) (obj, []gqlError) {
+	item, field, errs := s.projectItemField(v.object("input"), "clearProjectV2ItemFieldValue")
+	if errs != nil {
+		return obj{"clearProjectV2ItemFieldValue": nil}, errs
+	}
+	delete(item.Values, field.Name)
+	return obj{"clearProjectV2ItemFieldValue": obj{"projectV2Item": obj{"id": item.ID}}}, nil
+}
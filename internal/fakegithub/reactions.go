@@ -0,0 +1,81 @@
+package fakegithub
+
+import (
+	"fmt"
+	"slices"
+)
+
+// reactionContents lists the reaction kinds in the order GitHub serves
+// their groups.
+var reactionContents = []string{"THUMBS_UP", "THUMBS_DOWN", "LAUGH", "HOORAY", "CONFUSED", "HEART", "ROCKET", "EYES"}
+
+// reactionGroupsJSON serves a group for every kind of reaction, used or
+// not, as GitHub does.
+func (s *Store) reactionGroupsJSON(reactions []Reaction) []obj {
+	groups := make([]obj, 0, len(reactionContents))
+	for _, content := range reactionContents {
+		count, viewer := 0, false
+		for _, r := range reactions {
+			if r.Content == content {
+				count++
+				viewer = viewer || r.User == s.viewer
+			}
+		}
+		groups = append(groups, obj{"content": content, "viewerHasReacted": viewer, "reactors": obj{"totalCount": count}})
+	}
+	return groups
+}
+
+// reactable returns the reactions of the issue or comment with the given
+// node ID, or nil if there is none.
+func (s *Store) reactable(id string) *[]Reaction {
+	for _, r := range s.repos {
+		for _, i := range r.Issues {
+			if i.ID == id {
+				return &i.Reactions
+			}
+			for _, c := range i.Comments {
+				if c.ID == id {
+					return &c.Reactions
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func resolveAddReaction(s *Store, v variables) (obj, []gqlError) {
+	return s.react(v, "addReaction", true)
+}
+
+func resolveRemoveReaction(s *Store, v variables) (obj, []gqlError) {
+	return s.react(v, "removeReaction", false)
+}
+
+// react adds or removes the viewer's reaction. Adding a reaction twice or
+// removing one that is not there changes nothing.
+func (s *Store) react(v variables, field string, add bool) (obj, []gqlError) {
+	in := v.object("input")
+	id, content := in.string("subjectId"), in.string("content")
+	reactions := s.reactable(id)
+	if reactions == nil {
+		return obj{field: nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id), field)
+	}
+	if !slices.Contains(reactionContents, content) {
+		return obj{field: nil}, []gqlError{{Type: "ARGUMENT_ERROR", Message: fmt.Sprintf("Argument 'content' has an invalid value (%s).", content), Path: []interface{}{field}}}
+	}
+
+	mine := Reaction{Content: content, User: s.viewer}
+	i := slices.Index(*reactions, mine)
+	switch {
+	case add && i < 0:
+		*reactions = append(*reactions, mine)
+	case !add && i >= 0:
+		*reactions = slices.Delete(*reactions, i, i+1)
+	}
+
+	return obj{field: obj{
+		"reaction": obj{"content": content},
+		"subject":  obj{"id": id, "reactionGroups": s.reactionGroupsJSON(*reactions)},
+	}}, nil
+}
@@ -0,0 +1,604 @@
+package fakegithub
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resolver answers one operation from the store, whose lock is held. It
+// returns the data and any GraphQL errors.
+type resolver func(s *Store, v variables) (obj, []gqlError)
+
+// resolvers maps operation names, as sent by the data package, to their
+// resolvers.
+var resolvers = map[string]resolver{
+	"ListIssues":                    resolveListIssues,
+	"SearchIssues":                  resolveSearchIssues,
+	"GetIssue":                      resolveGetIssue,
+	"CreateIssue":                   resolveCreateIssue,
+	"UpdateIssue":                   resolveUpdateIssue,
+	"ListComments":                  resolveListComments,
+	"ListTimeline":                  resolveListTimeline,
+	"ListLabels":                    resolveListLabels,
+	"CreateLabel":                   resolveCreateLabel,
+	"AddLabelsToLabelable":          resolveAddLabelsToLabelable,
+	"RemoveLabelsFromLabelable":     resolveRemoveLabelsFromLabelable,
+	"ListMilestones":                resolveListMilestones,
+	"RepositoryID":                  resolveRepositoryID,
+	"UserID":                        resolveUserID,
+	"Viewer":                        resolveViewer,
+	"RateLimit":                     resolveRateLimit,
+	"AddReaction":                   resolveAddReaction,
+	"RemoveReaction":                resolveRemoveReaction,
+	"AddComment":                    resolveAddComment,
+	"UpdateIssueComment":            resolveUpdateIssueComment,
+	"DeleteIssueComment":            resolveDeleteIssueComment,
+	"MinimizeComment":               resolveMinimizeComment,
+	"UnminimizeComment":             resolveUnminimizeComment,
+	"ProjectField":                  resolveProjectField,
+	"ProjectItems":                  resolveProjectItems,
+	"IssueProjects":                 resolveIssueProjects,
+	"ProjectFields":                 resolveProjectFields,
+	"UpdateProjectV2ItemFieldValue": resolveUpdateProjectV2ItemFieldValue,
+	"ClearProjectV2ItemFieldValue":  resolveClearProjectV2ItemFieldValue,
+	"ListIssueTypes":                resolveListIssueTypes,
+	"UpdateIssueIssueType":          resolveUpdateIssueIssueType,
+}
+
+// variables are the decoded variables of a request.
+type variables map[string]interface{}
+
+func (v variables) string(name string) string {
+	s, _ := v[name].(string)
+	return s
+}
+
+func (v variables) int(name string) int {
+	f, _ := v[name].(float64)
+	return int(f)
+}
+
+func (v variables) strings(name string) []string {
+	list, _ := v[name].([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (v variables) object(name string) variables {
+	m, _ := v[name].(map[string]interface{})
+	return variables(m)
+}
+
+func notFound(message string, path ...interface{}) []gqlError {
+	return []gqlError{{Type: "NOT_FOUND", Message: message, Path: path}}
+}
+
+func unprocessable(message string, path ...interface{}) []gqlError {
+	return []gqlError{{Type: "UNPROCESSABLE", Message: message, Path: path}}
+}
+
+// repository looks up the repository named by the owner and name
+// variables.
+func (s *Store) repository(v variables) (*Repo, []gqlError) {
+	owner, name := v.string("owner"), v.string("name")
+	r := s.repos[repoKey(owner, name)]
+	if r == nil {
+		return nil, notFound(fmt.Sprintf("Could not resolve to a Repository with the name '%s/%s'.", owner, name), "repository")
+	}
+	return r, nil
+}
+
+// repositoryIssue looks up the issue named by the number variable.
+func (s *Store) repositoryIssue(v variables) (*Repo, *Issue, []gqlError) {
+	r, errs := s.repository(v)
+	if r == nil {
+		return nil, nil, errs
+	}
+	i := r.issue(v.int("number"))
+	if i == nil {
+		return r, nil, notFound(fmt.Sprintf("Could not resolve to an Issue with the number of %d.", v.int("number")), "repository", "issue")
+	}
+	return r, i, nil
+}
+
+func resolveListIssues(s *Store, v variables) (obj, []gqlError) {
+	r, errs := s.repository(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+
+	states := v.strings("states")
+	labels := v.strings("labels")
+	var issues []*Issue
+	for _, i := range r.Issues {
+		if len(states) > 0 && !containsFold(states, i.State) {
+			continue
+		}
+		if len(labels) > 0 && !anyFold(labels, i.Labels) {
+			continue
+		}
+		issues = append(issues, i)
+	}
+	order := v.object("orderBy")
+	sortIssues(issues, order.string("field"), order.string("direction"))
+
+	start, end, pageInfo, errs := paginate(len(issues), v, "repository", "issues")
+	if errs != nil {
+		return obj{"repository": nil}, errs
+	}
+	nodes := make([]obj, 0, end-start)
+	for _, i := range issues[start:end] {
+		nodes = append(nodes, r.issueJSON(i))
+	}
+	return obj{"repository": obj{"issues": obj{"pageInfo": pageInfo, "nodes": nodes}}}, nil
+}
+
+func resolveSearchIssues(s *Store, v variables) (obj, []gqlError) {
+	q := parseSearch(v.string("query"))
+
+	var issues []*Issue
+	repoOf := map[*Issue]*Repo{}
+	for _, r := range s.repos {
+		if q.repo != "" && repoKey(r.Owner, r.Name) != strings.ToLower(q.repo) {
+			continue
+		}
+		for _, i := range r.Issues {
+			if q.matches(i) {
+				issues = append(issues, i)
+				repoOf[i] = r
+			}
+		}
+	}
+	sortIssues(issues, q.sortField, q.sortDirection)
+
+	start, end, pageInfo, errs := paginate(len(issues), v, "search")
+	if errs != nil {
+		return obj{"search": nil}, errs
+	}
+	nodes := make([]obj, 0, end-start)
+	for _, i := range issues[start:end] {
+		nodes = append(nodes, repoOf[i].issueJSON(i))
+	}
+	return obj{"search": obj{"issueCount": len(issues), "pageInfo": pageInfo, "nodes": nodes}}, nil
+}
+
+func resolveGetIssue(s *Store, v variables) (obj, []gqlError) {
+	r, i, errs := s.repositoryIssue(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+	if i == nil {
+		return obj{"repository": obj{"issue": nil}}, errs
+	}
+	return obj{"repository": obj{"issue": r.issueJSON(i)}}, nil
+}
+
+func resolveCreateIssue(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r := s.repoByID(in.string("repositoryId"))
+	if r == nil {
+		return obj{"createIssue": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("repositoryId")), "createIssue")
+	}
+	if strings.TrimSpace(in.string("title")) == "" {
+		return obj{"createIssue": nil}, unprocessable("Title can't be blank", "createIssue")
+	}
+
+	issue := Issue{Title: in.string("title"), Body: in.string("body")}
+	for _, id := range in.strings("labelIds") {
+		l := r.labelByID(id)
+		if l == nil {
+			return obj{"createIssue": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id), "createIssue")
+		}
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	for _, id := range in.strings("assigneeIds") {
+		u := s.userByID(id)
+		if u == nil {
+			return obj{"createIssue": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id), "createIssue")
+		}
+		issue.Assignees = append(issue.Assignees, u.Login)
+	}
+	if id := in.string("milestoneId"); id != "" {
+		m := r.milestoneByID(id)
+		if m == nil {
+			return obj{"createIssue": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id), "createIssue")
+		}
+		issue.Milestone = m.Title
+	}
+
+	created := r.addIssue(issue)
+	return obj{"createIssue": obj{"issue": r.issueJSON(created)}}, nil
+}
+
+func resolveUpdateIssue(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r, i := s.issueByID(in.string("id"))
+	if i == nil {
+		return obj{"updateIssue": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("id")), "updateIssue")
+	}
+	if body, ok := in["body"].(string); ok {
+		i.Body = body
+	}
+	if title, ok := in["title"].(string); ok {
+		i.Title = title
+	}
+	i.UpdatedAt = s.now()
+	return obj{"updateIssue": obj{"issue": r.issueJSON(i)}}, nil
+}
+
+func resolveListComments(s *Store, v variables) (obj, []gqlError) {
+	r, i, errs := s.repositoryIssue(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+	if i == nil {
+		return obj{"repository": obj{"issue": nil}}, errs
+	}
+
+	start, end, pageInfo, errs := paginate(len(i.Comments), v, "repository", "issue", "comments")
+	if errs != nil {
+		return obj{"repository": obj{"issue": nil}}, errs
+	}
+	nodes := make([]obj, 0, end-start)
+	for _, c := range i.Comments[start:end] {
+		nodes = append(nodes, r.commentJSON(i, c))
+	}
+	return obj{"repository": obj{"issue": obj{"comments": obj{"pageInfo": pageInfo, "nodes": nodes}}}}, nil
+}
+
+func resolveListTimeline(s *Store, v variables) (obj, []gqlError) {
+	r, i, errs := s.repositoryIssue(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+	if i == nil {
+		return obj{"repository": obj{"issue": nil}}, errs
+	}
+
+	start, end, pageInfo, errs := paginate(len(i.Events), v, "repository", "issue", "timelineItems")
+	if errs != nil {
+		return obj{"repository": obj{"issue": nil}}, errs
+	}
+	nodes := make([]obj, 0, end-start)
+	for _, e := range i.Events[start:end] {
+		node := obj{"__typename": e.Type, "createdAt": e.CreatedAt, "actor": actorJSON(e.Actor)}
+		if e.Label != "" {
+			l := r.label(e.Label)
+			color := ""
+			if l != nil {
+				color = l.Color
+			}
+			node["label"] = obj{"name": e.Label, "color": color}
+		}
+		if e.Assignee != "" {
+			node["assignee"] = obj{"login": e.Assignee}
+		}
+		if e.Milestone != "" {
+			node["milestoneTitle"] = e.Milestone
+		}
+		if e.StateReason != "" {
+			node["stateReason"] = e.StateReason
+		}
+		if e.PreviousTitle != "" || e.CurrentTitle != "" {
+			node["previousTitle"] = e.PreviousTitle
+			node["currentTitle"] = e.CurrentTitle
+		}
+		nodes = append(nodes, node)
+	}
+	return obj{"repository": obj{"issue": obj{"timelineItems": obj{"pageInfo": pageInfo, "nodes": nodes}}}}, nil
+}
+
+func resolveListLabels(s *Store, v variables) (obj, []gqlError) {
+	r, errs := s.repository(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+
+	start, end, pageInfo, errs := paginate(len(r.Labels), v, "repository", "labels")
+	if errs != nil {
+		return obj{"repository": nil}, errs
+	}
+	nodes := make([]obj, 0, end-start)
+	for _, l := range r.Labels[start:end] {
+		nodes = append(nodes, labelJSON(l))
+	}
+	return obj{"repository": obj{"labels": obj{"pageInfo": pageInfo, "nodes": nodes}}}, nil
+}
+
+func resolveCreateLabel(s *Store, v variables) (obj, []gqlError) {
+	in := v.object("input")
+	r := s.repoByID(in.string("repositoryId"))
+	if r == nil {
+		return obj{"createLabel": nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("repositoryId")), "createLabel")
+	}
+	name := in.string("name")
+	if strings.TrimSpace(name) == "" {
+		return obj{"createLabel": nil}, unprocessable("Name can't be blank", "createLabel")
+	}
+	if r.label(name) != nil {
+		return obj{"createLabel": nil}, unprocessable("Name has already been taken", "createLabel")
+	}
+
+	l := r.addLabel(name, in.string("color"))
+	return obj{"createLabel": obj{"label": labelJSON(l)}}, nil
+}
+
+func resolveAddLabelsToLabelable(s *Store, v variables) (obj, []gqlError) {
+	return s.relabel(v, "addLabelsToLabelable", true)
+}
+
+func resolveRemoveLabelsFromLabelable(s *Store, v variables) (obj, []gqlError) {
+	return s.relabel(v, "removeLabelsFromLabelable", false)
+}
+
+// relabel adds or removes the labels named by ID in the input, recording
+// a timeline event for each change.
+func (s *Store) relabel(v variables, field string, add bool) (obj, []gqlError) {
+	in := v.object("input")
+	r, issue := s.issueByID(in.string("labelableId"))
+	if issue == nil {
+		return obj{field: nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", in.string("labelableId")), field)
+	}
+	var labels []*Label
+	for _, id := range in.strings("labelIds") {
+		l := r.labelByID(id)
+		if l == nil {
+			return obj{field: nil}, notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id), field)
+		}
+		labels = append(labels, l)
+	}
+
+	for _, l := range labels {
+		has := containsFold(issue.Labels, l.Name)
+		switch {
+		case add && !has:
+			issue.Labels = append(issue.Labels, l.Name)
+			issue.Events = append(issue.Events, &Event{Type: "LabeledEvent", Actor: s.viewer, CreatedAt: s.now(), Label: l.Name})
+		case !add && has:
+			issue.Labels = slices.DeleteFunc(issue.Labels, func(name string) bool { return strings.EqualFold(name, l.Name) })
+			issue.Events = append(issue.Events, &Event{Type: "UnlabeledEvent", Actor: s.viewer, CreatedAt: s.now(), Label: l.Name})
+		}
+	}
+	return obj{field: obj{"clientMutationId": nil}}, nil
+}
+
+func resolveListMilestones(s *Store, v variables) (obj, []gqlError) {
+	r, errs := s.repository(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+
+	start, end, pageInfo, errs := paginate(len(r.Milestones), v, "repository", "milestones")
+	if errs != nil {
+		return obj{"repository": nil}, errs
+	}
+	nodes := make([]obj, 0, end-start)
+	for _, m := range r.Milestones[start:end] {
+		nodes = append(nodes, obj{"id": m.ID, "number": m.Number, "title": m.Title, "state": m.State})
+	}
+	return obj{"repository": obj{"milestones": obj{"pageInfo": pageInfo, "nodes": nodes}}}, nil
+}
+
+func resolveRepositoryID(s *Store, v variables) (obj, []gqlError) {
+	r, errs := s.repository(v)
+	if r == nil {
+		return obj{"repository": nil}, errs
+	}
+	return obj{"repository": obj{"id": r.ID}}, nil
+}
+
+func resolveUserID(s *Store, v variables) (obj, []gqlError) {
+	login := v.string("login")
+	u := s.users[strings.ToLower(login)]
+	if u == nil {
+		return obj{"user": nil}, notFound(fmt.Sprintf("Could not resolve to a User with the login of '%s'.", login), "user")
+	}
+	return obj{"user": obj{"id": u.ID, "login": u.Login}}, nil
+}
+
+func resolveViewer(s *Store, v variables) (obj, []gqlError) {
+	return obj{"viewer": obj{"login": s.viewer}}, nil
+}
+
+// resolveRateLimit answers the budget query; the server adds the rateLimit
+// field to every query that selects it.
+func resolveRateLimit(s *Store, v variables) (obj, []gqlError) {
+	return obj{}, nil
+}
+
+func (r *Repo) issueJSON(i *Issue) obj {
+	labels := make([]obj, 0, len(i.Labels))
+	for _, name := range i.Labels {
+		if l := r.label(name); l != nil {
+			labels = append(labels, labelJSON(l))
+		} else {
+			labels = append(labels, obj{"name": name, "color": ""})
+		}
+	}
+	assignees := make([]obj, 0, len(i.Assignees))
+	for _, login := range i.Assignees {
+		assignees = append(assignees, obj{"login": login})
+	}
+	var milestone interface{}
+	if i.Milestone != "" {
+		milestone = obj{"title": i.Milestone}
+	}
+
+	issue := obj{
+		"id":             i.ID,
+		"number":         i.Number,
+		"title":          i.Title,
+		"body":           i.Body,
+		"state":          i.State,
+		"createdAt":      i.CreatedAt,
+		"updatedAt":      i.UpdatedAt,
+		"author":         actorJSON(i.Author),
+		"labels":         obj{"nodes": labels},
+		"assignees":      obj{"nodes": assignees},
+		"milestone":      milestone,
+		"issueType":      r.store.issueTypeJSON(r.Owner, i.Type),
+		"comments":       obj{"totalCount": len(i.Comments)},
+		"reactions":      obj{"totalCount": len(i.Reactions)},
+		"reactionGroups": r.store.reactionGroupsJSON(i.Reactions),
+		"url":            r.issueURL(i),
+	}
+	r.store.hierarchyJSON(i, issue)
+	return issue
+}
+
+func (r *Repo) issueURL(i *Issue) string {
+	return fmt.Sprintf("https://github.com/%s/%s/issues/%d", r.Owner, r.Name, i.Number)
+}
+
+func labelJSON(l *Label) obj {
+	return obj{"id": l.ID, "name": l.Name, "color": l.Color}
+}
+
+// actorJSON serves deleted accounts as a null actor.
+func actorJSON(login string) interface{} {
+	if login == "" || login == Ghost {
+		return nil
+	}
+	return obj{"login": login}
+}
+
+func (r *Repo) labelByID(id string) *Label {
+	for _, l := range r.Labels {
+		if l.ID == id {
+			return l
+		}
+	}
+	return nil
+}
+
+func (r *Repo) milestone(title string) *Milestone {
+	for _, m := range r.Milestones {
+		if strings.EqualFold(m.Title, title) {
+			return m
+		}
+	}
+	return nil
+}
+
+func (r *Repo) milestoneByID(id string) *Milestone {
+	for _, m := range r.Milestones {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Store) userByID(id string) *User {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+// sortIssues sorts issues by an IssueOrder field and direction, newest
+// first by default. Ties are broken by number, then by ID.
+func sortIssues(issues []*Issue, field, direction string) {
+	key := func(i *Issue) int64 {
+		switch field {
+		case "UPDATED_AT":
+			return i.UpdatedAt.UnixNano()
+		case "COMMENTS":
+			return int64(len(i.Comments))
+		default:
+			return i.CreatedAt.UnixNano()
+		}
+	}
+	asc := direction == "ASC"
+	sort.SliceStable(issues, func(a, b int) bool {
+		ka, kb := key(issues[a]), key(issues[b])
+		if ka == kb {
+			ka, kb = int64(issues[a].Number), int64(issues[b].Number)
+		}
+		if ka == kb {
+			return issues[a].ID < issues[b].ID
+		}
+		if asc {
+			return ka < kb
+		}
+		return ka > kb
+	})
+}
+
+// paginate applies the first and after variables to a list of n items.
+// Cursors are opaque and name the position after an item.
+func paginate(n int, v variables, path ...interface{}) (int, int, obj, []gqlError) {
+	first := v.int("first")
+	if first <= 0 || first > 100 {
+		return 0, 0, nil, []gqlError{{
+			Type:    "ARGUMENT_ERROR",
+			Message: fmt.Sprintf("Requesting %d records on the connection is outside the allowed range of 1 to 100.", first),
+			Path:    path,
+		}}
+	}
+
+	start := 0
+	if after := v.string("after"); after != "" {
+		pos, ok := decodeCursor(after)
+		if !ok {
+			return 0, 0, nil, []gqlError{{
+				Type:    "INVALID_CURSOR_ARGUMENTS",
+				Message: fmt.Sprintf("`%s` does not appear to be a valid cursor.", after),
+				Path:    path,
+			}}
+		}
+		start = min(pos, n)
+	}
+	end := min(start+first, n)
+
+	pageInfo := obj{"hasNextPage": end < n, "endCursor": nil}
+	if end > start {
+		pageInfo["endCursor"] = encodeCursor(end)
+	}
+	return start, end, pageInfo, nil
+}
+
+func encodeCursor(pos int) string {
+	return base64.StdEncoding.EncodeToString([]byte("cursor:" + strconv.Itoa(pos)))
+}
+
+func decodeCursor(cursor string) (int, bool) {
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+	pos, err := strconv.Atoi(strings.TrimPrefix(string(b), "cursor:"))
+	if err != nil || pos < 0 || !strings.HasPrefix(string(b), "cursor:") {
+		return 0, false
+	}
+	return pos, true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyFold(want, have []string) bool {
+	for _, s := range have {
+		if containsFold(want, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package fakegithub
+
+import (
+	"strings"
+)
+
+// searchQuery is a parsed issue search string. It understands the
+// qualifiers the dashboard's filters produce; any other words must all
+// appear in the title or body.
+type searchQuery struct {
+	repo          string
+	state         string
+	labels        []string
+	author        string
+	assignee      string
+	milestone     string
+	noAssignee    bool
+	terms         []string
+	sortField     string
+	sortDirection string
+}
+
+func parseSearch(query string) searchQuery {
+	q := searchQuery{sortField: "CREATED_AT", sortDirection: "DESC"}
+	for _, token := range splitSearch(query) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			q.terms = append(q.terms, strings.ToLower(token))
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "repo":
+			q.repo = value
+		case "is", "state":
+			switch strings.ToLower(value) {
+			case "open", "closed":
+				q.state = strings.ToUpper(value)
+			}
+		case "label":
+			q.labels = append(q.labels, value)
+		case "author":
+			q.author = value
+		case "assignee":
+			q.assignee = value
+		case "milestone":
+			q.milestone = value
+		case "no":
+			if strings.EqualFold(value, "assignee") {
+				q.noAssignee = true
+			}
+		case "sort":
+			field, direction, _ := strings.Cut(strings.ToLower(value), "-")
+			switch field {
+			case "updated":
+				q.sortField = "UPDATED_AT"
+			case "comments":
+				q.sortField = "COMMENTS"
+			default:
+				q.sortField = "CREATED_AT"
+			}
+			q.sortDirection = "DESC"
+			if direction == "asc" {
+				q.sortDirection = "ASC"
+			}
+		default:
+			q.terms = append(q.terms, strings.ToLower(token))
+		}
+	}
+	return q
+}
+
+// splitSearch splits a search string on spaces outside double quotes and
+// drops the quotes, so label:"needs review" is one token.
+func splitSearch(query string) []string {
+	var tokens []string
+	var b strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if b.Len() > 0 {
+				tokens = append(tokens, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		tokens = append(tokens, b.String())
+	}
+	return tokens
+}
+
+func (q searchQuery) matches(i *Issue) bool {
+	if q.state != "" && i.State != q.state {
+		return false
+	}
+	for _, l := range q.labels {
+		if !containsFold(i.Labels, l) {
+			return false
+		}
+	}
+	if q.author != "" && !strings.EqualFold(i.Author, q.author) {
+		return false
+	}
+	if q.assignee != "" && !containsFold(i.Assignees, q.assignee) {
+		return false
+	}
+	if q.noAssignee && len(i.Assignees) > 0 {
+		return false
+	}
+	if q.milestone != "" && !strings.EqualFold(i.Milestone, q.milestone) {
+		return false
+	}
+	text := strings.ToLower(i.Title + "\n" + i.Body)
+	for _, term := range q.terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
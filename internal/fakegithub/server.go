@@ -0,0 +1,539 @@
+package fakegithub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Failure is a failure injected into the responses to an operation.
+type Failure struct {
+	// Status is the HTTP status to fail with. Zero means a GraphQL error in
+	// an otherwise successful response.
+	Status int
+	// Type is the GraphQL error type, e.g. "NOT_FOUND" or "RATE_LIMITED".
+	Type    string
+	Message string
+	// Path is the GraphQL error path. With Partial set, the operation's data
+	// is returned alongside the error.
+	Path    []interface{}
+	Partial bool
+	// Headers are added to the response, e.g. Retry-After.
+	Headers http.Header
+	// Delay holds the response back, to exercise timeouts and cancellation.
+	Delay time.Duration
+	// Times is the number of requests that fail. Zero means all of them.
+	Times int
+}
+
+// Request is a request the server has answered.
+type Request struct {
+	Operation string
+	Query     string
+	Variables map[string]interface{}
+}
+
+// RateLimit is the server's GraphQL point budget.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// Server answers GraphQL requests from a Store. Use Do and DoWithContext
+// to query it in-process, or the go-gh clients from GraphQLClient and
+// RESTClient to query it over HTTP.
+type Server struct {
+	store *Store
+
+	mu        sync.Mutex
+	failures  map[string][]*Failure
+	rateLimit RateLimit
+	requests  []Request
+	http      *httptest.Server
+}
+
+// NewServer creates a server for store with a full budget of 5000 points.
+func NewServer(store *Store) *Server {
+	store.mu.Lock()
+	resetAt := store.now().Add(time.Hour)
+	store.mu.Unlock()
+
+	return &Server{
+		store:     store,
+		failures:  map[string][]*Failure{},
+		rateLimit: RateLimit{Limit: 5000, Remaining: 5000, ResetAt: resetAt},
+	}
+}
+
+// Store returns the store the server answers from.
+func (s *Server) Store() *Store {
+	return s.store
+}
+
+// SetRateLimit replaces the point budget. Once it is spent, queries fail
+// with RATE_LIMITED until it is replaced again.
+func (s *Server) SetRateLimit(limit RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+}
+
+// RateLimit returns the remaining point budget.
+func (s *Server) RateLimit() RateLimit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rateLimit
+}
+
+// Fail injects a failure into responses to the named operation, e.g.
+// "ListIssues" or "CreateMilestone" for the REST endpoint. An empty name
+// matches every operation. Failures are consumed in the order they were
+// added.
+func (s *Server) Fail(operation string, f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[operation] = append(s.failures[operation], &f)
+}
+
+// Requests returns the requests answered so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Operations returns the names of the operations answered so far.
+func (s *Server) Operations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ops := make([]string, len(s.requests))
+	for i, r := range s.requests {
+		ops[i] = r.Operation
+	}
+	return ops
+}
+
+// Do answers a GraphQL request in-process, like api.GraphQLClient.Do.
+func (s *Server) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return s.DoWithContext(context.Background(), query, variables, response)
+}
+
+// DoWithContext answers a GraphQL request in-process, like
+// api.GraphQLClient.DoWithContext. Failures are returned as *api.HTTPError
+// and *api.GraphQLError, with response populated from any data.
+func (s *Server) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	// Round-trip the variables so that resolvers see what they would over
+	// HTTP, e.g. float64 instead of int.
+	var vars map[string]interface{}
+	if err := roundTrip(variables, &vars); err != nil {
+		return err
+	}
+
+	res, err := s.execute(ctx, query, vars)
+	if err != nil {
+		return err
+	}
+	if res.status != http.StatusOK {
+		return &api.HTTPError{
+			StatusCode: res.status,
+			Message:    res.message,
+			Headers:    res.header,
+			RequestURL: &url.URL{Scheme: "https", Host: "api.github.com", Path: "/graphql"},
+		}
+	}
+	if res.data != nil {
+		if err := roundTrip(res.data, response); err != nil {
+			return err
+		}
+	}
+	if len(res.errors) > 0 {
+		items := make([]api.GraphQLErrorItem, len(res.errors))
+		for i, e := range res.errors {
+			items[i] = api.GraphQLErrorItem{Type: e.Type, Message: e.Message, Path: e.Path}
+		}
+		return &api.GraphQLError{Errors: items}
+	}
+	return nil
+}
+
+// result is the answer to one request.
+type result struct {
+	status  int
+	header  http.Header
+	message string
+	data    obj
+	errors  []gqlError
+}
+
+type gqlError struct {
+	Type    string        `json:"type,omitempty"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+type obj = map[string]interface{}
+
+var operationPattern = regexp.MustCompile(`^\s*(query|mutation)\s+(\w+)`)
+
+// operationName returns the name of the operation in query. The anonymous
+// viewer query is called "Viewer".
+func operationName(query string) string {
+	if m := operationPattern.FindStringSubmatch(query); m != nil {
+		return m[2]
+	}
+	if strings.Contains(query, "viewer") {
+		return "Viewer"
+	}
+	return ""
+}
+
+func (s *Server) execute(ctx context.Context, query string, vars map[string]interface{}) (result, error) {
+	op := operationName(query)
+	isQuery := !strings.HasPrefix(strings.TrimSpace(query), "mutation")
+
+	s.store.mu.Lock()
+	now := s.store.now()
+	s.store.mu.Unlock()
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Operation: op, Query: query, Variables: vars})
+	failure := s.nextFailure(op)
+	limit, limited := s.spend(isQuery, now)
+	s.mu.Unlock()
+
+	res := result{status: http.StatusOK, header: rateLimitHeaders(limit)}
+
+	if failure != nil {
+		if err := sleepContext(ctx, failure.Delay); err != nil {
+			return result{}, err
+		}
+		for k, v := range failure.Headers {
+			res.header[k] = v
+		}
+		if failure.Status != 0 && failure.Status != http.StatusOK {
+			res.status = failure.Status
+			res.message = failure.Message
+			return res, nil
+		}
+		res.errors = append(res.errors, gqlError{Type: failure.Type, Message: failure.Message, Path: failure.Path})
+		if !failure.Partial {
+			return res, nil
+		}
+	}
+
+	if limited {
+		res.header.Set("X-RateLimit-Remaining", "0")
+		res.errors = append(res.errors, gqlError{
+			Type:    "RATE_LIMITED",
+			Message: "API rate limit exceeded for user ID 1.",
+		})
+		return res, nil
+	}
+
+	resolve, ok := resolvers[op]
+	if !ok {
+		res.errors = append(res.errors, gqlError{Message: fmt.Sprintf("fakegithub: unsupported operation %q", op)})
+		return res, nil
+	}
+
+	s.store.mu.Lock()
+	data, errs := resolve(s.store, variables(vars))
+	s.store.mu.Unlock()
+
+	if data == nil {
+		data = obj{}
+	}
+	if isQuery && strings.Contains(query, "rateLimit") {
+		data["rateLimit"] = obj{
+			"limit":     limit.Limit,
+			"cost":      1,
+			"remaining": limit.Remaining,
+			"resetAt":   limit.ResetAt,
+		}
+	}
+	res.data = data
+	res.errors = append(res.errors, errs...)
+	return res, nil
+}
+
+// nextFailure returns the failure to inject into a request for op, if any.
+// s.mu must be held.
+func (s *Server) nextFailure(op string) *Failure {
+	for _, name := range []string{op, ""} {
+		queue := s.failures[name]
+		if len(queue) == 0 {
+			continue
+		}
+		f := queue[0]
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures[name] = queue[1:]
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// spend charges a query one point and reports whether the budget was
+// already spent. As on GitHub, the budget is refilled once its reset time
+// has passed. s.mu must be held.
+func (s *Server) spend(isQuery bool, now time.Time) (RateLimit, bool) {
+	if !isQuery {
+		return s.rateLimit, false
+	}
+	if !now.Before(s.rateLimit.ResetAt) {
+		s.rateLimit.Remaining = s.rateLimit.Limit
+		s.rateLimit.ResetAt = now.Add(time.Hour)
+	}
+	if s.rateLimit.Remaining <= 0 {
+		return s.rateLimit, true
+	}
+	s.rateLimit.Remaining--
+	return s.rateLimit, false
+}
+
+func rateLimitHeaders(limit RateLimit) http.Header {
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(limit.ResetAt.Unix(), 10))
+	return h
+}
+
+// ServeHTTP implements http.Handler. It answers GraphQL requests on any path
+// ending in /graphql and the REST endpoints the data package uses under
+// /repos, with or without the /api/v3 prefix of GitHub Enterprise Server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" {
+		writeJSON(w, http.StatusUnauthorized, nil, obj{"message": "Bad credentials"})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	switch {
+	case strings.HasSuffix(path, "/graphql") && r.Method == http.MethodPost:
+		s.serveGraphQL(w, r)
+	case strings.HasPrefix(path, "/repos/"):
+		s.serveREST(w, r, strings.TrimPrefix(path, "/repos/"))
+	default:
+		writeJSON(w, http.StatusNotFound, nil, obj{"message": "Not Found"})
+	}
+}
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, nil, obj{"message": "Problems parsing JSON"})
+		return
+	}
+
+	res, err := s.execute(r.Context(), req.Query, req.Variables)
+	if err != nil {
+		return
+	}
+	if res.status != http.StatusOK {
+		writeJSON(w, res.status, res.header, obj{"message": res.message})
+		return
+	}
+
+	body := obj{"data": res.data}
+	if len(res.errors) > 0 {
+		body["errors"] = res.errors
+	}
+	writeJSON(w, http.StatusOK, res.header, body)
+}
+
+// serveREST answers POST /repos/{owner}/{name}/milestones, the only REST
+// endpoint the data package uses. Its operation name is "CreateMilestone".
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request, path string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[2] != "milestones" || r.Method != http.MethodPost {
+		writeJSON(w, http.StatusNotFound, nil, obj{"message": "Not Found"})
+		return
+	}
+
+	var body struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, nil, obj{"message": "Problems parsing JSON"})
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Operation: "CreateMilestone", Variables: obj{"title": body.Title}})
+	failure := s.nextFailure("CreateMilestone")
+	s.mu.Unlock()
+
+	if failure != nil {
+		if sleepContext(r.Context(), failure.Delay) != nil {
+			return
+		}
+		status := failure.Status
+		if status == 0 {
+			status = http.StatusUnprocessableEntity
+		}
+		writeJSON(w, status, failure.Headers, obj{"message": failure.Message})
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	repo := s.store.repos[repoKey(parts[0], parts[1])]
+	switch {
+	case repo == nil:
+		writeJSON(w, http.StatusNotFound, nil, obj{"message": "Not Found"})
+	case body.Title == "":
+		writeJSON(w, http.StatusUnprocessableEntity, nil, obj{"message": "Validation Failed"})
+	case repo.milestone(body.Title) != nil:
+		writeJSON(w, http.StatusUnprocessableEntity, nil, obj{"message": "Validation Failed: title already_exists"})
+	default:
+		m := repo.addMilestone(body.Title)
+		writeJSON(w, http.StatusCreated, nil, obj{
+			"node_id": m.ID,
+			"number":  m.Number,
+			"title":   m.Title,
+			"state":   strings.ToLower(m.State),
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, header http.Header, body interface{}) {
+	for k, v := range header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// URL starts serving over HTTP if needed and returns the base URL.
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.http == nil {
+		s.http = httptest.NewServer(s)
+	}
+	return s.http.URL
+}
+
+// Close stops serving over HTTP.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.http != nil {
+		s.http.Close()
+		s.http = nil
+	}
+}
+
+// ClientOptions returns go-gh client options for host whose requests are
+// sent to this server over HTTP, starting it if needed.
+func (s *Server) ClientOptions(host string) api.ClientOptions {
+	target, _ := url.Parse(s.URL())
+	return api.ClientOptions{
+		Host:      host,
+		AuthToken: "fake-token",
+		Transport: rewriteTransport{target: target},
+	}
+}
+
+// GraphQLClient returns a go-gh GraphQL client for host that talks to this
+// server over HTTP.
+func (s *Server) GraphQLClient(host string) (*api.GraphQLClient, error) {
+	return api.NewGraphQLClient(s.ClientOptions(host))
+}
+
+// RESTClient returns a go-gh REST client for host that talks to this server
+// over HTTP.
+func (s *Server) RESTClient(host string) (*api.RESTClient, error) {
+	return api.NewRESTClient(s.ClientOptions(host))
+}
+
+// LocalREST answers REST requests in-process, without a network listener.
+// It has the method set of data.RESTDoer.
+type LocalREST struct {
+	server *Server
+}
+
+// LocalREST returns a REST client that calls the server directly.
+func (s *Server) LocalREST() *LocalREST {
+	return &LocalREST{server: s}
+}
+
+// Do sends a request with the given method, path relative to the API root,
+// and JSON body, and decodes a successful response into response. Failures
+// are returned as *api.HTTPError, as from a go-gh REST client.
+func (l *LocalREST) Do(method string, path string, body io.Reader, response interface{}) error {
+	req := httptest.NewRequest(method, "/"+strings.TrimPrefix(path, "/"), body)
+	req.Header.Set("Authorization", "token local")
+	rec := httptest.NewRecorder()
+	l.server.ServeHTTP(rec, req)
+
+	if rec.Code >= http.StatusMultipleChoices {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &msg)
+		u, _ := url.Parse("https://api.github.com" + req.URL.Path)
+		return &api.HTTPError{StatusCode: rec.Code, Message: msg.Message, Headers: rec.Header(), RequestURL: u}
+	}
+	if response == nil || rec.Body.Len() == 0 {
+		return nil
+	}
+	return json.Unmarshal(rec.Body.Bytes(), response)
+}
+
+// rewriteTransport sends every request to target, keeping its path, so that
+// clients for any host reach the server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func roundTrip(in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,683 @@
+package fakegithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+)
+
+// newTestServer seeds a repository with five issues, a label, a milestone,
+// and a comment, and serves it over HTTP.
+func newTestServer(t *testing.T) (*Server, *Repo) {
+	t.Helper()
+
+	store := NewStore()
+	repo := store.AddRepo("octo", "hello")
+	repo.AddLabel("bug", "d73a4a")
+	repo.AddMilestone("v1")
+	for n := 1; n <= 5; n++ {
+		issue := Issue{
+			Title:     "Issue " + string(rune('A'+n-1)),
+			CreatedAt: Epoch.Add(time.Duration(n) * time.Hour),
+		}
+		if n%2 == 0 {
+			issue.Labels = []string{"bug"}
+		}
+		if n == 5 {
+			issue.State = "CLOSED"
+		}
+		repo.AddIssue(issue)
+	}
+	repo.AddComment(1, Comment{Author: "hubot", Body: "First!"})
+	repo.AddComment(1, Comment{Author: Ghost, Body: "Gone"})
+
+	srv := NewServer(store)
+	t.Cleanup(srv.Close)
+	return srv, repo
+}
+
+func httpQuerier(t *testing.T, srv *Server) data.ContextQuerier {
+	t.Helper()
+	client, err := srv.GraphQLClient("github.com")
+	if err != nil {
+		t.Fatalf("GraphQLClient: %v", err)
+	}
+	return client
+}
+
+func TestListIssues_PaginatesOverHTTP(t *testing.T) {
+	srv, _ := newTestServer(t)
+	issues := data.NewIssueClient(httpQuerier(t, srv), "octo", "hello")
+
+	opts := data.IssueListOptions{States: []string{"OPEN"}, First: 2}
+	var numbers []int
+	for page := 0; ; page++ {
+		result, err := issues.List(opts)
+		if err != nil {
+			t.Fatalf("List page %d: %v", page, err)
+		}
+		for _, issue := range result.Issues {
+			numbers = append(numbers, issue.Number)
+		}
+		if !result.PageInfo.HasNextPage {
+			break
+		}
+		opts.After = result.PageInfo.EndCursor
+	}
+
+	want := []int{4, 3, 2, 1}
+	if len(numbers) != len(want) {
+		t.Fatalf("numbers = %v, want %v", numbers, want)
+	}
+	for i := range want {
+		if numbers[i] != want[i] {
+			t.Fatalf("numbers = %v, want %v", numbers, want)
+		}
+	}
+}
+
+func TestListIssues_FiltersAndOrders(t *testing.T) {
+	srv, _ := newTestServer(t)
+	issues := data.NewIssueClient(srv, "octo", "hello")
+
+	result, err := issues.List(data.IssueListOptions{
+		Labels:  []string{"bug"},
+		OrderBy: data.IssueOrder{Field: "CREATED_AT", Direction: "ASC"},
+		First:   10,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(result.Issues) != 2 || result.Issues[0].Number != 2 || result.Issues[1].Number != 4 {
+		t.Fatalf("issues = %+v, want #2 and #4", result.Issues)
+	}
+	if result.Issues[0].Labels[0].Color != "d73a4a" {
+		t.Errorf("label color = %q", result.Issues[0].Labels[0].Color)
+	}
+}
+
+func TestSearchIssues(t *testing.T) {
+	srv, repo := newTestServer(t)
+	repo.AddIssue(Issue{Title: "Crash on start", Body: "segfault", Labels: []string{"bug"}})
+	issues := data.NewIssueClient(httpQuerier(t, srv), "octo", "hello")
+
+	result, err := issues.Search(`is:open label:"bug" segfault`, 10, "")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Title != "Crash on start" {
+		t.Fatalf("issues = %+v", result.Issues)
+	}
+
+	result, err = issues.Search("is:closed", 10, "")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Number != 5 {
+		t.Fatalf("closed issues = %+v", result.Issues)
+	}
+}
+
+func TestGetIssue_NotFound(t *testing.T) {
+	srv, _ := newTestServer(t)
+	issues := data.NewIssueClient(httpQuerier(t, srv), "octo", "hello")
+
+	_, err := issues.Get(99)
+	var apiErr *data.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != data.ErrNotFound || apiErr.Resource != "issue" {
+		t.Fatalf("err = %v, want issue not found", err)
+	}
+}
+
+func TestComments_DeletedAuthor(t *testing.T) {
+	srv, _ := newTestServer(t)
+	comments := data.NewCommentClient(httpQuerier(t, srv), "octo", "hello")
+
+	result, err := comments.List(1, 10, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(result.Comments) != 2 || result.Comments[0].Author != "hubot" || result.Comments[1].Author != "[deleted]" {
+		t.Fatalf("comments = %+v", result.Comments)
+	}
+}
+
+func TestCreateIssue_ThenListed(t *testing.T) {
+	srv, _ := newTestServer(t)
+	q := httpQuerier(t, srv)
+	rest, err := srv.RESTClient("github.com")
+	if err != nil {
+		t.Fatalf("RESTClient: %v", err)
+	}
+
+	labels := data.NewLabelClient(q, "octo", "hello")
+	label, err := labels.Create("", "area/ui", "ededed")
+	if err != nil {
+		t.Fatalf("Create label: %v", err)
+	}
+	if _, err := labels.Create("", "area/ui", "ededed"); err == nil {
+		t.Fatal("expected duplicate label to fail")
+	}
+	milestone, err := data.NewMilestoneClient(q, rest, "octo", "hello").Create("v2")
+	if err != nil {
+		t.Fatalf("Create milestone: %v", err)
+	}
+	userID, err := data.NewUserClient(q).ID("octocat")
+	if err != nil {
+		t.Fatalf("user ID: %v", err)
+	}
+
+	issues := data.NewIssueClient(q, "octo", "hello")
+	created, err := issues.Create(data.IssueCreateInput{
+		Title:       "New",
+		LabelIDs:    []string{label.ID},
+		AssigneeIDs: []string{userID},
+		MilestoneID: milestone.ID,
+	})
+	if err != nil {
+		t.Fatalf("Create issue: %v", err)
+	}
+	if created.Number != 6 {
+		t.Errorf("number = %d, want 6", created.Number)
+	}
+
+	got, err := issues.Get(6)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Milestone != "v2" || len(got.Labels) != 1 || got.Labels[0].Name != "area/ui" || got.Assignees[0] != "octocat" {
+		t.Fatalf("issue = %+v", got)
+	}
+}
+
+func TestFail_RetriedByRateLimitQuerier(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.Fail("ListIssues", Failure{Status: http.StatusBadGateway, Message: "Bad Gateway", Times: 2})
+
+	q := data.NewRateLimitQuerier(httpQuerier(t, srv))
+	q.BaseDelay = time.Millisecond
+	q.MaxDelay = time.Millisecond
+	var budgets []data.RateLimit
+	q.OnRateLimit = func(rl data.RateLimit) { budgets = append(budgets, rl) }
+
+	result, err := data.NewIssueClient(q, "octo", "hello").List(data.IssueListOptions{First: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(result.Issues) != 5 {
+		t.Errorf("issues = %d, want 5", len(result.Issues))
+	}
+	if ops := srv.Operations(); len(ops) != 3 {
+		t.Errorf("operations = %v, want three attempts", ops)
+	}
+	if len(budgets) != 1 || budgets[0].Limit != 5000 || budgets[0].Remaining != 4997 {
+		t.Errorf("budgets = %+v", budgets)
+	}
+}
+
+func TestFail_PartialData(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.Fail("GetIssue", Failure{
+		Type:    "FORBIDDEN",
+		Message: "Resource not accessible",
+		Path:    []interface{}{"repository", "issue", "milestone"},
+		Partial: true,
+	})
+
+	issue, err := data.NewIssueClient(srv, "octo", "hello").Get(1)
+	if !data.IsPartialData(err) {
+		t.Fatalf("err = %v, want partial data", err)
+	}
+	if issue.Title != "Issue A" {
+		t.Errorf("title = %q", issue.Title)
+	}
+}
+
+func TestFail_DelayHonorsTimeout(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.Fail("", Failure{Delay: time.Second})
+
+	q := data.NewTimeoutQuerier(httpQuerier(t, srv), 10*time.Millisecond)
+	_, err := data.NewIssueClient(q, "octo", "hello").List(data.IssueListOptions{First: 10})
+	var apiErr *data.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != data.ErrNetwork {
+		t.Fatalf("err = %v, want network error", err)
+	}
+}
+
+func TestRateLimit_Exhausted(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetRateLimit(RateLimit{Limit: 5000, Remaining: 0, ResetAt: time.Now().Add(time.Hour)})
+
+	_, err := data.NewIssueClient(httpQuerier(t, srv), "octo", "hello").List(data.IssueListOptions{First: 10})
+	var apiErr *data.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != data.ErrRateLimited {
+		t.Fatalf("err = %v, want rate limited", err)
+	}
+
+	rl, err := data.NewRateLimitClient(srv).Get()
+	if err == nil {
+		t.Fatalf("rate limit query succeeded with %+v", rl)
+	}
+}
+
+func TestRateLimit_RefillsAfterReset(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.SetRateLimit(RateLimit{Limit: 5000, Remaining: 0, ResetAt: Epoch})
+
+	rl, err := data.NewRateLimitClient(srv).Get()
+	if err != nil {
+		t.Fatalf("rate limit query after reset: %v", err)
+	}
+	if rl.Remaining != 4999 || !rl.ResetAt.Equal(Epoch.Add(time.Hour)) {
+		t.Errorf("rate limit = %+v", rl)
+	}
+}
+
+func TestDoWithContext_Cancelled(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.Fail("ListIssues", Failure{Delay: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := data.NewIssueClient(srv, "octo", "hello").ListContext(ctx, data.IssueListOptions{First: 10})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestServeHTTP_RequiresToken(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp, err := http.Post(srv.URL()+"/graphql", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestEnterpriseHost(t *testing.T) {
+	srv, _ := newTestServer(t)
+	client, err := srv.GraphQLClient("ghe.example.com")
+	if err != nil {
+		t.Fatalf("GraphQLClient: %v", err)
+	}
+
+	login, err := data.NewUserClient(client).WhoAmI()
+	if err != nil || login != "octocat" {
+		t.Fatalf("WhoAmI = %q, %v", login, err)
+	}
+}
+
+func TestReactions_ToggleOnIssueAndComment(t *testing.T) {
+	srv, repo := newTestServer(t)
+	repo.Issue(1).Reactions = []Reaction{{Content: "HEART", User: "hubot"}}
+	q := httpQuerier(t, srv)
+	reactions := data.NewReactionClient(q)
+
+	issue, err := data.NewIssueClient(q, "octo", "hello").Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	want := []data.ReactionGroup{{Content: "HEART", Count: 1}}
+	if !reflect.DeepEqual(issue.ReactionGroups, want) {
+		t.Fatalf("issue groups = %+v, want %+v", issue.ReactionGroups, want)
+	}
+
+	groups, err := reactions.Add(issue.ID, "HEART")
+	want = []data.ReactionGroup{{Content: "HEART", Count: 2, ViewerHasReacted: true}}
+	if err != nil || !reflect.DeepEqual(groups, want) {
+		t.Fatalf("Add = %+v, %v, want %+v", groups, err, want)
+	}
+
+	comments, err := data.NewCommentClient(q, "octo", "hello").List(1, 10, "")
+	if err != nil {
+		t.Fatalf("List comments: %v", err)
+	}
+	commentID := comments.Comments[0].ID
+	if _, err := reactions.Add(commentID, "ROCKET"); err != nil {
+		t.Fatalf("Add to comment: %v", err)
+	}
+	groups, err = reactions.Remove(commentID, "ROCKET")
+	if err != nil || len(groups) != 0 {
+		t.Fatalf("Remove = %+v, %v", groups, err)
+	}
+	if got := repo.Issue(1).Reactions; len(got) != 2 {
+		t.Errorf("issue reactions = %+v", got)
+	}
+
+	if _, err := reactions.Add("I_missing", "HEART"); err == nil {
+		t.Error("expected reacting to a missing subject to fail")
+	}
+	if _, err := reactions.Add(issue.ID, "PARTY_PARROT"); err == nil {
+		t.Error("expected an unknown reaction to fail")
+	}
+}
+
+func TestComments_AddUpdateDelete(t *testing.T) {
+	srv, repo := newTestServer(t)
+	q := httpQuerier(t, srv)
+	comments := data.NewCommentClient(q, "octo", "hello")
+
+	issue, err := data.NewIssueClient(q, "octo", "hello").Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if issue.URL != "https://github.com/octo/hello/issues/1" {
+		t.Errorf("issue URL = %q", issue.URL)
+	}
+
+	added, err := comments.Add(issue.ID, "Looking into it")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if added.Author != "octocat" || !added.ViewerDidAuthor || !strings.HasPrefix(added.URL, issue.URL+"#issuecomment-") {
+		t.Errorf("added = %+v", added)
+	}
+
+	updated, err := comments.Update(added.ID, "Fixed the typo")
+	if err != nil || updated.Body != "Fixed the typo" {
+		t.Fatalf("Update = %+v, %v", updated, err)
+	}
+
+	list, err := comments.List(1, 10, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	hubot := list.Comments[0]
+	if hubot.ViewerDidAuthor || hubot.ViewerCanUpdate || hubot.ViewerCanDelete || hubot.ViewerCanMinimize {
+		t.Errorf("hubot's comment is changeable by the viewer: %+v", hubot)
+	}
+	if _, err := comments.Update(hubot.ID, "Hijacked"); !isKind(err, data.ErrPermission) {
+		t.Errorf("updating someone else's comment: err = %v, want a permission error", err)
+	}
+	if _, err := comments.Add(issue.ID, "  "); !isKind(err, data.ErrValidation) {
+		t.Errorf("adding a blank comment: err = %v, want a validation error", err)
+	}
+
+	if err := comments.Delete(added.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := len(repo.Issue(1).Comments); got != 2 {
+		t.Errorf("comments after delete = %d, want the 2 seeded", got)
+	}
+	if err := comments.Delete(added.ID); !isKind(err, data.ErrNotFound) {
+		t.Errorf("deleting twice: err = %v, want not found", err)
+	}
+}
+
+func TestComments_MaintainersMinimize(t *testing.T) {
+	srv, repo := newTestServer(t)
+	q := httpQuerier(t, srv)
+	comments := data.NewCommentClient(q, "octo", "hello")
+
+	hubot := repo.Issue(1).Comments[0].ID
+	if _, err := comments.Minimize(hubot, "SPAM"); !isKind(err, data.ErrPermission) {
+		t.Errorf("minimizing as a non-maintainer: err = %v, want a permission error", err)
+	}
+
+	repo.Maintainers = []string{"octocat"}
+	list, err := comments.List(1, 10, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if c := list.Comments[0]; !c.ViewerCanUpdate || !c.ViewerCanDelete || !c.ViewerCanMinimize {
+		t.Errorf("maintainer permissions = %+v", c)
+	}
+
+	minimized, err := comments.Minimize(hubot, "OFF_TOPIC")
+	if err != nil {
+		t.Fatalf("Minimize: %v", err)
+	}
+	if !minimized.IsMinimized || minimized.MinimizedReason != "off-topic" {
+		t.Errorf("minimized = %v, %q, want off-topic", minimized.IsMinimized, minimized.MinimizedReason)
+	}
+	if _, err := comments.Minimize(hubot, "BORING"); !isKind(err, data.ErrValidation) {
+		t.Errorf("unknown classifier: err = %v, want a validation error", err)
+	}
+
+	shown, err := comments.Unminimize(hubot)
+	if err != nil {
+		t.Fatalf("Unminimize: %v", err)
+	}
+	if shown.IsMinimized || shown.MinimizedReason != "" {
+		t.Errorf("unminimized = %v, %q", shown.IsMinimized, shown.MinimizedReason)
+	}
+}
+
+func isKind(err error, kind data.ErrorKind) bool {
+	var apiErr *data.Error
+	return errors.As(err, &apiErr) && apiErr.Kind == kind
+}
+
+func TestLabels_AddAndRemoveFromIssue(t *testing.T) {
+	srv, repo := newTestServer(t)
+	q := httpQuerier(t, srv)
+	labels := data.NewLabelClient(q, "octo", "hello")
+	doing := repo.AddLabel("status/doing", "fbca04")
+	bug := repo.label("bug")
+	issue := repo.Issue(2)
+
+	if err := labels.RemoveFromIssue(issue.ID, []string{bug.ID}); err != nil {
+		t.Fatalf("RemoveFromIssue: %v", err)
+	}
+	if err := labels.AddToIssue(issue.ID, []string{doing.ID}); err != nil {
+		t.Fatalf("AddToIssue: %v", err)
+	}
+	if got := strings.Join(repo.Issue(2).Labels, ","); got != "status/doing" {
+		t.Errorf("labels = %s, want status/doing", got)
+	}
+	if n := len(repo.Issue(2).Events); n != 2 {
+		t.Errorf("events = %d, want an unlabeled and a labeled event", n)
+	}
+	if err := labels.AddToIssue("I_missing", []string{doing.ID}); !isKind(err, data.ErrNotFound) {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestProjects_ReadAndSetSingleSelect(t *testing.T) {
+	srv, repo := newTestServer(t)
+	project := srv.Store().AddProject("octo", "Roadmap")
+	status := project.AddField("Status", "SINGLE_SELECT", "Todo", "Doing", "Done")
+	project.AddField("Estimate", "NUMBER")
+	item := project.AddItem(repo, 1, map[string]string{"Status": "Todo"})
+	project.AddItem(repo, 2, nil)
+	other := srv.Store().AddRepo("octo", "other")
+	other.AddIssue(Issue{Title: "Elsewhere"})
+	project.AddItem(other, 1, map[string]string{"Status": "Done"})
+
+	projects := data.NewProjectClient(httpQuerier(t, srv), "octo", "hello")
+	field, err := projects.Field(1, "Status")
+	if err != nil {
+		t.Fatalf("Field: %v", err)
+	}
+	if field.ProjectTitle != "Roadmap" || len(field.Options) != 3 || field.Options[2].Name != "Done" {
+		t.Fatalf("field = %+v", field)
+	}
+	if _, err := projects.Field(1, "Estimate"); !isKind(err, data.ErrNotFound) {
+		t.Errorf("number field: err = %v, want not found", err)
+	}
+	if _, err := projects.Field(9, "Status"); !isKind(err, data.ErrNotFound) {
+		t.Errorf("missing project: err = %v, want not found", err)
+	}
+
+	items, err := projects.Items(field, 100)
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	if len(items) != 2 || items[0].OptionID != status.Options[0].ID || items[1].OptionID != "" {
+		t.Fatalf("items = %+v", items)
+	}
+
+	if err := projects.SetOption(field, item.ID, status.Options[1].ID); err != nil {
+		t.Fatalf("SetOption: %v", err)
+	}
+	if item.Values["Status"] != "Doing" {
+		t.Errorf("status = %q, want Doing", item.Values["Status"])
+	}
+	if err := projects.SetOption(field, item.ID, "PVTO_missing"); !isKind(err, data.ErrValidation) {
+		t.Errorf("err = %v, want a validation error", err)
+	}
+}
+
+func TestProjects_IssueValuesAndSetEachType(t *testing.T) {
+	srv, repo := newTestServer(t)
+	project := srv.Store().AddProject("octo", "Roadmap")
+	project.AddField("Priority", "SINGLE_SELECT", "P0", "P1")
+	project.AddField("Iteration", "ITERATION", "Sprint 1", "Sprint 2")
+	project.AddField("Estimate", "NUMBER")
+	project.AddField("Due", "DATE")
+	project.AddField("Notes", "TEXT")
+	item := project.AddItem(repo, 1, map[string]string{"Priority": "P1", "Estimate": "3"})
+
+	issues := data.NewIssueClient(httpQuerier(t, srv), "octo", "hello")
+	issue, err := issues.Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(issue.Projects) != 0 {
+		t.Errorf("Get loaded projects %+v", issue.Projects)
+	}
+	loaded := []data.Issue{issue}
+	if err := issues.LoadProjects(loaded); err != nil {
+		t.Fatalf("LoadProjects: %v", err)
+	}
+	issue = loaded[0]
+	if len(issue.Projects) != 1 || issue.Projects[0].ItemID != item.ID || len(issue.Projects[0].Values) != 2 {
+		t.Fatalf("projects = %+v", issue.Projects)
+	}
+	if v, _ := issue.ProjectValue("Estimate"); v.Text != "3" {
+		t.Errorf("estimate = %+v", v)
+	}
+
+	projects := data.NewProjectClient(httpQuerier(t, srv), "octo", "hello")
+	fields, err := projects.Fields(project.ID)
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if len(fields) != 5 {
+		t.Fatalf("fields = %+v", fields)
+	}
+	values := map[string]string{
+		"Priority":  fields[0].Options[0].ID,
+		"Iteration": fields[1].Options[1].ID,
+		"Estimate":  "0.5",
+		"Due":       "2026-11-01",
+		"Notes":     "needs design",
+	}
+	for _, f := range fields {
+		if _, err := projects.SetValue(f, item.ID, values[f.Name]); err != nil {
+			t.Fatalf("SetValue(%s): %v", f.Name, err)
+		}
+	}
+	want := map[string]string{"Priority": "P0", "Iteration": "Sprint 2", "Estimate": "0.5", "Due": "2026-11-01", "Notes": "needs design"}
+	for name, v := range want {
+		if item.Values[name] != v {
+			t.Errorf("%s = %q, want %q", name, item.Values[name], v)
+		}
+	}
+
+	if _, err := projects.SetValue(fields[2], item.ID, ""); err != nil {
+		t.Fatalf("clearing: %v", err)
+	}
+	if _, ok := item.Values["Estimate"]; ok {
+		t.Errorf("estimate was not cleared: %+v", item.Values)
+	}
+}
+
+func TestSubIssuesAndEditBody(t *testing.T) {
+	srv, repo := newTestServer(t)
+	other := srv.Store().AddRepo("octo", "other")
+	elsewhere := other.AddIssue(Issue{Title: "Elsewhere"})
+	repo.AddSubIssue(1, repo.Issue(5))
+	repo.AddSubIssue(1, elsewhere)
+	repo.AddSubIssue(5, repo.Issue(2))
+
+	issues := data.NewIssueClient(httpQuerier(t, srv), "octo", "hello")
+	parent, err := issues.Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if parent.SubIssueProgress != (data.SubIssueProgress{Total: 2, Completed: 1}) || len(parent.SubIssues) != 2 {
+		t.Fatalf("parent = %+v", parent)
+	}
+	if sub := parent.SubIssues[0]; sub.Number != 5 || sub.SubIssueProgress.Total != 1 {
+		t.Errorf("first sub-issue = %+v", sub)
+	}
+	if sub := parent.SubIssues[1]; sub.Repo != "octo/other" {
+		t.Errorf("second sub-issue = %+v", sub)
+	}
+
+	child, err := issues.Get(5)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if child.Parent == nil || child.Parent.Number != 1 || child.Parent.Repo != "octo/hello" {
+		t.Errorf("parent of #5 = %+v", child.Parent)
+	}
+
+	list, err := issues.List(data.IssueListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	for _, i := range list.Issues {
+		if i.Number == 1 && i.SubIssueProgress.Total != 2 {
+			t.Errorf("listed #1 progress = %+v", i.SubIssueProgress)
+		}
+	}
+
+	repo.Issue(2).Body = "- [ ] one"
+	if _, err := issues.EditBody(2, "- [ ] stale", "- [x] stale"); !isKind(err, data.ErrConflict) {
+		t.Errorf("stale edit: err = %v, want a conflict", err)
+	}
+	body, err := issues.EditBody(2, "- [ ] one", "- [x] one")
+	if err != nil || body != "- [x] one" || repo.Issue(2).Body != "- [x] one" {
+		t.Errorf("EditBody = %q, %v; stored %q", body, err, repo.Issue(2).Body)
+	}
+}
+
+func TestIssueTypes(t *testing.T) {
+	srv, repo := newTestServer(t)
+	srv.Store().AddIssueType("octo", "Bug", "RED", "Something isn't working")
+	feature := srv.Store().AddIssueType("octo", "Feature", "BLUE", "")
+	srv.Store().AddIssueType("someone-else", "Chore", "GRAY", "")
+	repo.Issue(1).Type = "Bug"
+
+	q := httpQuerier(t, srv)
+	types, err := data.NewIssueTypeClient(q, "octo", "hello").List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(types) != 2 || types[0].Name != "Bug" || types[1].Name != "Feature" {
+		t.Fatalf("types = %+v", types)
+	}
+
+	issue, err := data.NewIssueClient(q, "octo", "hello").Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if issue.Type.Name != "Bug" || issue.Type.Color != "cf222e" {
+		t.Errorf("type = %+v", issue.Type)
+	}
+
+	client := data.NewIssueTypeClient(q, "octo", "hello")
+	set, err := client.SetType(issue.ID, feature.ID)
+	if err != nil || set.Name != "Feature" || repo.Issue(1).Type != "Feature" {
+		t.Errorf("SetType = %+v, %v; stored %q", set, err, repo.Issue(1).Type)
+	}
+	if _, err := client.SetType(issue.ID, ""); err != nil || repo.Issue(1).Type != "" {
+		t.Errorf("clearing: %v; stored %q", err, repo.Issue(1).Type)
+	}
+	if _, err := client.SetType(issue.ID, "IT_missing"); !isKind(err, data.ErrNotFound) {
+		t.Errorf("unknown type: err = %v, want not found", err)
+	}
+}
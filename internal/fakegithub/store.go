@@ -0,0 +1,357 @@
+// Package fakegithub is an in-memory stand-in for the GitHub GraphQL API.
+//
+// A Store holds repositories with issues, comments, labels, and milestones.
+// A Server answers the GraphQL operations the data package sends, either
+// in-process as a querier or over HTTP for go-gh clients, and can simulate
+// rate limits and failures so that multi-step flows are testable offline.
+// NewDemoStore generates the repository used by --demo.
+package fakegithub
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ghost is the login of deleted accounts. Objects authored by Ghost are
+// served with a null author, as GitHub does.
+const Ghost = "ghost"
+
+// Epoch is the default clock of a new Store, so that timestamps are stable.
+var Epoch = time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// Store is an in-memory set of GitHub repositories and users. Its methods
+// are safe to call while a Server is answering requests.
+type Store struct {
+	mu     sync.Mutex
+	viewer string
+	repos  map[string]*Repo
+	users  map[string]*User
+	// projects are Projects v2 projects, owned by users or organizations.
+	projects []*Project
+	nextID   int
+	now      func() time.Time
+
+	// issueTypes are the issue types of organizations.
+	issueTypes []*IssueType
+}
+
+// Repo is a repository in the store.
+type Repo struct {
+	ID         string
+	Owner      string
+	Name       string
+	Issues     []*Issue
+	Labels     []*Label
+	Milestones []*Milestone
+	// Maintainers may edit, delete, and minimize anyone's comments. The
+	// owner is always a maintainer.
+	Maintainers []string
+
+	store *Store
+}
+
+// Issue is an issue in a repository. Labels, Assignees, Milestone, and Type
+// refer to labels, users, milestones, and issue types by name, login, title,
+// and name.
+type Issue struct {
+	ID        string
+	Number    int
+	Title     string
+	Body      string
+	State     string // "OPEN" or "CLOSED"
+	Author    string // Ghost for a deleted account
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Labels    []string
+	Assignees []string
+	Milestone string
+	Type      string
+	Reactions []Reaction
+	Comments  []*Comment
+	Events    []*Event
+	// SubIssues may be issues of other repositories.
+	SubIssues []*Issue
+}
+
+// Comment is a comment on an issue.
+type Comment struct {
+	ID        string
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Reactions []Reaction
+	// MinimizedReason is the classifier the comment was minimized with,
+	// such as "OFF_TOPIC", or empty if it is shown.
+	MinimizedReason string
+}
+
+// Reaction is a user's emoji reaction, such as "THUMBS_UP", to an issue or
+// comment.
+type Reaction struct {
+	Content string
+	User    string
+}
+
+// Event is an issue timeline event, such as "LabeledEvent" or
+// "ClosedEvent". Only the fields that apply to the type are served.
+type Event struct {
+	Type          string
+	Actor         string
+	CreatedAt     time.Time
+	Label         string
+	Assignee      string
+	Milestone     string
+	StateReason   string
+	PreviousTitle string
+	CurrentTitle  string
+}
+
+// Label is a repository label.
+type Label struct {
+	ID    string
+	Name  string
+	Color string
+}
+
+// Milestone is a repository milestone.
+type Milestone struct {
+	ID     string
+	Number int
+	Title  string
+	State  string
+}
+
+// User is a GitHub account.
+type User struct {
+	ID    string
+	Login string
+}
+
+// NewStore creates an empty store whose viewer is "octocat" and whose clock
+// is fixed at Epoch.
+func NewStore() *Store {
+	s := &Store{
+		repos: map[string]*Repo{},
+		users: map[string]*User{},
+		now:   func() time.Time { return Epoch },
+	}
+	s.viewer = s.addUser("octocat").Login
+	return s
+}
+
+// SetClock replaces the clock used to timestamp new objects.
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetViewer sets the login of the authenticated user, adding the user if
+// needed.
+func (s *Store) SetViewer(login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.viewer = s.addUser(login).Login
+}
+
+// AddUser adds a user, or returns the existing one with that login.
+func (s *Store) AddUser(login string) *User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(login)
+}
+
+func (s *Store) addUser(login string) *User {
+	if u, ok := s.users[strings.ToLower(login)]; ok {
+		return u
+	}
+	u := &User{ID: s.newID("U"), Login: login}
+	s.users[strings.ToLower(login)] = u
+	return u
+}
+
+// AddRepo adds an empty repository.
+func (s *Store) AddRepo(owner, name string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := &Repo{ID: s.newID("R"), Owner: owner, Name: name, store: s}
+	s.repos[repoKey(owner, name)] = r
+	return r
+}
+
+// Repo returns the repository with the given owner and name, or nil.
+func (s *Store) Repo(owner, name string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.repos[repoKey(owner, name)]
+}
+
+func (s *Store) repoByID(id string) *Repo {
+	for _, r := range s.repos {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *Store) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s_%d", prefix, s.nextID)
+}
+
+func repoKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
+
+// AddIssue adds an issue. Zero fields get defaults: the next number, the
+// OPEN state, the viewer as author, and the store's clock as timestamps.
+func (r *Repo) AddIssue(issue Issue) *Issue {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.addIssue(issue)
+}
+
+func (r *Repo) addIssue(issue Issue) *Issue {
+	i := issue
+	i.ID = r.store.newID("I")
+	if i.Number == 0 {
+		i.Number = r.nextNumber()
+	}
+	if i.State == "" {
+		i.State = "OPEN"
+	}
+	if i.Author == "" {
+		i.Author = r.store.viewer
+	}
+	if i.CreatedAt.IsZero() {
+		i.CreatedAt = r.store.now()
+	}
+	if i.UpdatedAt.IsZero() {
+		i.UpdatedAt = i.CreatedAt
+	}
+	r.Issues = append(r.Issues, &i)
+	return &i
+}
+
+func (r *Repo) nextNumber() int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Number > n {
+			n = i.Number
+		}
+	}
+	return n + 1
+}
+
+// Issue returns the issue with the given number, or nil.
+func (r *Repo) Issue(number int) *Issue {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.issue(number)
+}
+
+func (r *Repo) issue(number int) *Issue {
+	for _, i := range r.Issues {
+		if i.Number == number {
+			return i
+		}
+	}
+	return nil
+}
+
+// DeleteIssue removes the issue with the given number, as if it had been
+// deleted or transferred.
+func (r *Repo) DeleteIssue(number int) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for n, i := range r.Issues {
+		if i.Number == number {
+			r.Issues = append(r.Issues[:n], r.Issues[n+1:]...)
+			return
+		}
+	}
+}
+
+// AddLabel adds a label.
+func (r *Repo) AddLabel(name, color string) *Label {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.addLabel(name, color)
+}
+
+func (r *Repo) addLabel(name, color string) *Label {
+	l := &Label{ID: r.store.newID("LA"), Name: name, Color: color}
+	r.Labels = append(r.Labels, l)
+	return l
+}
+
+func (r *Repo) label(name string) *Label {
+	for _, l := range r.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return l
+		}
+	}
+	return nil
+}
+
+// AddMilestone adds an open milestone.
+func (r *Repo) AddMilestone(title string) *Milestone {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.addMilestone(title)
+}
+
+func (r *Repo) addMilestone(title string) *Milestone {
+	m := &Milestone{ID: r.store.newID("MI"), Number: len(r.Milestones) + 1, Title: title, State: "OPEN"}
+	r.Milestones = append(r.Milestones, m)
+	return m
+}
+
+// AddComment adds a comment to the issue with the given number. Zero fields
+// default as in AddIssue.
+func (r *Repo) AddComment(number int, comment Comment) *Comment {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	issue := r.issue(number)
+	if issue == nil {
+		panic(fmt.Sprintf("fakegithub: no issue #%d in %s/%s", number, r.Owner, r.Name))
+	}
+	c := comment
+	c.ID = r.store.newID("IC")
+	if c.Author == "" {
+		c.Author = r.store.viewer
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = r.store.now()
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = c.CreatedAt
+	}
+	issue.Comments = append(issue.Comments, &c)
+	return &c
+}
+
+// AddEvent adds a timeline event to the issue with the given number.
+func (r *Repo) AddEvent(number int, event Event) *Event {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	issue := r.issue(number)
+	if issue == nil {
+		panic(fmt.Sprintf("fakegithub: no issue #%d in %s/%s", number, r.Owner, r.Name))
+	}
+	e := event
+	if e.Actor == "" {
+		e.Actor = r.store.viewer
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = r.store.now()
+	}
+	issue.Events = append(issue.Events, &e)
+	return &e
+}
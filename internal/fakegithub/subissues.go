@@ -0,0 +1,67 @@
+package fakegithub
+
+import "fmt"
+
+// AddSubIssue makes child, an issue of this or another repository, a
+// sub-issue of the issue with the given number.
+func (r *Repo) AddSubIssue(parent int, child *Issue) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	p := r.issue(parent)
+	if p == nil {
+		panic(fmt.Sprintf("fakegithub: no issue #%d in %s/%s", parent, r.Owner, r.Name))
+	}
+	p.SubIssues = append(p.SubIssues, child)
+}
+
+// parentOf returns the issue that i is a sub-issue of, and its repository.
+func (s *Store) parentOf(i *Issue) (*Repo, *Issue) {
+	for _, r := range s.repos {
+		for _, p := range r.Issues {
+			for _, sub := range p.SubIssues {
+				if sub == i {
+					return r, p
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func subIssuesSummaryJSON(i *Issue) obj {
+	completed := 0
+	for _, sub := range i.SubIssues {
+		if sub.State == "CLOSED" {
+			completed++
+		}
+	}
+	return obj{"total": len(i.SubIssues), "completed": completed}
+}
+
+// issueRefJSON serves an issue in a hierarchy, with its repository.
+func (s *Store) issueRefJSON(r *Repo, i *Issue) obj {
+	return obj{
+		"number":           i.Number,
+		"title":            i.Title,
+		"state":            i.State,
+		"repository":       obj{"nameWithOwner": r.Owner + "/" + r.Name},
+		"subIssuesSummary": subIssuesSummaryJSON(i),
+	}
+}
+
+// hierarchyJSON adds the parent and sub-issues of i to its JSON.
+func (s *Store) hierarchyJSON(i *Issue, issue obj) {
+	issue["subIssuesSummary"] = subIssuesSummaryJSON(i)
+	var parent interface{}
+	if r, p := s.parentOf(i); p != nil {
+		parent = s.issueRefJSON(r, p)
+	}
+	issue["parent"] = parent
+	nodes := make([]obj, 0, len(i.SubIssues))
+	for _, sub := range i.SubIssues {
+		if r, _ := s.issueByID(sub.ID); r != nil {
+			nodes = append(nodes, s.issueRefJSON(r, sub))
+		}
+	}
+	issue["subIssues"] = obj{"nodes": nodes}
+}
@@ -1,6 +1,11 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+
 	"github.com/cboone/gh-problemas/internal/data"
 	"github.com/cboone/gh-problemas/internal/ui/components"
 	"github.com/charmbracelet/bubbles/key"
@@ -16,6 +21,27 @@ type View interface {
 	KeyHints() []string
 }
 
+// Closer is implemented by views that hold resources, such as in-flight
+// requests, which must be released when the view leaves the stack.
+type Closer interface {
+	Close()
+}
+
+// RequestOwner is implemented by views that tag their fetches with request
+// IDs. Errors from requests the view no longer owns are not reported.
+type RequestOwner interface {
+	OwnsRequest(id int64) bool
+}
+
+var lastRequestID atomic.Int64
+
+// NextRequestID returns a process-wide unique ID for tagging a fetch and the
+// message that carries its result, so that views can discard responses to
+// requests they no longer care about.
+func NextRequestID() int64 {
+	return lastRequestID.Add(1)
+}
+
 // Navigation messages
 
 // NavigateToDetailMsg requests navigation to an issue detail view.
@@ -30,27 +56,138 @@ type NavigateBackMsg struct{}
 
 // IssuesLoadedMsg carries the result of loading issues.
 type IssuesLoadedMsg struct {
-	Result data.IssueListResult
-	Err    error
+	RequestID int64
+	Result    data.IssueListResult
+	Err       error
 }
 
 // IssuesPageLoadedMsg carries the result of loading an additional page of issues.
 type IssuesPageLoadedMsg struct {
-	Result data.IssueListResult
-	Err    error
+	RequestID int64
+	Result    data.IssueListResult
+	Err       error
 }
 
 // IssueDetailLoadedMsg carries the result of loading a single issue.
 type IssueDetailLoadedMsg struct {
-	Issue data.Issue
-	Err   error
+	RequestID int64
+	Issue     data.Issue
+	Err       error
 }
 
 // CommentsLoadedMsg carries the result of loading comments.
 type CommentsLoadedMsg struct {
-	Comments []data.Comment
-	PageInfo data.PageInfo
-	Err      error
+	RequestID int64
+	Comments  []data.Comment
+	PageInfo  data.PageInfo
+	Err       error
+}
+
+// TimelineLoadedMsg carries the result of loading timeline events.
+type TimelineLoadedMsg struct {
+	RequestID int64
+	Events    []data.TimelineEvent
+	PageInfo  data.PageInfo
+	Err       error
+}
+
+// CommentSavedMsg carries the result of posting a new comment, or of
+// editing one when Edited is set.
+type CommentSavedMsg struct {
+	RequestID int64
+	Comment   data.Comment
+	Edited    bool
+	Err       error
+}
+
+// CommentDeletedMsg carries the result of deleting the comment with node ID
+// CommentID.
+type CommentDeletedMsg struct {
+	RequestID int64
+	CommentID string
+	Err       error
+}
+
+// CommentMinimizedMsg carries the result of hiding or showing a comment.
+type CommentMinimizedMsg struct {
+	RequestID int64
+	Comment   data.Comment
+	Err       error
+}
+
+// ReactionsUpdatedMsg carries the result of adding or removing a reaction
+// on the issue or comment with node ID SubjectID.
+type ReactionsUpdatedMsg struct {
+	RequestID int64
+	SubjectID string
+	Content   string
+	Removed   bool
+	Groups    []data.ReactionGroup
+	Err       error
+}
+
+// BoardLoadedMsg carries what a board needs besides its issues: the labels
+// of a label board's columns, or the field and items of a project board.
+type BoardLoadedMsg struct {
+	RequestID int64
+	Labels    []data.Label
+	Field     data.ProjectField
+	Items     []data.ProjectItem
+	Err       error
+}
+
+// BoardMovedMsg reports that an issue was moved to another board column.
+type BoardMovedMsg struct {
+	RequestID   int64
+	IssueNumber int
+	Column      string
+	Err         error
+}
+
+// ProjectFieldsLoadedMsg carries the settable fields of the projects an
+// issue is on.
+type ProjectFieldsLoadedMsg struct {
+	RequestID int64
+	Fields    []data.ProjectField
+	Err       error
+}
+
+// ProjectFieldUpdatedMsg reports that a project item's field was set, or
+// cleared when Value.Text is empty.
+type ProjectFieldUpdatedMsg struct {
+	RequestID int64
+	ItemID    string
+	Value     data.ProjectValue
+	Err       error
+}
+
+// IssueTypesLoadedMsg carries the issue types available in the repository.
+type IssueTypesLoadedMsg struct {
+	RequestID int64
+	Types     []data.IssueType
+	Err       error
+}
+
+// IssueTypeUpdatedMsg reports that an issue's type was set, or removed when
+// Type is zero.
+type IssueTypeUpdatedMsg struct {
+	RequestID int64
+	Type      data.IssueType
+	Err       error
+}
+
+// IssueBodyUpdatedMsg carries the result of rewriting an issue's body, such
+// as to check a task. On a conflict, Body is the body now on GitHub.
+type IssueBodyUpdatedMsg struct {
+	RequestID int64
+	Body      string
+	Status    string
+	Err       error
+}
+
+// RateLimitMsg reports the GraphQL point budget after a request.
+type RateLimitMsg struct {
+	RateLimit data.RateLimit
 }
 
 // StatusLevel determines how status text is rendered.
@@ -99,6 +236,15 @@ type App struct {
 	repoName     string
 	initView     ViewFactory
 	detailViewFn DetailViewFactory
+	clipboard    func(string) error
+	browser      Browser
+	editor       Editor
+
+	// logger and logViewer are set in debug mode.
+	logger        *slog.Logger
+	logViewer     *components.LogViewer
+	showLog       bool
+	logGeneration int
 }
 
 // NewApp creates a new App with the given issue client, repo name, and view factories.
@@ -122,6 +268,7 @@ func NewApp(client *data.IssueClient, repoName string, initView ViewFactory, det
 		repoName:     repoName,
 		initView:     initView,
 		detailViewFn: dvFn,
+		clipboard:    copyToTerminal,
 	}
 }
 
@@ -132,14 +279,51 @@ func (a *App) PushView(v View) tea.Cmd {
 	return v.Init()
 }
 
-// PopView removes the top view from the stack if more than one view remains.
+// PopView removes the top view from the stack if more than one view remains,
+// closing it if it implements Closer.
 func (a *App) PopView() {
 	if len(a.viewStack) > 1 {
+		closeView(a.viewStack[len(a.viewStack)-1])
 		a.viewStack = a.viewStack[:len(a.viewStack)-1]
 		a.updateKeyHints()
 	}
 }
 
+// quit closes every view on the stack, cancelling their in-flight requests,
+// and exits the program.
+func (a *App) quit() tea.Cmd {
+	for i := len(a.viewStack) - 1; i >= 0; i-- {
+		closeView(a.viewStack[i])
+	}
+	return tea.Quit
+}
+
+func closeView(v View) {
+	if c, ok := v.(Closer); ok {
+		c.Close()
+	}
+}
+
+// setReadError shows err, the result of read request id, in the status bar.
+// Cancellations are deliberate, and the current view may have moved on from
+// the request, so neither is reported.
+func (a *App) setReadError(id int64, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if r, ok := a.CurrentView().(RequestOwner); ok && !r.OwnsRequest(id) {
+		return
+	}
+	a.statusBar.SetError(err)
+}
+
+// setWriteError shows err, the result of a mutation, in the status bar.
+// Mutations outlive the view that started them, so a failure is shown
+// wherever the user is; otherwise they would believe the write was made.
+func (a *App) setWriteError(err error) {
+	a.statusBar.SetError(err)
+}
+
 // CurrentView returns the top view on the stack, or nil.
 func (a *App) CurrentView() View {
 	if len(a.viewStack) == 0 {
@@ -154,6 +338,9 @@ func (a *App) ViewStackLen() int {
 }
 
 func (a *App) updateKeyHints() {
+	if a.showLog {
+		return
+	}
 	if v := a.CurrentView(); v != nil {
 		a.statusBar.SetKeyHints(v.KeyHints())
 	}
@@ -200,11 +387,16 @@ func (a *App) Init() tea.Cmd {
 
 // Update implements tea.Model.
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	a.logMsg(msg)
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height - 1 // Reserve 1 line for status bar
 		a.statusBar.SetWidth(msg.Width)
+		if a.logViewer != nil {
+			a.logViewer.SetSize(a.width, a.height)
+		}
 		// Propagate resize to current view
 		if v := a.CurrentView(); v != nil {
 			updated, cmd := v.Update(msg)
@@ -216,10 +408,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if key.Matches(msg, a.keys.ForceQuit) {
-			return a, tea.Quit
+			return a, a.quit()
+		}
+		if a.showLog {
+			return a, a.updateLog(msg)
+		}
+		if a.logViewer != nil && key.Matches(msg, a.keys.Logs) {
+			return a, a.toggleLog()
 		}
 		if key.Matches(msg, a.keys.Quit) && len(a.viewStack) <= 1 {
-			return a, tea.Quit
+			return a, a.quit()
 		}
 
 	case NavigateToDetailMsg:
@@ -248,24 +446,110 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.statusBar.SetInfo(msg.Text)
 		return a, nil
 
+	case CopyMsg:
+		a.copy(msg)
+		return a, nil
+
+	case OpenURLMsg:
+		return a, a.browse(msg)
+
+	case browsedMsg:
+		a.browsed(msg)
+		return a, nil
+
+	case EditMsg:
+		return a, a.edit(msg)
+
+	case logTickMsg:
+		if !a.showLog || msg.generation != a.logGeneration {
+			return a, nil
+		}
+		a.logViewer.Reload()
+		return a, a.logTick()
+
+	case RateLimitMsg:
+		rl := msg.RateLimit
+		a.statusBar.SetRateLimit(rl.Remaining, rl.Limit, rl.ResetAt)
+		return a, nil
+
 	case IssuesLoadedMsg:
 		if msg.Err != nil {
-			a.statusBar.SetError(msg.Err)
+			a.setReadError(msg.RequestID, msg.Err)
 		}
 
 	case IssuesPageLoadedMsg:
 		if msg.Err != nil {
-			a.statusBar.SetError(msg.Err)
+			a.setReadError(msg.RequestID, msg.Err)
 		}
 
 	case IssueDetailLoadedMsg:
 		if msg.Err != nil {
-			a.statusBar.SetError(msg.Err)
+			a.setReadError(msg.RequestID, msg.Err)
 		}
 
 	case CommentsLoadedMsg:
 		if msg.Err != nil {
-			a.statusBar.SetError(msg.Err)
+			a.setReadError(msg.RequestID, msg.Err)
+		}
+
+	case TimelineLoadedMsg:
+		if msg.Err != nil {
+			a.setReadError(msg.RequestID, msg.Err)
+		}
+
+	case CommentSavedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case CommentDeletedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case CommentMinimizedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case ReactionsUpdatedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case BoardLoadedMsg:
+		if msg.Err != nil {
+			a.setReadError(msg.RequestID, msg.Err)
+		}
+
+	case BoardMovedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case ProjectFieldsLoadedMsg:
+		if msg.Err != nil {
+			a.setReadError(msg.RequestID, msg.Err)
+		}
+
+	case ProjectFieldUpdatedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case IssueTypesLoadedMsg:
+		if msg.Err != nil {
+			a.setReadError(msg.RequestID, msg.Err)
+		}
+
+	case IssueTypeUpdatedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
+		}
+
+	case IssueBodyUpdatedMsg:
+		if msg.Err != nil {
+			a.setWriteError(msg.Err)
 		}
 	}
 
@@ -287,6 +571,9 @@ func (a *App) View() string {
 	}
 
 	viewContent := a.CurrentView().View()
+	if a.showLog {
+		viewContent = a.logViewer.View()
+	}
 	viewHeight := a.height
 	content := lipgloss.NewStyle().Height(viewHeight).Render(viewContent)
 	return content + "\n" + a.statusBar.View()
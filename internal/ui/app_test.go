@@ -1,8 +1,16 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/cboone/gh-problemas/internal/data"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -110,3 +118,158 @@ func TestQuit_OnDetailView_DelegatesToView(t *testing.T) {
 		}
 	}
 }
+
+// closingView records whether it was closed.
+type closingView struct {
+	mockView
+	closed bool
+}
+
+func (v *closingView) Close() { v.closed = true }
+
+func TestPopView_ClosesView(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	dashboard := &closingView{mockView: mockView{name: "dashboard"}}
+	detail := &closingView{mockView: mockView{name: "detail"}}
+	app.PushView(dashboard)
+	app.PushView(detail)
+
+	app.Update(NavigateBackMsg{})
+	if !detail.closed {
+		t.Fatal("expected popped view to be closed")
+	}
+	if dashboard.closed {
+		t.Fatal("expected remaining view to stay open")
+	}
+}
+
+func TestQuit_ClosesAllViews(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	dashboard := &closingView{mockView: mockView{name: "dashboard"}}
+	detail := &closingView{mockView: mockView{name: "detail"}}
+	app.PushView(dashboard)
+	app.PushView(detail)
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !dashboard.closed || !detail.closed {
+		t.Fatal("expected every view to be closed on quit")
+	}
+}
+
+func TestCancelledRequest_NotShownAsError(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	app.PushView(&mockView{name: "dashboard"})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+
+	app.Update(IssueDetailLoadedMsg{Err: fmt.Errorf("fetching: %w", context.Canceled)})
+	if strings.Contains(app.statusBar.View(), "canceled") {
+		t.Fatalf("expected cancellation to be ignored, got %q", app.statusBar.View())
+	}
+
+	app.Update(IssueDetailLoadedMsg{Err: context.DeadlineExceeded})
+	if !strings.Contains(app.statusBar.View(), "timed out") {
+		t.Fatalf("expected timeout to be reported, got %q", app.statusBar.View())
+	}
+}
+
+// ownerView is a mockView that owns a single request.
+type ownerView struct {
+	mockView
+	requestID int64
+}
+
+func (v *ownerView) Update(tea.Msg) (View, tea.Cmd) { return v, nil }
+func (v *ownerView) OwnsRequest(id int64) bool      { return id == v.requestID }
+
+func TestStaleRequestError_NotShown(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	app.PushView(&ownerView{mockView: mockView{name: "detail"}, requestID: 2})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+
+	app.Update(IssueDetailLoadedMsg{RequestID: 1, Err: errors.New("stale failure")})
+	if strings.Contains(app.statusBar.View(), "stale failure") {
+		t.Fatalf("expected error from a stale request to be ignored, got %q", app.statusBar.View())
+	}
+
+	app.Update(IssueDetailLoadedMsg{RequestID: 2, Err: errors.New("current failure")})
+	if !strings.Contains(app.statusBar.View(), "current failure") {
+		t.Fatalf("expected error from the current request, got %q", app.statusBar.View())
+	}
+}
+
+func TestStaleMutationError_Shown(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	app.PushView(&ownerView{mockView: mockView{name: "dashboard"}, requestID: 2})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+
+	app.Update(CommentSavedMsg{RequestID: 1, Err: errors.New("comment not saved")})
+	if !strings.Contains(app.statusBar.View(), "comment not saved") {
+		t.Fatalf("expected a failed write to be reported after moving on, got %q", app.statusBar.View())
+	}
+}
+
+func TestNextRequestID_Increases(t *testing.T) {
+	first := NextRequestID()
+	if second := NextRequestID(); second <= first {
+		t.Fatalf("expected increasing request IDs, got %d then %d", first, second)
+	}
+}
+
+func TestRateLimitMsg_UpdatesStatusBar(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	app.PushView(&mockView{name: "dashboard"})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+
+	app.Update(RateLimitMsg{RateLimit: data.RateLimit{Limit: 5000, Remaining: 4321}})
+	if !strings.Contains(app.statusBar.View(), "4321/5000 pts") {
+		t.Fatalf("expected budget in status bar, got %q", app.statusBar.View())
+	}
+}
+
+func TestDebugLog_LogsMessagesAndShowsOverlay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	app := NewApp(nil, "owner/repo", nil)
+	app.SetDebugLog(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})), path)
+	app.PushView(&mockView{name: "dashboard"})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 10})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	app.Update(IssuesLoadedMsg{RequestID: 42, Err: errors.New("boom")})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("`")})
+	if cmd == nil {
+		t.Fatal("expected opening the log to schedule a refresh")
+	}
+	view := app.View()
+	for _, want := range []string{"Debug log: " + path, "DEBUG update key=j type=tea.KeyMsg", "request_id=42", "error=boom"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("log overlay lacks %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "dashboard\n") {
+		t.Errorf("overlay shows the view underneath:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if view := app.View(); !strings.HasPrefix(view, "dashboard") {
+		t.Errorf("expected esc to close the overlay, got:\n%s", view)
+	}
+	if _, cmd := app.Update(logTickMsg{generation: app.logGeneration}); cmd != nil {
+		t.Error("expected no refresh after the overlay is closed")
+	}
+}
+
+func TestDebugLog_KeyDisabledWithoutLog(t *testing.T) {
+	app := NewApp(nil, "owner/repo", nil)
+	app.PushView(&mockView{name: "dashboard"})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("`")})
+	if view := app.View(); !strings.HasPrefix(view, "dashboard") {
+		t.Errorf("expected the view without debug mode, got:\n%s", view)
+	}
+}
@@ -0,0 +1,174 @@
+package components
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/utils"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxLogLines is the number of lines a LogViewer keeps from the end of its
+// file.
+const maxLogLines = 500
+
+// LogViewer shows the end of a JSON log file, one formatted entry per line.
+// It follows new entries while scrolled to the bottom.
+type LogViewer struct {
+	path     string
+	viewport viewport.Model
+	style    lipgloss.Style
+	lines    []string
+	err      error
+}
+
+// NewLogViewer creates a viewer for the log file at path.
+func NewLogViewer(path string, titleStyle lipgloss.Style) *LogViewer {
+	return &LogViewer{path: path, viewport: viewport.New(0, 0), style: titleStyle}
+}
+
+// SetSize sets the size of the viewer, including its title line.
+func (l *LogViewer) SetSize(width, height int) {
+	l.viewport.Width = width
+	l.viewport.Height = max(height-1, 0)
+	l.render(false)
+}
+
+// Reload rereads the file. The view stays at the bottom if it was there.
+func (l *LogViewer) Reload() {
+	follow := l.viewport.AtBottom() || len(l.lines) == 0
+	l.lines, l.err = tailLog(l.path, maxLogLines)
+	l.render(follow)
+}
+
+// render fills the viewport with the entries, cut to its width.
+func (l *LogViewer) render(follow bool) {
+	lines := make([]string, len(l.lines))
+	for i, line := range l.lines {
+		lines[i] = ansi.Truncate(line, l.viewport.Width, "…")
+	}
+	l.viewport.SetContent(strings.Join(lines, "\n"))
+	if follow {
+		l.viewport.GotoBottom()
+	}
+}
+
+// ScrollUp scrolls up by n lines.
+func (l *LogViewer) ScrollUp(n int) {
+	l.viewport.ScrollUp(n)
+}
+
+// ScrollDown scrolls down by n lines.
+func (l *LogViewer) ScrollDown(n int) {
+	l.viewport.ScrollDown(n)
+}
+
+// GotoTop scrolls to the oldest entry shown.
+func (l *LogViewer) GotoTop() {
+	l.viewport.GotoTop()
+}
+
+// GotoBottom scrolls to the newest entry and follows new ones.
+func (l *LogViewer) GotoBottom() {
+	l.viewport.GotoBottom()
+}
+
+// View renders a title line with the file path followed by the entries.
+// The title says when the view is paused above the newest entry.
+func (l *LogViewer) View() string {
+	title := "Debug log: " + l.path
+	if !l.viewport.AtBottom() {
+		title = "Debug log (paused, G to follow): " + l.path
+	}
+	body := l.viewport.View()
+	switch {
+	case l.err != nil:
+		body = "Could not read log: " + l.err.Error()
+	case len(l.lines) == 0:
+		body = "No log entries yet"
+	}
+	return l.style.Render(ansi.Truncate(title, l.viewport.Width, "…")) + "\n" + body
+}
+
+// tailLog returns up to n formatted entries from the end of the file.
+func tailLog(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > 2*n {
+			lines = append(lines[:0], lines[len(lines)-n:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	for i, line := range lines {
+		lines[i] = FormatLogLine(line)
+	}
+	return lines, nil
+}
+
+// FormatLogLine formats a JSON log entry as "15:04:05.000 LEVEL message
+// key=value ...", with the remaining keys sorted. Lines that are not JSON
+// objects are returned unchanged.
+func FormatLogLine(line string) string {
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return line
+	}
+
+	var b strings.Builder
+	if ts, ok := entry["time"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			ts = t.In(utils.Location).Format("15:04:05.000")
+		}
+		b.WriteString(ts + " ")
+	}
+	if level, ok := entry["level"].(string); ok {
+		fmt.Fprintf(&b, "%-5s ", level)
+	}
+	if msg, ok := entry["msg"].(string); ok {
+		b.WriteString(msg)
+	}
+	delete(entry, "time")
+	delete(entry, "level")
+	delete(entry, "msg")
+
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := entry[k]
+		if s, ok := v.(string); ok {
+			if strings.ContainsAny(s, " \t\"=") || s == "" {
+				s = fmt.Sprintf("%q", s)
+			}
+			fmt.Fprintf(&b, " %s=%s", k, s)
+			continue
+		}
+		enc, _ := json.Marshal(v)
+		fmt.Fprintf(&b, " %s=%s", k, enc)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/utils"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFormatLogLine(t *testing.T) {
+	prev := utils.Location
+	utils.Location = time.UTC
+	t.Cleanup(func() { utils.Location = prev })
+
+	line := `{"time":"2025-01-08T12:00:01.5Z","level":"WARN","msg":"graphql request","operation":"GetIssue","variables":{"number":7},"error":"HTTP 502: Bad Gateway","duration_ms":250}`
+	want := `12:00:01.500 WARN  graphql request duration_ms=250 error="HTTP 502: Bad Gateway" operation=GetIssue variables={"number":7}`
+	if got := FormatLogLine(line); got != want {
+		t.Errorf("FormatLogLine =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := FormatLogLine("not json"); got != "not json" {
+		t.Errorf("FormatLogLine(not json) = %q", got)
+	}
+}
+
+func TestLogViewer_TailsAndFollows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+	var b strings.Builder
+	for i := 1; i <= maxLogLines+20; i++ {
+		fmt.Fprintf(&b, `{"level":"INFO","msg":"entry %d"}`+"\n", i)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogViewer(path, lipgloss.NewStyle())
+	l.SetSize(40, 4)
+	l.Reload()
+	if got := lastLine(l.View()); got != fmt.Sprintf("INFO  entry %d", maxLogLines+20) {
+		t.Fatalf("expected the newest entry at the bottom:\n%s", got)
+	}
+
+	l.GotoTop()
+	if got := l.View(); !strings.Contains(got, "INFO  entry 21 ") || !strings.Contains(got, "paused") {
+		t.Fatalf("expected the oldest kept entry at the top and a paused title:\n%s", got)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(f, `{"level":"INFO","msg":"newer"}`)
+	f.Close()
+
+	l.Reload()
+	if strings.Contains(l.View(), "newer") {
+		t.Error("expected a paused viewer to stay in place")
+	}
+	l.GotoBottom()
+	if lastLine(l.View()) != "INFO  newer" {
+		t.Errorf("expected the new entry after following:\n%s", l.View())
+	}
+}
+
+func TestLogViewer_MissingFile(t *testing.T) {
+	l := NewLogViewer(filepath.Join(t.TempDir(), "missing.log"), lipgloss.NewStyle())
+	l.SetSize(80, 5)
+	l.Reload()
+	if got := l.View(); !strings.Contains(got, "Could not read log") {
+		t.Errorf("View() = %q", got)
+	}
+}
+
+func lastLine(view string) string {
+	lines := strings.Split(view, "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,83 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PickerItem is one choice in a Picker.
+type PickerItem struct {
+	Label string
+	// Marked items are shown with a check mark, e.g. a reaction the viewer
+	// has already given.
+	Marked bool
+}
+
+// Picker is a small boxed list of choices with a cursor. Items are
+// numbered from 1 so that the first nine can be chosen directly.
+type Picker struct {
+	title    string
+	items    []PickerItem
+	cursor   int
+	selected lipgloss.Style
+}
+
+// NewPicker creates a picker with the cursor on the first item. The cursor
+// row is drawn with selectedStyle.
+func NewPicker(title string, items []PickerItem, selectedStyle lipgloss.Style) *Picker {
+	return &Picker{title: title, items: items, selected: selectedStyle}
+}
+
+// Up moves the cursor up, wrapping to the last item.
+func (p *Picker) Up() {
+	if len(p.items) > 0 {
+		p.cursor = (p.cursor - 1 + len(p.items)) % len(p.items)
+	}
+}
+
+// Down moves the cursor down, wrapping to the first item.
+func (p *Picker) Down() {
+	if len(p.items) > 0 {
+		p.cursor = (p.cursor + 1) % len(p.items)
+	}
+}
+
+// Cursor returns the index of the item under the cursor.
+func (p *Picker) Cursor() int {
+	return p.cursor
+}
+
+// IndexForKey returns the index of the item numbered by key, "1" to "9",
+// and whether there is one.
+func (p *Picker) IndexForKey(key string) (int, bool) {
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return 0, false
+	}
+	i := int(key[0] - '1')
+	return i, i < len(p.items)
+}
+
+// View renders the picker in a rounded box.
+func (p *Picker) View() string {
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(p.title)}
+	for i, item := range p.items {
+		mark := " "
+		if item.Marked {
+			mark = "✓"
+		}
+		line := fmt.Sprintf("%d %s %s", i+1, mark, item.Label)
+		if i == p.cursor {
+			line = p.selected.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1)
+	return box.Render(strings.Join(lines, "\n"))
+}
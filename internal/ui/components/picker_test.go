@@ -0,0 +1,52 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPicker_NavigatesAndMarks(t *testing.T) {
+	p := NewPicker("React", []PickerItem{{Label: "one"}, {Label: "two", Marked: true}, {Label: "three"}}, lipgloss.NewStyle())
+
+	p.Up()
+	if p.Cursor() != 2 {
+		t.Errorf("cursor after Up = %d, want wrap to 2", p.Cursor())
+	}
+	p.Down()
+	p.Down()
+	if p.Cursor() != 1 {
+		t.Errorf("cursor = %d, want 1", p.Cursor())
+	}
+
+	view := ansi.Strip(p.View())
+	for _, want := range []string{"React", "  1   one", "> 2 ✓ two", "  3   three"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestPicker_IndexForKey(t *testing.T) {
+	p := NewPicker("", []PickerItem{{Label: "a"}, {Label: "b"}}, lipgloss.NewStyle())
+	tests := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"1", 0, true},
+		{"2", 1, true},
+		{"3", 2, false},
+		{"0", 0, false},
+		{"x", 0, false},
+		{"12", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := p.IndexForKey(tt.key)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("IndexForKey(%q) = %d, %v, want %d, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package components
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -12,6 +17,9 @@ type StatusBar struct {
 	keyHints      []string
 	message       string
 	messagePrefix string
+	rateRemaining int
+	rateLimit     int
+	rateResetAt   time.Time
 	width         int
 	style         lipgloss.Style
 }
@@ -26,6 +34,14 @@ func (s *StatusBar) SetRepoName(name string) {
 	s.repoName = name
 }
 
+// SetRateLimit sets the API budget displayed after the repo name. A limit
+// of zero hides it.
+func (s *StatusBar) SetRateLimit(remaining, limit int, resetAt time.Time) {
+	s.rateRemaining = remaining
+	s.rateLimit = limit
+	s.rateResetAt = resetAt
+}
+
 // SetKeyHints sets the key hints displayed in the center.
 func (s *StatusBar) SetKeyHints(hints []string) {
 	s.keyHints = hints
@@ -49,7 +65,9 @@ func (s *StatusBar) SetLoading(msg string) {
 	s.messagePrefix = "loading"
 }
 
-// SetError sets an error message with API/network classification.
+// SetError sets an error message with API/network classification, described
+// by the error's data.Error kind. Errors of no known kind are shown as they
+// are.
 func (s *StatusBar) SetError(err error) {
 	if err == nil {
 		s.SetMessage("")
@@ -62,27 +80,9 @@ func (s *StatusBar) SetError(err error) {
 		return
 	}
 
-	if isNetworkError(text) {
-		s.messagePrefix = "network"
-		s.message = text
-		return
-	}
-
-	if strings.Contains(text, "HTTP 401") || strings.Contains(text, "401 Unauthorized") {
-		s.messagePrefix = "api"
-		s.message = "Run gh auth login to re-authenticate"
-		return
-	}
-
-	if strings.Contains(text, "HTTP 403") || strings.Contains(text, "403 Forbidden") {
-		s.messagePrefix = "api"
-		s.message = "Check your permissions for this repository"
-		return
-	}
-
-	if strings.Contains(text, "HTTP 404") || strings.Contains(text, "404 Not Found") {
-		s.messagePrefix = "api"
-		s.message = "Repository not found"
+	var apiErr *data.Error
+	if errors.As(data.ClassifyError(err), &apiErr) {
+		s.messagePrefix, s.message = describeError(apiErr, text)
 		return
 	}
 
@@ -90,6 +90,54 @@ func (s *StatusBar) SetError(err error) {
 	s.message = text
 }
 
+// describeError returns the message prefix and an actionable description
+// for a classified error.
+func describeError(e *data.Error, text string) (string, string) {
+	switch e.Kind {
+	case data.ErrAuth:
+		return "api", "Run gh auth login to re-authenticate"
+	case data.ErrPermission:
+		if strings.Contains(strings.ToLower(e.Message), "scope") {
+			return "api", "Missing token scopes; run gh auth refresh to grant them"
+		}
+		return "api", "Check your permissions for this repository"
+	case data.ErrNotFound:
+		switch e.Resource {
+		case "":
+			return "api", "Not found"
+		case "issue":
+			return "api", "Issue not found; it may have been deleted or transferred"
+		default:
+			return "api", strings.ToUpper(e.Resource[:1]) + e.Resource[1:] + " not found"
+		}
+	case data.ErrRateLimited:
+		if !e.RetryAt.IsZero() {
+			return "api", "Rate limit exceeded; retry after " + e.RetryAt.In(utils.Location).Format("15:04")
+		}
+		return "api", "Rate limit exceeded; wait a minute and retry"
+	case data.ErrNetwork:
+		if e.Message != "" {
+			text = e.Message
+		}
+		return "network", text + "; check your connection and retry"
+	case data.ErrValidation:
+		if e.Message != "" {
+			return "api", e.Message
+		}
+		return "api", text
+	case data.ErrPartialData:
+		return "warning", "Some fields could not be loaded: " + e.Message
+	case data.ErrServer:
+		return "api", "GitHub could not process the request; retry in a moment"
+	case data.ErrConflict:
+		if e.Message != "" {
+			return "api", e.Message
+		}
+		return "api", "Changed on GitHub since it was loaded; try again"
+	}
+	return "api", text
+}
+
 // SetWidth sets the status bar width.
 func (s *StatusBar) SetWidth(w int) {
 	s.width = w
@@ -102,27 +150,36 @@ func (s *StatusBar) View() string {
 	right := s.renderMessage()
 
 	if s.width <= 0 {
+		if budget := s.renderRateLimit(); budget != "" {
+			left += "  " + budget
+		}
 		bar := strings.TrimSpace(left + " " + center + " " + right)
 		return s.style.Render(bar)
 	}
 
-	maxLeft := s.width / 4
+	// The style's padding and borders take up part of the bar.
+	width := s.width - s.style.GetHorizontalFrameSize()
+
+	maxLeft := width / 4
 	if maxLeft < 12 {
 		maxLeft = 12
 	}
 	left = truncateText(left, maxLeft)
+	if budget := s.renderRateLimit(); budget != "" {
+		left += "  " + budget
+	}
 
-	maxRight := s.width / 3
+	maxRight := width / 3
 	if maxRight < 20 {
 		maxRight = 20
 	}
 	right = truncateText(right, maxRight)
 
 	// Calculate available space
-	available := s.width - lipgloss.Width(left) - lipgloss.Width(right)
+	available := width - lipgloss.Width(left) - lipgloss.Width(right)
 	if available < 0 {
-		left = truncateText(left, s.width-lipgloss.Width(right))
-		available = s.width - lipgloss.Width(left) - lipgloss.Width(right)
+		left = truncateText(left, width-lipgloss.Width(right))
+		available = width - lipgloss.Width(left) - lipgloss.Width(right)
 		if available < 0 {
 			available = 0
 		}
@@ -163,6 +220,19 @@ func (s *StatusBar) View() string {
 	return s.style.Width(s.width).Render(bar)
 }
 
+// renderRateLimit shows the remaining budget, and the reset time once less
+// than a tenth of the budget is left.
+func (s *StatusBar) renderRateLimit() string {
+	if s.rateLimit <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%d/%d pts", s.rateRemaining, s.rateLimit)
+	if s.rateRemaining*10 < s.rateLimit {
+		text += ", resets " + s.rateResetAt.In(utils.Location).Format("15:04")
+	}
+	return text
+}
+
 func (s *StatusBar) renderMessage() string {
 	if s.message == "" {
 		return ""
@@ -175,6 +245,8 @@ func (s *StatusBar) renderMessage() string {
 		return "network: " + s.message
 	case "api":
 		return "api: " + s.message
+	case "warning":
+		return "warning: " + s.message
 	default:
 		return s.message
 	}
@@ -212,25 +284,3 @@ func truncateText(text string, max int) string {
 
 	return text[:end] + ellipsis
 }
-
-func isNetworkError(errText string) bool {
-	text := strings.ToLower(errText)
-	keywords := []string{
-		"dial tcp",
-		"connection refused",
-		"no such host",
-		"i/o timeout",
-		"network is unreachable",
-		"timeout",
-		"tls",
-		"temporary failure",
-	}
-
-	for _, keyword := range keywords {
-		if strings.Contains(text, keyword) {
-			return true
-		}
-	}
-
-	return false
-}
@@ -1,16 +1,25 @@
 package components
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui/uitest"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/v2/pkg/api"
 )
 
 func TestStatusBar_SetError_Network(t *testing.T) {
 	sb := NewStatusBar(lipgloss.NewStyle())
-	sb.SetError(errors.New("dial tcp: i/o timeout"))
+	sb.SetError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("i/o timeout")})
 	sb.SetWidth(120)
 
 	v := sb.View()
@@ -21,7 +30,7 @@ func TestStatusBar_SetError_Network(t *testing.T) {
 
 func TestStatusBar_SetError_API401(t *testing.T) {
 	sb := NewStatusBar(lipgloss.NewStyle())
-	sb.SetError(errors.New("HTTP 401 unauthorized"))
+	sb.SetError(&api.HTTPError{StatusCode: http.StatusUnauthorized, Message: "Bad credentials"})
 	sb.SetWidth(120)
 
 	v := sb.View()
@@ -32,7 +41,11 @@ func TestStatusBar_SetError_API401(t *testing.T) {
 
 func TestStatusBar_SetError_API404(t *testing.T) {
 	sb := NewStatusBar(lipgloss.NewStyle())
-	sb.SetError(errors.New("HTTP 404 not found"))
+	sb.SetError(&api.HTTPError{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not Found",
+		RequestURL: &url.URL{Scheme: "https", Host: "api.github.com", Path: "/repos/owner/repo"},
+	})
 	sb.SetWidth(120)
 
 	v := sb.View()
@@ -41,6 +54,17 @@ func TestStatusBar_SetError_API404(t *testing.T) {
 	}
 }
 
+func TestStatusBar_SetError_UnclassifiedShownAsIs(t *testing.T) {
+	sb := NewStatusBar(lipgloss.NewStyle())
+	sb.SetError(errors.New("template: HTTP 404 in body"))
+	sb.SetWidth(120)
+
+	v := sb.View()
+	if !strings.Contains(v, "api: template: HTTP 404 in body") {
+		t.Fatalf("expected the error text, got %q", v)
+	}
+}
+
 func TestStatusBar_SeparatorWhenCenterSqueezed(t *testing.T) {
 	sb := NewStatusBar(lipgloss.NewStyle())
 	sb.SetRepoName("owner/repo")
@@ -94,3 +118,118 @@ func TestStatusBar_TruncatesRightMessage(t *testing.T) {
 		t.Fatalf("expected ellipsis truncation, got %q", v)
 	}
 }
+
+func TestStatusBar_RateLimit(t *testing.T) {
+	sb := NewStatusBar(lipgloss.NewStyle())
+	sb.SetRepoName("owner/repo")
+	sb.SetWidth(120)
+
+	if v := sb.View(); strings.Contains(v, "pts") {
+		t.Fatalf("expected no budget before it is known, got %q", v)
+	}
+
+	resetAt := time.Date(2025, time.June, 1, 14, 5, 0, 0, time.Local)
+	sb.SetRateLimit(4980, 5000, resetAt)
+	v := sb.View()
+	if !strings.Contains(v, "owner/repo  4980/5000 pts") || strings.Contains(v, "resets") {
+		t.Fatalf("expected budget without reset time, got %q", v)
+	}
+
+	sb.SetRateLimit(42, 5000, resetAt)
+	if v := sb.View(); !strings.Contains(v, "42/5000 pts, resets 14:05") {
+		t.Fatalf("expected low budget with reset time, got %q", v)
+	}
+}
+
+func TestStatusBar_SetError_Typed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			"deleted issue",
+			&api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND", Path: []interface{}{"repository", "issue"}}}},
+			"api: Issue not found",
+		},
+		{
+			"missing repository",
+			&api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND", Path: []interface{}{"repository"}}}},
+			"api: Repository not found",
+		},
+		{
+			"ghes auth wording",
+			&api.HTTPError{StatusCode: 401, Message: "Requires authentication"},
+			"api: Run gh auth login",
+		},
+		{
+			"missing scopes",
+			&api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "INSUFFICIENT_SCOPES", Message: "Your token has not been granted the required scopes"}}},
+			"api: Missing token scopes",
+		},
+		{
+			"rate limited",
+			&data.Error{Kind: data.ErrRateLimited, RetryAt: time.Date(2025, time.June, 1, 14, 5, 0, 0, time.Local), Err: errors.New("rate limited")},
+			"api: Rate limit exceeded; retry after 14:05",
+		},
+		{
+			"partial data",
+			&data.Error{Kind: data.ErrPartialData, Message: "Author is not accessible", Err: errors.New("partial")},
+			"warning: Some fields could not be loaded",
+		},
+		{
+			"timeout",
+			fmt.Errorf("loading issues: %w", context.DeadlineExceeded),
+			"network: request timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewStatusBar(lipgloss.NewStyle())
+			sb.SetWidth(200)
+			sb.SetError(tt.err)
+			if v := sb.View(); !strings.Contains(v, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, v)
+			}
+		})
+	}
+}
+
+func TestStatusBar_Snapshots(t *testing.T) {
+	uitest.Freeze(t)
+	padded := lipgloss.NewStyle().Padding(0, 1)
+
+	tests := []struct {
+		name  string
+		width int
+		setup func(sb *StatusBar)
+	}{
+		{"hints_only", 80, func(sb *StatusBar) {}},
+		{"info_narrow", 30, func(sb *StatusBar) { sb.SetInfo("Showing 25 issues") }},
+		{"loading", 80, func(sb *StatusBar) { sb.SetLoading("Refreshing issues...") }},
+		{"error_wide", 120, func(sb *StatusBar) {
+			sb.SetError(&data.Error{Kind: data.ErrNotFound, Resource: "issue", Err: errors.New("not found")})
+		}},
+		{"rate_limit_low", 80, func(sb *StatusBar) {
+			sb.SetRateLimit(42, 5000, uitest.Now.Add(30*time.Minute))
+			sb.SetInfo("Showing 25 issues")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewStatusBar(padded)
+			sb.SetRepoName("octo/hello")
+			sb.SetKeyHints([]string{"j/k: navigate", "enter: open", "q: quit"})
+			sb.SetWidth(tt.width)
+			tt.setup(sb)
+
+			view := sb.View()
+			if w := lipgloss.Width(view); w != tt.width {
+				t.Errorf("width = %d, want %d", w, tt.width)
+			}
+			uitest.Golden(t, "statusbar_"+tt.name, view)
+		})
+	}
+}
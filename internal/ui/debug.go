@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logRefreshInterval is how often the open log viewer rereads the log.
+const logRefreshInterval = time.Second
+
+// logTickMsg asks the log viewer to reread the log. Ticks from an earlier
+// opening of the viewer carry a stale generation and are ignored.
+type logTickMsg struct {
+	generation int
+}
+
+// SetDebugLog logs every message passing through Update to logger and
+// enables the log viewer overlay, which tails the log file at path.
+func (a *App) SetDebugLog(logger *slog.Logger, path string) {
+	a.logger = logger
+	a.logViewer = components.NewLogViewer(path, a.styles.Header)
+	a.logViewer.SetSize(a.width, a.height)
+}
+
+// logMsg writes msg to the debug log. Spinner ticks arrive many times a
+// second and are skipped.
+func (a *App) logMsg(msg tea.Msg) {
+	if a.logger == nil {
+		return
+	}
+	switch msg.(type) {
+	case spinner.TickMsg, logTickMsg:
+		return
+	}
+	a.logger.LogAttrs(context.Background(), slog.LevelDebug, "update", msgAttrs(msg)...)
+}
+
+// msgAttrs describes msg for the debug log: its type and, for the messages
+// that carry them, its key, size, request ID, and error.
+func msgAttrs(msg tea.Msg) []slog.Attr {
+	attrs := []slog.Attr{slog.String("type", fmt.Sprintf("%T", msg))}
+	var requestID int64
+	var err error
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		attrs = append(attrs, slog.String("key", msg.String()))
+	case tea.WindowSizeMsg:
+		attrs = append(attrs, slog.Int("width", msg.Width), slog.Int("height", msg.Height))
+	case NavigateToDetailMsg:
+		attrs = append(attrs, slog.Int("issue", msg.IssueNumber))
+	case IssuesLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("issues", len(msg.Result.Issues)))
+	case IssuesPageLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("issues", len(msg.Result.Issues)))
+	case IssueDetailLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+	case CommentsLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("comments", len(msg.Comments)))
+	case TimelineLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("events", len(msg.Events)))
+	case CommentSavedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.String("comment", msg.Comment.ID), slog.Bool("edited", msg.Edited))
+	case CommentDeletedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.String("comment", msg.CommentID))
+	case CommentMinimizedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.String("comment", msg.Comment.ID), slog.Bool("minimized", msg.Comment.IsMinimized))
+	case CopyMsg:
+		attrs = append(attrs, slog.String("what", msg.What), slog.Int("size", len(msg.Text)))
+	case OpenURLMsg:
+		attrs = append(attrs, slog.String("url", msg.URL))
+	case browsedMsg:
+		err = msg.err
+		attrs = append(attrs, slog.String("url", msg.url))
+	case ReactionsUpdatedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.String("subject", msg.SubjectID), slog.String("content", msg.Content), slog.Bool("removed", msg.Removed))
+	case BoardLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("labels", len(msg.Labels)), slog.Int("items", len(msg.Items)))
+	case BoardMovedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("issue", msg.IssueNumber), slog.String("column", msg.Column))
+	case ProjectFieldsLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("fields", len(msg.Fields)))
+	case ProjectFieldUpdatedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.String("item", msg.ItemID), slog.String("field", msg.Value.FieldName))
+	case IssueTypesLoadedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("types", len(msg.Types)))
+	case IssueTypeUpdatedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.String("type", msg.Type.Name))
+	case IssueBodyUpdatedMsg:
+		requestID, err = msg.RequestID, msg.Err
+		attrs = append(attrs, slog.Int("size", len(msg.Body)))
+	case StatusMessageMsg:
+		attrs = append(attrs, slog.String("text", msg.Text))
+	}
+	if requestID != 0 {
+		attrs = append(attrs, slog.Int64("request_id", requestID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	return attrs
+}
+
+// toggleLog opens or closes the log viewer overlay.
+func (a *App) toggleLog() tea.Cmd {
+	a.showLog = !a.showLog
+	if !a.showLog {
+		a.updateKeyHints()
+		return nil
+	}
+	a.statusBar.SetKeyHints(logKeyHints())
+	a.logGeneration++
+	a.logViewer.Reload()
+	return a.logTick()
+}
+
+func (a *App) logTick() tea.Cmd {
+	generation := a.logGeneration
+	return tea.Tick(logRefreshInterval, func(time.Time) tea.Msg {
+		return logTickMsg{generation: generation}
+	})
+}
+
+// updateLog handles keys while the log viewer is open.
+func (a *App) updateLog(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, a.keys.Logs), key.Matches(msg, a.keys.Back), key.Matches(msg, a.keys.Quit):
+		return a.toggleLog()
+	case key.Matches(msg, a.keys.Up):
+		a.logViewer.ScrollUp(1)
+	case key.Matches(msg, a.keys.Down):
+		a.logViewer.ScrollDown(1)
+	case key.Matches(msg, a.keys.PageUp):
+		a.logViewer.ScrollUp(max(a.height-2, 1))
+	case key.Matches(msg, a.keys.PageDown):
+		a.logViewer.ScrollDown(max(a.height-2, 1))
+	case key.Matches(msg, a.keys.GoToTop):
+		a.logViewer.GotoTop()
+	case key.Matches(msg, a.keys.GoToEnd):
+		a.logViewer.GotoBottom()
+	}
+	return nil
+}
+
+func logKeyHints() []string {
+	return []string{"j/k scroll", "G follow", "esc close"}
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/browser"
+)
+
+// CopyMsg asks the App to copy Text to the clipboard. What names the text
+// in the confirmation, e.g. "permalink".
+type CopyMsg struct {
+	Text string
+	What string
+}
+
+// OpenURLMsg asks the App to open URL in the web browser.
+type OpenURLMsg struct {
+	URL string
+}
+
+// browsedMsg reports how opening a URL in the browser went.
+type browsedMsg struct {
+	url string
+	err error
+}
+
+// EditMsg asks the App to open Text in the user's editor. Done turns the
+// edited text, or the error that prevented editing, into a message for the
+// current view.
+type EditMsg struct {
+	Text string
+	Done func(text string, err error) tea.Msg
+}
+
+// Editor lets the user edit text and delivers done's message afterwards.
+type Editor func(text string, done func(text string, err error) tea.Msg) tea.Cmd
+
+// Browser opens url and delivers done's message afterwards.
+type Browser func(url string, done func(err error) tea.Msg) tea.Cmd
+
+// ExternalBrowser opens URLs with command, such as "w3m" or "firefox", given
+// the URL as its last argument. It runs in the terminal, suspending the
+// program until it exits, since it may be a terminal browser. Without a
+// command the system opener is used, with its output discarded.
+func ExternalBrowser(command string) Browser {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		browser.Stdout, browser.Stderr = io.Discard, io.Discard
+		return BackgroundBrowser(browser.OpenURL)
+	}
+	return func(url string, done func(error) tea.Msg) tea.Cmd {
+		cmd := exec.Command(args[0], append(args[1:], url)...)
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			if err != nil {
+				err = fmt.Errorf("running %s: %w", args[0], err)
+			}
+			return done(err)
+		})
+	}
+}
+
+// BackgroundBrowser opens URLs with open without suspending the program,
+// for openers that hand the URL to a browser and return.
+func BackgroundBrowser(open func(url string) error) Browser {
+	return func(url string, done func(error) tea.Msg) tea.Cmd {
+		return func() tea.Msg { return done(open(url)) }
+	}
+}
+
+// ExternalEditor runs command, such as "vim" or "code --wait", on a
+// temporary markdown file holding the text, suspending the program until it
+// exits.
+func ExternalEditor(command string) Editor {
+	return func(text string, done func(string, error) tea.Msg) tea.Cmd {
+		fail := func(err error) tea.Cmd {
+			return func() tea.Msg { return done("", err) }
+		}
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return fail(errors.New("no editor configured"))
+		}
+
+		f, err := os.CreateTemp("", "gh-problemas-*.md")
+		if err != nil {
+			return fail(err)
+		}
+		path := f.Name()
+		_, err = f.WriteString(text)
+		if err = errors.Join(err, f.Close()); err != nil {
+			os.Remove(path)
+			return fail(err)
+		}
+
+		cmd := exec.Command(args[0], append(args[1:], path)...)
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			defer os.Remove(path)
+			if err != nil {
+				return done("", fmt.Errorf("running %s: %w", args[0], err))
+			}
+			b, err := os.ReadFile(path)
+			return done(string(b), err)
+		})
+	}
+}
+
+// SetClipboard replaces how CopyMsg text reaches the clipboard. By default
+// it is written to the terminal as an OSC 52 sequence.
+func (a *App) SetClipboard(fn func(text string) error) {
+	a.clipboard = fn
+}
+
+// SetBrowser sets the browser opened for OpenURLMsg. Without one, opening
+// fails with an error in the status bar.
+func (a *App) SetBrowser(browser Browser) {
+	a.browser = browser
+}
+
+// SetEditor sets the editor opened for EditMsg. Without one, editing fails
+// with an error in the status bar.
+func (a *App) SetEditor(editor Editor) {
+	a.editor = editor
+}
+
+func copyToTerminal(text string) error {
+	return utils.CopyOSC52(os.Stdout, text)
+}
+
+func (a *App) copy(msg CopyMsg) {
+	if err := a.clipboard(msg.Text); err != nil {
+		a.statusBar.SetError(fmt.Errorf("copying %s: %w", msg.What, err))
+		return
+	}
+	a.statusBar.SetInfo("Copied " + msg.What)
+}
+
+func (a *App) browse(msg OpenURLMsg) tea.Cmd {
+	if a.browser == nil {
+		return func() tea.Msg { return browsedMsg{url: msg.URL, err: errors.New("no browser configured")} }
+	}
+	a.statusBar.SetLoading("Opening the browser...")
+	return a.browser(msg.URL, func(err error) tea.Msg { return browsedMsg{url: msg.URL, err: err} })
+}
+
+func (a *App) browsed(msg browsedMsg) {
+	if msg.err != nil {
+		a.statusBar.SetError(fmt.Errorf("opening the browser: %w", msg.err))
+		return
+	}
+	a.statusBar.SetInfo("Opened in the browser")
+}
+
+func (a *App) edit(msg EditMsg) tea.Cmd {
+	if a.editor == nil {
+		return func() tea.Msg { return msg.Done("", errors.New("no editor configured")) }
+	}
+	return a.editor(msg.Text, msg.Done)
+}
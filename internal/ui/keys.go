@@ -17,6 +17,36 @@ type KeyMap struct {
 	GoToTop   key.Binding
 	GoToEnd   key.Binding
 	NextPage  key.Binding
+	Logs      key.Binding
+	React     key.Binding
+	NextBlock key.Binding
+	PrevBlock key.Binding
+	Reply     key.Binding
+	Copy      key.Binding
+	CopyLink  key.Binding
+	Edit      key.Binding
+	Delete    key.Binding
+	Collapse  key.Binding
+	Minimize  key.Binding
+	Confirm   key.Binding
+	Project   key.Binding
+	SubIssues key.Binding
+	Tasks     key.Binding
+	IssueType key.Binding
+	Browse    key.Binding
+	CopyRef   key.Binding
+	Links     key.Binding
+
+	NextSection  key.Binding
+	PrevSection  key.Binding
+	ToggleLayout key.Binding
+	SortCycle    key.Binding
+	SortMenu     key.Binding
+	GroupBy      key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	MoveLeft     key.Binding
+	MoveRight    key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -35,5 +65,35 @@ func DefaultKeyMap() KeyMap {
 		GoToTop:   key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "go to top")),
 		GoToEnd:   key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "go to end")),
 		NextPage:  key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "load more")),
+		Logs:      key.NewBinding(key.WithKeys("`"), key.WithHelp("`", "debug log")),
+		React:     key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "react")),
+		NextBlock: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next block")),
+		PrevBlock: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "previous block")),
+		Reply:     key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "quote reply")),
+		Copy:      key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy")),
+		CopyLink:  key.NewBinding(key.WithKeys("Y"), key.WithHelp("Y", "copy link")),
+		Edit:      key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+		Delete:    key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "delete")),
+		Collapse:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "collapse")),
+		Minimize:  key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "hide")),
+		Confirm:   key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "confirm")),
+		Project:   key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "project fields")),
+		SubIssues: key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "parent and sub-issues")),
+		Tasks:     key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "tasks")),
+		IssueType: key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "issue type")),
+		Browse:    key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open in browser")),
+		CopyRef:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "copy URL or reference")),
+		Links:     key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "follow a link")),
+
+		NextSection:  key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next section")),
+		PrevSection:  key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous section")),
+		ToggleLayout: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "table/list")),
+		SortCycle:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "next sort")),
+		SortMenu:     key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "sort menu")),
+		GroupBy:      key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "group by")),
+		Left:         key.NewBinding(key.WithKeys("h", "left"), key.WithHelp("h/left", "left")),
+		Right:        key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l/right", "right")),
+		MoveLeft:     key.NewBinding(key.WithKeys("<"), key.WithHelp("<", "move left")),
+		MoveRight:    key.NewBinding(key.WithKeys(">"), key.WithHelp(">", "move right")),
 	}
 }
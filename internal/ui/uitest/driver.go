@@ -0,0 +1,144 @@
+// Package uitest drives Bubble Tea models in tests and compares what they
+// draw against golden files.
+package uitest
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Driver runs a tea.Model the way tea.Program would, but synchronously:
+// each command is run to completion and its message fed back into the
+// model until nothing is left to do. Spinner ticks are dropped so that the
+// model settles.
+type Driver struct {
+	model   tea.Model
+	pending []tea.Msg
+	quit    bool
+}
+
+// NewDriver creates a driver for m. Call Init to start it.
+func NewDriver(m tea.Model) *Driver {
+	return &Driver{model: m}
+}
+
+// Model returns the model being driven.
+func (d *Driver) Model() tea.Model {
+	return d.model
+}
+
+// Init runs the model's Init command and everything it leads to.
+func (d *Driver) Init() {
+	d.run(d.model.Init())
+	d.settle()
+}
+
+// Resize sends a window size message.
+func (d *Driver) Resize(width, height int) {
+	d.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Send delivers each message in turn and runs everything it leads to.
+func (d *Driver) Send(msgs ...tea.Msg) {
+	for _, msg := range msgs {
+		d.pending = append(d.pending, msg)
+		d.settle()
+	}
+}
+
+// Post queues a message to be delivered with the next Send, for callbacks
+// that fire while a command is running.
+func (d *Driver) Post(msg tea.Msg) {
+	d.pending = append(d.pending, msg)
+}
+
+// Keys sends key presses. Names such as "enter", "esc", "up", and "ctrl+c"
+// are special keys; anything else is typed as runes.
+func (d *Driver) Keys(keys ...string) {
+	for _, k := range keys {
+		d.Send(KeyMsg(k))
+	}
+}
+
+// Quit reports whether the model has asked to quit.
+func (d *Driver) Quit() bool {
+	return d.quit
+}
+
+// View returns the model's view as drawn.
+func (d *Driver) View() string {
+	return d.model.View()
+}
+
+// Screen returns the model's view with ANSI sequences stripped and trailing
+// spaces removed from each line, as compared against golden files.
+func (d *Driver) Screen() string {
+	return Normalize(d.model.View())
+}
+
+func (d *Driver) settle() {
+	for len(d.pending) > 0 {
+		msg := d.pending[0]
+		d.pending = d.pending[1:]
+		var cmd tea.Cmd
+		d.model, cmd = d.model.Update(msg)
+		d.run(cmd)
+	}
+}
+
+func (d *Driver) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case nil, spinner.TickMsg:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			d.run(c)
+		}
+	case tea.QuitMsg:
+		d.quit = true
+	default:
+		d.pending = append(d.pending, msg)
+	}
+}
+
+var specialKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"backspace": tea.KeyBackspace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"space":     tea.KeySpace,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+d":    tea.KeyCtrlD,
+	"ctrl+u":    tea.KeyCtrlU,
+}
+
+// KeyMsg returns the key message for a key name as accepted by Keys.
+func KeyMsg(name string) tea.KeyMsg {
+	if t, ok := specialKeys[name]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// Normalize strips ANSI sequences and trailing spaces from each line of s.
+func Normalize(s string) string {
+	lines := strings.Split(ansi.Strip(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
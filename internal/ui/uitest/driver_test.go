@@ -0,0 +1,66 @@
+package uitest
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNormalize(t *testing.T) {
+	got := Normalize("\x1b[1mbold\x1b[0m   \nplain \n")
+	if want := "bold\nplain\n"; got != want {
+		t.Errorf("Normalize = %q, want %q", got, want)
+	}
+}
+
+func TestKeyMsg(t *testing.T) {
+	if k := KeyMsg("enter"); k.Type != tea.KeyEnter {
+		t.Errorf("enter = %v", k)
+	}
+	if k := KeyMsg("j"); k.Type != tea.KeyRunes || string(k.Runes) != "j" {
+		t.Errorf("j = %v", k)
+	}
+}
+
+// counter counts key presses; "x" asks it to fetch, which yields a key.
+type counter struct{ n int }
+
+func (c *counter) Init() tea.Cmd { return nil }
+
+func (c *counter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if k, ok := msg.(tea.KeyMsg); ok {
+		c.n++
+		switch k.String() {
+		case "x":
+			return c, tea.Batch(func() tea.Msg { return KeyMsg("y") }, nil)
+		case "q":
+			return c, tea.Quit
+		}
+	}
+	return c, nil
+}
+
+func (c *counter) View() string { return "" }
+
+func TestDriver_RunsCommandsToCompletion(t *testing.T) {
+	c := &counter{}
+	d := NewDriver(c)
+	d.Init()
+
+	d.Keys("a", "x")
+	if c.n != 3 {
+		t.Errorf("updates = %d, want 3", c.n)
+	}
+	d.Keys("q")
+	if !d.Quit() {
+		t.Error("expected quit")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := diff("a\nb", "a\nc\nd")
+	want := "line 2:\n  want: b\n  got:  c\nline 3:\n  want: \n  got:  d\n"
+	if got != want {
+		t.Errorf("diff = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package uitest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/utils"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// Now is the frozen time used by Freeze.
+var Now = time.Date(2025, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+// Freeze makes rendering deterministic for the rest of the test: relative
+// timestamps are computed from Now, clock times are shown in UTC, and
+// styles render without color. Everything is restored on cleanup.
+func Freeze(t testing.TB) {
+	t.Helper()
+
+	prevNow, prevLocation, prevProfile := utils.Now, utils.Location, lipgloss.ColorProfile()
+	utils.Now = func() time.Time { return Now }
+	utils.Location = time.UTC
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	t.Cleanup(func() {
+		utils.Now = prevNow
+		utils.Location = prevLocation
+		lipgloss.SetColorProfile(prevProfile)
+	})
+}
+
+// Golden compares got, normalized as by Normalize, with the golden file
+// testdata/<name>.golden. With -update the file is rewritten instead.
+func Golden(t testing.TB, name string, got string) {
+	t.Helper()
+
+	got = Normalize(got)
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run go test with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from %s (run go test with -update to accept):\n%s", name, path, diff(string(want), got))
+	}
+}
+
+// diff describes the lines that differ between want and got.
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&b, "line %d:\n  want: %s\n  got:  %s\n", i+1, w, g)
+	}
+	return b.String()
+}
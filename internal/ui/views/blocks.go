@@ -0,0 +1,367 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/utils"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// blockKind says what a detailBlock shows.
+type blockKind int
+
+const (
+	bodyBlock blockKind = iota
+	commentBlock
+	eventBlock
+)
+
+// detailBlock is an addressable part of the detail view: the issue body, a
+// comment, or a timeline event. start and end are the lines it occupies in
+// the rendered content, end exclusive.
+type detailBlock struct {
+	kind       blockKind
+	index      int // into DetailView.comments or DetailView.events
+	start, end int
+}
+
+var focusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+
+// buildBlocks lists the body followed by the comments and events in
+// chronological order, and finds the focused block again.
+func (d *DetailView) buildBlocks() {
+	d.blocks = append(d.blocks[:0], detailBlock{kind: bodyBlock})
+	c, e := 0, 0
+	for c < len(d.comments) || e < len(d.events) {
+		if e == len(d.events) || c < len(d.comments) && !d.events[e].CreatedAt.Before(d.comments[c].CreatedAt) {
+			d.blocks = append(d.blocks, detailBlock{kind: commentBlock, index: c})
+			c++
+		} else {
+			d.blocks = append(d.blocks, detailBlock{kind: eventBlock, index: e})
+			e++
+		}
+	}
+
+	d.focus = min(d.focus, len(d.blocks)-1)
+	for i, b := range d.blocks {
+		if d.blockKey(b) == d.focusKey {
+			d.focus = i
+			break
+		}
+	}
+	d.focusKey = d.blockKey(d.blocks[d.focus])
+}
+
+// blockKey identifies a block across re-renders.
+func (d *DetailView) blockKey(b detailBlock) string {
+	switch b.kind {
+	case commentBlock:
+		if id := d.comments[b.index].ID; id != "" {
+			return id
+		}
+		return fmt.Sprintf("comment:%d", b.index)
+	case eventBlock:
+		return fmt.Sprintf("event:%d", b.index)
+	}
+	return "body"
+}
+
+// activityTitle heads the comments and events below the body.
+func (d *DetailView) activityTitle() string {
+	if len(d.events) == 0 {
+		return fmt.Sprintf("Comments (%d)", len(d.comments))
+	}
+	return fmt.Sprintf("Activity (%d comments, %d events)", len(d.comments), len(d.events))
+}
+
+func (d *DetailView) renderBlock(b detailBlock, opts RenderOptions) string {
+	collapsed := d.isCollapsed(b)
+	switch b.kind {
+	case commentBlock:
+		return renderComment(d.comments[b.index], opts, d.renderMarkdown, collapsed)
+	case eventBlock:
+		e := d.events[b.index]
+		return metaStyle.Render(fmt.Sprintf("● %s %s  %s", e.Actor, e.Description(), utils.FormatTime(e.CreatedAt, opts.DateFormat))) + "\n"
+	}
+	return renderIssueBody(*d.issue, opts, d.renderMarkdown, collapsed)
+}
+
+// gutter indents each line of a block, marking the focused one with a bar.
+func (d *DetailView) gutter(s string, focused bool) string {
+	mark := strings.Repeat(" ", gutterWidth)
+	if focused {
+		mark = focusStyle.Render("▌") + " "
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = mark + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// updateBlocks handles the keys that act on the focused block, reporting
+// whether msg was one of them.
+func (d *DetailView) updateBlocks(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, d.keys.NextBlock):
+		d.setFocus(d.focus + 1)
+	case key.Matches(msg, d.keys.PrevBlock):
+		d.setFocus(d.focus - 1)
+	case key.Matches(msg, d.keys.Collapse):
+		return d.toggleCollapsed(), true
+	case key.Matches(msg, d.keys.Copy):
+		return d.copyText(), true
+	case key.Matches(msg, d.keys.CopyLink):
+		return d.copyLink(), true
+	case key.Matches(msg, d.keys.Reply) && d.commentClient != nil:
+		return d.quoteReply(), true
+	case key.Matches(msg, d.keys.Edit) && d.commentClient != nil:
+		return d.editComment(), true
+	case key.Matches(msg, d.keys.Delete) && d.commentClient != nil:
+		return d.askDeleteComment(), true
+	case key.Matches(msg, d.keys.Minimize) && d.commentClient != nil:
+		return d.toggleMinimized(), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// setFocus focuses block i and scrolls it into view.
+func (d *DetailView) setFocus(i int) {
+	if i < 0 || i >= len(d.blocks) {
+		return
+	}
+	d.focus = i
+	d.focusKey = d.blockKey(d.blocks[i])
+	d.renderContent()
+
+	b := d.blocks[i]
+	top := d.viewport.YOffset
+	switch {
+	case i == 0:
+		d.viewport.GotoTop()
+	case b.start < top:
+		d.viewport.SetYOffset(b.start)
+	case b.end > top+d.viewport.Height:
+		d.viewport.SetYOffset(min(b.start, b.end-d.viewport.Height))
+	}
+}
+
+// syncFocus moves the focus to the first visible block after scrolling
+// has moved the focused one off screen.
+func (d *DetailView) syncFocus() {
+	top, bottom := d.viewport.YOffset, d.viewport.YOffset+d.viewport.Height
+	visible := func(b detailBlock) bool { return b.end > top && b.start < bottom }
+	if d.focus < len(d.blocks) && visible(d.blocks[d.focus]) {
+		return
+	}
+	for i, b := range d.blocks {
+		if visible(b) {
+			d.focus = i
+			d.focusKey = d.blockKey(b)
+			d.renderContent()
+			return
+		}
+	}
+}
+
+// focusedBlock returns the focused block, if the issue has loaded.
+func (d *DetailView) focusedBlock() (detailBlock, bool) {
+	if d.issue == nil || d.focus >= len(d.blocks) {
+		return detailBlock{}, false
+	}
+	return d.blocks[d.focus], true
+}
+
+// focusedComment returns the focused comment, if a comment is focused.
+func (d *DetailView) focusedComment() (data.Comment, bool) {
+	b, ok := d.focusedBlock()
+	if !ok || b.kind != commentBlock {
+		return data.Comment{}, false
+	}
+	return d.comments[b.index], true
+}
+
+// isCollapsed reports whether block b shows only its heading. Minimized
+// comments start collapsed; the c key overrides that either way.
+func (d *DetailView) isCollapsed(b detailBlock) bool {
+	if collapsed, ok := d.collapsed[d.blockKey(b)]; ok {
+		return collapsed
+	}
+	return b.kind == commentBlock && d.comments[b.index].IsMinimized
+}
+
+func (d *DetailView) toggleCollapsed() tea.Cmd {
+	b, ok := d.focusedBlock()
+	if !ok || b.kind == eventBlock {
+		return nil
+	}
+	d.collapsed[d.blockKey(b)] = !d.isCollapsed(b)
+	d.setFocus(d.focus)
+	return nil
+}
+
+// blockText returns the markdown source of the focused block, or the
+// description of an event, and what to call it.
+func (d *DetailView) blockText() (text, what string) {
+	b, _ := d.focusedBlock()
+	switch b.kind {
+	case commentBlock:
+		return d.comments[b.index].Body, "comment"
+	case eventBlock:
+		e := d.events[b.index]
+		return e.Actor + " " + e.Description(), "event"
+	}
+	return d.issue.Body, "description"
+}
+
+func (d *DetailView) copyText() tea.Cmd {
+	text, what := d.blockText()
+	return func() tea.Msg { return ui.CopyMsg{Text: text, What: what} }
+}
+
+func (d *DetailView) copyLink() tea.Cmd {
+	b, _ := d.focusedBlock()
+	link := d.issue.URL
+	if b.kind == commentBlock {
+		link = d.comments[b.index].URL
+	}
+	if b.kind == eventBlock || link == "" {
+		return ui.StatusInfo("No link for this block")
+	}
+	return func() tea.Msg { return ui.CopyMsg{Text: link, What: "link"} }
+}
+
+// commentEditedMsg carries the text written in the editor for a new
+// comment, or for the comment with ID commentID.
+type commentEditedMsg struct {
+	requestID int64
+	commentID string
+	original  string
+	text      string
+	err       error
+}
+
+// edit opens text in the editor, for the comment with ID commentID or for
+// a new one.
+func (d *DetailView) edit(commentID, text string) tea.Cmd {
+	id := d.requestID
+	return func() tea.Msg {
+		return ui.EditMsg{Text: text, Done: func(edited string, err error) tea.Msg {
+			return commentEditedMsg{requestID: id, commentID: commentID, original: text, text: edited, err: err}
+		}}
+	}
+}
+
+// quoteReply starts a new comment quoting the focused block.
+func (d *DetailView) quoteReply() tea.Cmd {
+	text, _ := d.blockText()
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return d.edit("", strings.Join(lines, "\n")+"\n\n")
+}
+
+func (d *DetailView) editComment() tea.Cmd {
+	c, ok := d.focusedComment()
+	if !ok || !c.ViewerCanUpdate {
+		return ui.StatusInfo("You can't edit this comment")
+	}
+	return d.edit(c.ID, c.Body)
+}
+
+// saveComment posts or updates a comment with the edited text, unless it
+// was left empty or unchanged.
+func (d *DetailView) saveComment(msg commentEditedMsg) tea.Cmd {
+	switch {
+	case msg.err != nil:
+		return ui.StatusInfo(fmt.Sprintf("Editor failed: %v", msg.err))
+	case strings.TrimSpace(msg.text) == "" || msg.text == msg.original:
+		return ui.StatusInfo("Comment discarded")
+	}
+
+	client := d.commentClient
+	id := d.requestID
+	if msg.commentID != "" {
+		return tea.Batch(ui.StatusLoading("Updating comment..."), func() tea.Msg {
+			c, err := client.Update(msg.commentID, msg.text)
+			return ui.CommentSavedMsg{RequestID: id, Comment: c, Edited: true, Err: err}
+		})
+	}
+	subjectID := d.issue.ID
+	return tea.Batch(ui.StatusLoading("Posting comment..."), func() tea.Msg {
+		c, err := client.Add(subjectID, msg.text)
+		return ui.CommentSavedMsg{RequestID: id, Comment: c, Err: err}
+	})
+}
+
+// applySavedComment shows a posted comment, focused, or an edited one in
+// place.
+func (d *DetailView) applySavedComment(msg ui.CommentSavedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	if msg.Edited {
+		for i := range d.comments {
+			if d.comments[i].ID == msg.Comment.ID {
+				d.comments[i] = msg.Comment
+			}
+		}
+		d.renderContent()
+		return ui.StatusInfo("Comment updated")
+	}
+
+	d.comments = append(d.comments, msg.Comment)
+	d.focusKey = msg.Comment.ID
+	d.renderContent()
+	d.setFocus(d.focus)
+	return ui.StatusInfo("Comment posted")
+}
+
+// askDeleteComment asks to confirm deleting the focused comment.
+func (d *DetailView) askDeleteComment() tea.Cmd {
+	c, ok := d.focusedComment()
+	if !ok || !c.ViewerCanDelete {
+		return ui.StatusInfo("You can't delete this comment")
+	}
+	d.confirmDelete = c.ID
+	return ui.StatusInfo("Delete this comment? Press y to confirm")
+}
+
+// updateConfirmDelete deletes the comment on y and keeps it on any other
+// key.
+func (d *DetailView) updateConfirmDelete(msg tea.KeyMsg) tea.Cmd {
+	commentID := d.confirmDelete
+	d.confirmDelete = ""
+	if !key.Matches(msg, d.keys.Confirm) {
+		return ui.StatusInfo("Kept the comment")
+	}
+
+	client := d.commentClient
+	id := d.requestID
+	return tea.Batch(ui.StatusLoading("Deleting comment..."), func() tea.Msg {
+		err := client.Delete(commentID)
+		return ui.CommentDeletedMsg{RequestID: id, CommentID: commentID, Err: err}
+	})
+}
+
+func (d *DetailView) applyDeletedComment(msg ui.CommentDeletedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	for i := range d.comments {
+		if d.comments[i].ID == msg.CommentID {
+			d.comments = append(d.comments[:i], d.comments[i+1:]...)
+			break
+		}
+	}
+	d.renderContent()
+	return ui.StatusInfo("Comment deleted")
+}
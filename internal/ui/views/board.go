@@ -0,0 +1,442 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Board lays a section's issues out in columns: one per label, such as
+// status/todo, or one per option of a Projects v2 single-select field.
+type Board struct {
+	Labels []string
+	// Project is the number of a project owned by the repository owner,
+	// and Field one of its single-select fields, such as Status.
+	Project int
+	Field   string
+}
+
+// boardState is what a board has loaded besides its issues, and where its
+// cursor is.
+type boardState struct {
+	requestID     int64
+	moveRequestID int64
+	labels        []data.Label             // the column labels, with IDs
+	field         data.ProjectField        // project boards only
+	items         map[int]data.ProjectItem // project boards only, by issue number
+	col, row      int
+}
+
+// boardColumn is a column of the board. The column of issues with none of
+// the board's labels or options has no id, and issues cannot be moved to it.
+type boardColumn struct {
+	name   string
+	color  string
+	id     string
+	issues []data.Issue
+}
+
+const (
+	minBoardColumnWidth = 24
+	boardCardHeight     = 2
+	// maxBoardItems bounds the items a project board looks through. An
+	// organization's project can hold thousands, from many repositories.
+	maxBoardItems = 1000
+)
+
+// SetLabelClient lets label boards move issues by swapping labels.
+func (d *DashboardView) SetLabelClient(c *data.LabelClient) {
+	d.labelClient = c
+}
+
+// SetProjectClient lets project boards load and move issues.
+func (d *DashboardView) SetProjectClient(c *data.ProjectClient) {
+	d.projectClient = c
+}
+
+// onBoard reports whether the current section is shown as a board.
+func (d *DashboardView) onBoard() bool {
+	return d.sections[d.section].Board != nil
+}
+
+// onProjectBoard reports whether the current section is a board of a
+// project field, whose issues come from the project rather than the issue
+// list.
+func (d *DashboardView) onProjectBoard() bool {
+	b := d.sections[d.section].Board
+	return b != nil && b.Project > 0
+}
+
+// fetchSection loads the current section: its issues and, for a board,
+// its columns.
+func (d *DashboardView) fetchSection() tea.Cmd {
+	if !d.onBoard() {
+		return d.fetchIssues(d.pageSize, "")
+	}
+	if d.board == nil {
+		d.board = &boardState{}
+	}
+	if d.onProjectBoard() {
+		return d.fetchBoard()
+	}
+	return tea.Batch(d.fetchIssues(d.pageSize, ""), d.fetchBoard())
+}
+
+// fetchBoard loads the labels of a label board's columns, or the field and
+// items of a project board.
+func (d *DashboardView) fetchBoard() tea.Cmd {
+	board := *d.sections[d.section].Board
+	id := ui.NextRequestID()
+	d.board.requestID = id
+
+	if board.Project > 0 {
+		ctx, projects := d.ctx, d.projectClient
+		return func() tea.Msg {
+			if projects == nil {
+				return ui.BoardLoadedMsg{RequestID: id, Err: fmt.Errorf("project boards are not available")}
+			}
+			field, err := projects.Field(board.Project, board.Field)
+			if err != nil {
+				return ui.BoardLoadedMsg{RequestID: id, Err: err}
+			}
+			items, err := projects.ItemsContext(ctx, field, maxBoardItems)
+			return ui.BoardLoadedMsg{RequestID: id, Field: field, Items: items, Err: err}
+		}
+	}
+
+	labels := d.labelClient
+	if labels == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		all, err := labels.List()
+		var columns []data.Label
+		for _, l := range all {
+			if slices.ContainsFunc(board.Labels, func(name string) bool { return strings.EqualFold(name, l.Name) }) {
+				columns = append(columns, l)
+			}
+		}
+		return ui.BoardLoadedMsg{RequestID: id, Labels: columns, Err: err}
+	}
+}
+
+// applyBoard stores a loaded board. A project board's items become the
+// section's issues.
+func (d *DashboardView) applyBoard(msg ui.BoardLoadedMsg) tea.Cmd {
+	if !d.onProjectBoard() {
+		if msg.Err == nil {
+			d.board.labels = msg.Labels
+		}
+		return nil
+	}
+
+	d.loading = false
+	d.spinner.Stop()
+	if msg.Err != nil {
+		d.errMsg = fmt.Sprintf("Error loading board: %v", msg.Err)
+		return nil
+	}
+	d.errMsg = ""
+	d.board.field = msg.Field
+	d.board.items = make(map[int]data.ProjectItem, len(msg.Items))
+	d.issues = make([]data.Issue, len(msg.Items))
+	for i, item := range msg.Items {
+		d.board.items[item.Issue.Number] = item
+		d.issues[i] = item.Issue
+	}
+	d.paginator.Reset()
+	d.paginator.Update(data.PageInfo{}, len(d.issues))
+	d.updateTitle()
+	return ui.StatusInfo(fmt.Sprintf("Showing %d issues", len(d.issues)))
+}
+
+// boardColumns buckets the loaded issues into the board's columns, in the
+// section's sort order. Issues in none of them come first.
+func (d *DashboardView) boardColumns() []boardColumn {
+	var columns []boardColumn
+	var column func(issue data.Issue) int
+	board := d.sections[d.section].Board
+
+	if board.Project > 0 {
+		field := d.board.field
+		for _, o := range field.Options {
+			columns = append(columns, boardColumn{name: o.Name, color: o.Color, id: o.ID})
+		}
+		column = func(issue data.Issue) int {
+			optionID := d.board.items[issue.Number].OptionID
+			return slices.IndexFunc(field.Options, func(o data.ProjectFieldOption) bool { return o.ID == optionID })
+		}
+	} else {
+		for _, name := range board.Labels {
+			c := boardColumn{name: name}
+			if i := slices.IndexFunc(d.board.labels, func(l data.Label) bool { return strings.EqualFold(l.Name, name) }); i >= 0 {
+				c.color, c.id = d.board.labels[i].Color, d.board.labels[i].ID
+			}
+			columns = append(columns, c)
+		}
+		column = func(issue data.Issue) int {
+			return slices.IndexFunc(board.Labels, func(name string) bool {
+				return slices.ContainsFunc(issue.Labels, func(l data.Label) bool { return strings.EqualFold(l.Name, name) })
+			})
+		}
+	}
+
+	none := boardColumn{name: "No " + board.Field, color: "ededed"}
+	if board.Project == 0 {
+		none.name = "No status"
+	}
+	for _, issue := range d.sortIssues(d.issues) {
+		if i := column(issue); i >= 0 {
+			columns[i].issues = append(columns[i].issues, issue)
+		} else {
+			none.issues = append(none.issues, issue)
+		}
+	}
+	if len(none.issues) > 0 {
+		columns = append([]boardColumn{none}, columns...)
+	}
+	return columns
+}
+
+// clampBoardCursor keeps the cursor on a card, or on an empty column.
+func (d *DashboardView) clampBoardCursor(columns []boardColumn) {
+	d.board.col = max(min(d.board.col, len(columns)-1), 0)
+	if len(columns) == 0 {
+		d.board.row = 0
+		return
+	}
+	d.board.row = max(min(d.board.row, len(columns[d.board.col].issues)-1), 0)
+}
+
+// selectedCard returns the issue under the cursor.
+func (d *DashboardView) selectedCard(columns []boardColumn) (data.Issue, bool) {
+	d.clampBoardCursor(columns)
+	if len(columns) == 0 || len(columns[d.board.col].issues) == 0 {
+		return data.Issue{}, false
+	}
+	return columns[d.board.col].issues[d.board.row], true
+}
+
+// updateBoard handles the board's keys, reporting whether it used msg.
+func (d *DashboardView) updateBoard(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if d.loading || d.board == nil {
+		return nil, false
+	}
+	columns := d.boardColumns()
+	if len(columns) == 0 {
+		return nil, false
+	}
+	d.clampBoardCursor(columns)
+
+	switch {
+	case key.Matches(msg, d.keys.Up):
+		d.board.row = max(d.board.row-1, 0)
+	case key.Matches(msg, d.keys.Down):
+		d.board.row++
+	case key.Matches(msg, d.keys.Left):
+		d.board.col = max(d.board.col-1, 0)
+	case key.Matches(msg, d.keys.Right):
+		d.board.col++
+	case key.Matches(msg, d.keys.GoToTop):
+		d.board.row = 0
+	case key.Matches(msg, d.keys.GoToEnd):
+		d.board.row = len(columns[d.board.col].issues) - 1
+	case key.Matches(msg, d.keys.Open):
+		if issue, ok := d.selectedCard(columns); ok {
+			return func() tea.Msg { return ui.NavigateToDetailMsg{IssueNumber: issue.Number} }, true
+		}
+	case key.Matches(msg, d.keys.MoveLeft):
+		return d.moveCard(columns, -1), true
+	case key.Matches(msg, d.keys.MoveRight):
+		return d.moveCard(columns, 1), true
+	default:
+		return nil, false
+	}
+	d.clampBoardCursor(columns)
+	return nil, true
+}
+
+// moveCard moves the selected issue to the next column in direction dir
+// that it can be moved to, by swapping its label or setting its project
+// field.
+func (d *DashboardView) moveCard(columns []boardColumn, dir int) tea.Cmd {
+	issue, ok := d.selectedCard(columns)
+	if !ok {
+		return nil
+	}
+	if d.board.moveRequestID != 0 {
+		return ui.StatusInfo("Still moving the last issue...")
+	}
+	from := columns[d.board.col]
+	target := d.board.col + dir
+	for target >= 0 && target < len(columns) && columns[target].id == "" {
+		target += dir
+	}
+	if target < 0 || target >= len(columns) {
+		return nil
+	}
+	to := columns[target]
+
+	id := ui.NextRequestID()
+	d.board.moveRequestID = id
+	number := issue.Number
+	status := ui.StatusLoading(fmt.Sprintf("Moving #%d to %s...", number, to.name))
+
+	if d.onProjectBoard() {
+		projects, field, itemID := d.projectClient, d.board.field, d.board.items[number].ID
+		return tea.Batch(status, func() tea.Msg {
+			err := projects.SetOption(field, itemID, to.id)
+			return ui.BoardMovedMsg{RequestID: id, IssueNumber: number, Column: to.name, Err: err}
+		})
+	}
+
+	labels, issueID := d.labelClient, issue.ID
+	return tea.Batch(status, func() tea.Msg {
+		if labels == nil {
+			return ui.BoardMovedMsg{RequestID: id, IssueNumber: number, Column: to.name, Err: fmt.Errorf("labels cannot be changed")}
+		}
+		// Adding first means a failure leaves the issue in both columns,
+		// where a refresh shows it, rather than in neither.
+		err := labels.AddToIssue(issueID, []string{to.id})
+		if err == nil && from.id != "" {
+			err = labels.RemoveFromIssue(issueID, []string{from.id})
+		}
+		return ui.BoardMovedMsg{RequestID: id, IssueNumber: number, Column: to.name, Err: err}
+	})
+}
+
+// applyMove updates the moved issue's labels or project field and keeps
+// the cursor on it.
+func (d *DashboardView) applyMove(msg ui.BoardMovedMsg) tea.Cmd {
+	if d.board == nil || msg.RequestID != d.board.moveRequestID {
+		return nil
+	}
+	d.board.moveRequestID = 0
+	if msg.Err != nil {
+		return nil
+	}
+
+	i := slices.IndexFunc(d.issues, func(issue data.Issue) bool { return issue.Number == msg.IssueNumber })
+	if i < 0 {
+		return nil
+	}
+	if d.onProjectBoard() {
+		item := d.board.items[msg.IssueNumber]
+		for _, o := range d.board.field.Options {
+			if o.Name == msg.Column {
+				item.OptionID = o.ID
+			}
+		}
+		d.board.items[msg.IssueNumber] = item
+	} else {
+		board := d.sections[d.section].Board
+		issue := &d.issues[i]
+		issue.Labels = slices.DeleteFunc(slices.Clone(issue.Labels), func(l data.Label) bool {
+			return slices.ContainsFunc(board.Labels, func(name string) bool { return strings.EqualFold(name, l.Name) })
+		})
+		for _, l := range d.board.labels {
+			if strings.EqualFold(l.Name, msg.Column) {
+				issue.Labels = append(issue.Labels, l)
+			}
+		}
+	}
+
+	for c, column := range d.boardColumns() {
+		if r := slices.IndexFunc(column.issues, func(issue data.Issue) bool { return issue.Number == msg.IssueNumber }); r >= 0 {
+			d.board.col, d.board.row = c, r
+		}
+	}
+	return ui.StatusInfo(fmt.Sprintf("Moved #%d to %s", msg.IssueNumber, msg.Column))
+}
+
+// boardView renders the columns that fit, keeping the cursor's column in
+// view, with the cursor's card scrolled into view.
+func (d *DashboardView) boardView(height int) string {
+	// Short terminals leave no room below the tabs and title.
+	height = max(height, 1)
+	columns := d.boardColumns()
+	d.clampBoardCursor(columns)
+	if len(columns) == 0 {
+		return d.styles.HelpDesc.Render("  No columns")
+	}
+
+	visible := max(min(len(columns), (d.width+2)/(minBoardColumnWidth+2)), 1)
+	first := max(min(d.board.col-visible/2, len(columns)-visible), 0)
+	width := (d.width - 2*(visible-1)) / visible
+
+	separator := d.styles.HelpKey.Render(strings.TrimSuffix(strings.Repeat(" │\n", height), "\n"))
+	var parts []string
+	for c := first; c < first+visible; c++ {
+		if c > first {
+			parts = append(parts, separator)
+		}
+		parts = append(parts, d.boardColumnView(columns[c], c == d.board.col, width, height))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+}
+
+// boardColumnView renders a column header and as many cards as fit in
+// height lines.
+func (d *DashboardView) boardColumnView(column boardColumn, current bool, width, height int) string {
+	header := renderLabelChips([]data.Label{{Name: column.name, Color: column.color}}) + " " + d.styles.HelpDesc.Render(fmt.Sprintf("(%d)", len(column.issues)))
+	lines := []string{" " + fitCell(header, width-1, false), ""}
+
+	cards := max((height-len(lines))/boardCardHeight, 1)
+	first := 0
+	if current {
+		first = max(d.board.row-cards+1, 0)
+	}
+	board := d.sections[d.section].Board
+	for r := first; r < len(column.issues) && r < first+cards; r++ {
+		issue := column.issues[r]
+		selected := current && r == d.board.row
+		numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		titleStyle := d.styles.IssueTitle
+		cursor := "  "
+		if selected {
+			numberStyle = numberStyle.Foreground(lipgloss.Color("12"))
+			titleStyle = titleStyle.Foreground(lipgloss.Color("12"))
+			cursor = "> "
+		}
+
+		var others []data.Label
+		for _, l := range issue.Labels {
+			if !slices.ContainsFunc(board.Labels, func(name string) bool { return strings.EqualFold(name, l.Name) }) {
+				others = append(others, l)
+			}
+		}
+		meta := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(issue.Author)
+		if len(others) > 0 {
+			meta += " " + renderLabelChips(others)
+		}
+
+		lines = append(lines,
+			fitCell(cursor+numberStyle.Render(fmt.Sprintf("#%d", issue.Number))+" "+titleStyle.Render(issue.Title), width, false),
+			fitCell("  "+meta, width, false))
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i := range lines {
+		lines[i] = fitCell(lines[i], width, false)
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+// boardKeyHints are the hints shown on a board.
+func (d *DashboardView) boardKeyHints() []string {
+	hints := []string{"h/l: column", "j/k: card", "</>: move", "enter: open", "R: refresh"}
+	if d.paginator.HasNextPage() {
+		hints = append(hints, "L: load more")
+	}
+	if len(d.sections) > 1 {
+		hints = append(hints, "tab: section")
+	}
+	return append(hints, "q: quit")
+}
@@ -1,8 +1,10 @@
 package views
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/cboone/gh-problemas/internal/data"
@@ -13,6 +15,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // issueItem wraps a data.Issue for the list component.
@@ -44,6 +47,10 @@ func (d issueDelegate) Spacing() int                        { return 0 }
 func (d issueDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
 
 func (d issueDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if g, ok := item.(groupItem); ok {
+		renderGroupHeader(w, d.styles, g, index == m.Index(), d.Height())
+		return
+	}
 	i, ok := item.(issueItem)
 	if !ok {
 		return
@@ -69,7 +76,11 @@ func (d issueDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 		titleStyle = titleStyle.Foreground(lipgloss.Color("12"))
 	}
 
-	titleLine := numberStyle.Render(fmt.Sprintf("#%-5d", i.issue.Number)) + " " + titleStyle.Render(i.issue.Title)
+	titleLine := numberStyle.Render(fmt.Sprintf("#%-5d", i.issue.Number)) + " "
+	if badge := renderTypeBadge(i.issue.Type, false); badge != "" {
+		titleLine += badge + " "
+	}
+	titleLine += titleStyle.Render(i.issue.Title)
 	if labels != "" {
 		titleLine += " " + labels
 	}
@@ -82,6 +93,9 @@ func (d issueDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 	if i.issue.ReactionCount > 0 {
 		meta += fmt.Sprintf("  %d reactions", i.issue.ReactionCount)
 	}
+	if p := progressText(i.issue.SubIssueProgress); p != "" {
+		meta += "  " + p
+	}
 
 	metaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 	if isSelected {
@@ -93,23 +107,86 @@ func (d issueDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 		cursor = "> "
 	}
 
-	_, _ = fmt.Fprintf(w, "%s%s\n%s%s", cursor, titleLine, "  ", metaStyle.Render(meta))
+	titleLine, meta = cursor+titleLine, "  "+metaStyle.Render(meta)
+	// Long titles and labels are cut at the edge of the list rather than
+	// running past it.
+	if width := m.Width(); width > 0 {
+		titleLine = ansi.Truncate(titleLine, width, "…")
+		meta = ansi.Truncate(meta, width, "…")
+	}
+	_, _ = fmt.Fprintf(w, "%s\n%s", titleLine, meta)
+}
+
+// Section is a tab of the dashboard: the issues matching its filters,
+// shown as a two-line list or as a table of Columns.
+type Section struct {
+	Title   string
+	States  []string // "OPEN", "CLOSED"; empty for all
+	Labels  []string
+	Table   bool
+	Columns []string // keys from TableColumnKeys or "project:<field>"
+	Board   *Board   // shown as a board instead of a list or table
+	Sort    string   // a key from SortKeys; empty for newest first
+	GroupBy string   // see ValidGroupBy; empty for no groups
+	// ProjectFields keeps the issues with these project field values,
+	// keyed by field name. GitHub can't filter by them, so they are
+	// applied to the loaded issues.
+	ProjectFields map[string]string
+	// Types keeps the issues of these issue types, such as Bug, also
+	// applied to the loaded issues.
+	Types []string
+
+	// columnSort orders the loaded issues by a table column, overriding
+	// Sort until another sort is chosen.
+	columnSort tableSort
+	// collapsed holds the names of the collapsed groups.
+	collapsed map[string]bool
+}
+
+// DefaultSection lists the open issues.
+func DefaultSection() Section {
+	return Section{Title: "Open Issues", States: []string{"OPEN"}}
 }
 
 // DashboardView is the main view showing open issues.
 type DashboardView struct {
-	list        list.Model
-	issueClient *data.IssueClient
-	paginator   *data.Paginator
-	spinner     *components.Spinner
-	styles      ui.Styles
-	keys        ui.KeyMap
-	loading     bool
-	loadingMore bool
-	errMsg      string
-	width       int
-	height      int
-	pageSize    int
+	list          list.Model
+	issueClient   *data.IssueClient
+	paginator     *data.Paginator
+	spinner       *components.Spinner
+	styles        ui.Styles
+	keys          ui.KeyMap
+	ctx           context.Context
+	cancel        context.CancelFunc
+	requestID     int64
+	pageRequestID int64
+	loading       bool
+	loadingMore   bool
+	errMsg        string
+	width         int
+	height        int
+	pageSize      int
+
+	sections []Section
+	section  int
+	// issues are the loaded issues in the order the server returned them.
+	issues []data.Issue
+
+	// labelGroupings are the label prefix groupings the sections were
+	// configured with, offered when cycling the grouping.
+	labelGroupings []string
+
+	labelClient   *data.LabelClient
+	projectClient *data.ProjectClient
+	board         *boardState
+
+	sortMenu       *components.Picker
+	sortMemory     SortMemory
+	priorityLabels []string
+
+	// copyMenu offers the copies of the selected issue.
+	copyMenu *components.Picker
+	copies   []issueCopy
 }
 
 // NewDashboardView creates a new dashboard view.
@@ -130,6 +207,7 @@ func NewDashboardViewWithPageSize(client *data.IssueClient, styles ui.Styles, ke
 
 	spinner := components.NewSpinner(styles.Spinner)
 	paginator := data.NewPaginator(pageSize)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return &DashboardView{
 		list:        l,
@@ -138,27 +216,91 @@ func NewDashboardViewWithPageSize(client *data.IssueClient, styles ui.Styles, ke
 		spinner:     spinner,
 		styles:      styles,
 		keys:        keys,
+		ctx:         ctx,
+		cancel:      cancel,
 		loading:     true,
 		width:       width,
 		height:      height,
 		pageSize:    pageSize,
+		sections:    []Section{DefaultSection()},
+
+		priorityLabels: DefaultPriorityLabels,
 	}
 }
 
+// SetSections replaces the dashboard's sections. Tab and shift+tab switch
+// between them.
+func (d *DashboardView) SetSections(sections []Section) {
+	if len(sections) == 0 {
+		sections = []Section{DefaultSection()}
+	}
+	d.sections = sections
+	d.section = 0
+	d.labelGroupings = nil
+	for _, sec := range sections {
+		if strings.HasPrefix(sec.GroupBy, "label:") && !slices.Contains(d.labelGroupings, sec.GroupBy) {
+			d.labelGroupings = append(d.labelGroupings, sec.GroupBy)
+		}
+	}
+	d.applyLayout()
+}
+
 // Init implements ui.View.
 func (d *DashboardView) Init() tea.Cmd {
-	client := d.issueClient
-	pageSize := d.pageSize
 	spinCmd := d.spinner.Start("Loading issues...")
 	statusCmd := ui.StatusLoading("Loading issues...")
-	fetchCmd := func() tea.Msg {
-		result, err := client.List(data.IssueListOptions{
-			States: []string{"OPEN"},
-			First:  pageSize,
-		})
-		return ui.IssuesLoadedMsg{Result: result, Err: err}
-	}
-	return tea.Batch(spinCmd, statusCmd, fetchCmd)
+	return tea.Batch(spinCmd, statusCmd, d.fetchSection())
+}
+
+// Close implements ui.Closer, cancelling any in-flight requests.
+func (d *DashboardView) Close() {
+	d.cancel()
+}
+
+// OwnsRequest implements ui.RequestOwner.
+func (d *DashboardView) OwnsRequest(id int64) bool {
+	if d.board != nil && (id == d.board.requestID || id == d.board.moveRequestID) {
+		return true
+	}
+	return id == d.requestID || id == d.pageRequestID
+}
+
+// fetchIssues returns a command that loads open issues. Without a cursor it
+// reloads the first page and abandons any pending page load; with one it
+// loads the next page. Only the response to the latest request is applied.
+func (d *DashboardView) fetchIssues(first int, after string) tea.Cmd {
+	ctx := d.ctx
+	client := d.issueClient
+	sec := d.sections[d.section]
+	opts := data.IssueListOptions{States: sec.States, Labels: sec.Labels, OrderBy: findSort(sec.Sort).order, First: first, After: after}
+	projects := sec.usesProjects()
+	id := ui.NextRequestID()
+
+	list := func() (data.IssueListResult, error) {
+		result, err := client.ListContext(ctx, opts)
+		if projects && (err == nil || data.IsPartialData(err)) {
+			if perr := client.LoadProjectsContext(ctx, result.Issues); perr != nil && (err == nil || !data.IsPartialData(perr)) {
+				err = perr
+			}
+		}
+		return result, err
+	}
+
+	if after == "" {
+		d.requestID = id
+		d.pageRequestID = 0
+		d.loadingMore = false
+		return func() tea.Msg {
+			result, err := list()
+			return ui.IssuesLoadedMsg{RequestID: id, Result: result, Err: err}
+		}
+	}
+
+	d.pageRequestID = id
+	return func() tea.Msg {
+		result, err := list()
+		return ui.IssuesPageLoadedMsg{RequestID: id, Result: result, Err: err}
+	}
 }
 
 // Update implements ui.View.
@@ -169,93 +311,133 @@ func (d *DashboardView) Update(msg tea.Msg) (ui.View, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		d.width = msg.Width
 		d.height = msg.Height - 1
-		d.list.SetSize(msg.Width, d.height)
+		d.applyLayout()
 		return d, nil
 
 	case ui.IssuesLoadedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
 		d.loading = false
 		d.spinner.Stop()
-		if msg.Err != nil {
+		if msg.Err != nil && !data.IsPartialData(msg.Err) {
 			d.errMsg = fmt.Sprintf("Error loading issues: %v", msg.Err)
 			return d, nil
 		}
 		d.errMsg = ""
 		d.paginator.Reset()
 		d.paginator.Update(msg.Result.PageInfo, len(msg.Result.Issues))
-		items := make([]list.Item, len(msg.Result.Issues))
-		for i, issue := range msg.Result.Issues {
-			items[i] = issueItem{issue: issue}
-		}
-		cmd := d.list.SetItems(items)
+		d.issues = msg.Result.Issues
+		cmd := d.setItems()
 		d.updateTitle()
-		statusCmd := ui.StatusInfo(fmt.Sprintf("Showing %d issues", d.paginator.TotalLoaded()))
+		statusCmd := loadedStatus(msg.Err, fmt.Sprintf("Showing %d issues", d.paginator.TotalLoaded()))
 		return d, tea.Batch(cmd, statusCmd)
 
 	case ui.IssuesPageLoadedMsg:
+		if msg.RequestID != d.pageRequestID {
+			return d, nil
+		}
 		d.loadingMore = false
 		d.spinner.Stop()
-		if msg.Err != nil {
+		if msg.Err != nil && !data.IsPartialData(msg.Err) {
 			d.errMsg = fmt.Sprintf("Error loading more issues: %v", msg.Err)
 			return d, nil
 		}
 		d.errMsg = ""
 		d.paginator.Update(msg.Result.PageInfo, len(msg.Result.Issues))
-		// Append new items to existing list
-		existing := d.list.Items()
-		for _, issue := range msg.Result.Issues {
-			existing = append(existing, issueItem{issue: issue})
-		}
-		cmd := d.list.SetItems(existing)
+		d.issues = append(d.issues, msg.Result.Issues...)
+		cmd := d.setItems()
 		d.updateTitle()
-		statusCmd := ui.StatusInfo(fmt.Sprintf("Showing %d issues", d.paginator.TotalLoaded()))
+		statusCmd := loadedStatus(msg.Err, fmt.Sprintf("Showing %d issues", d.paginator.TotalLoaded()))
 		return d, tea.Batch(cmd, statusCmd)
 
 	case tea.KeyMsg:
+		if d.sortMenu != nil {
+			return d, d.updateSortMenu(msg)
+		}
+		if d.copyMenu != nil {
+			return d, d.updateCopyMenu(msg)
+		}
+		if d.onBoard() {
+			if cmd, ok := d.updateBoard(msg); ok {
+				return d, cmd
+			}
+		}
 		if key.Matches(msg, d.keys.Open) {
-			item, ok := d.list.SelectedItem().(issueItem)
-			if ok {
+			switch item := d.list.SelectedItem().(type) {
+			case issueItem:
 				return d, func() tea.Msg {
 					return ui.NavigateToDetailMsg{IssueNumber: item.issue.Number}
 				}
+			case groupItem:
+				return d, d.toggleGroup()
 			}
 		}
 		if key.Matches(msg, d.keys.Refresh) {
 			d.loading = true
 			d.errMsg = ""
-			client := d.issueClient
-			pageSize := d.pageSize
 			spinCmd := d.spinner.Start("Refreshing...")
 			statusCmd := ui.StatusLoading("Refreshing issues...")
-			fetchCmd := func() tea.Msg {
-				result, err := client.List(data.IssueListOptions{
-					States: []string{"OPEN"},
-					First:  pageSize,
-				})
-				return ui.IssuesLoadedMsg{Result: result, Err: err}
+			return d, tea.Batch(spinCmd, statusCmd, d.fetchSection())
+		}
+		if key.Matches(msg, d.keys.NextSection) && len(d.sections) > 1 {
+			return d, d.switchSection(d.section + 1)
+		}
+		if key.Matches(msg, d.keys.PrevSection) && len(d.sections) > 1 {
+			return d, d.switchSection(d.section - 1)
+		}
+		if key.Matches(msg, d.keys.ToggleLayout) && !d.loading && !d.onBoard() {
+			return d, d.toggleLayout()
+		}
+		if key.Matches(msg, d.keys.GroupBy) && !d.loading && !d.onBoard() {
+			return d, d.cycleGrouping()
+		}
+		if key.Matches(msg, d.keys.Collapse) && d.sections[d.section].GroupBy != "" && !d.loading {
+			return d, d.toggleGroup()
+		}
+		if key.Matches(msg, d.keys.SortCycle) && !d.loading && !d.onBoard() {
+			return d, d.cycleSort()
+		}
+		if key.Matches(msg, d.keys.SortMenu) && !d.loading && !d.onBoard() {
+			d.openSortMenu()
+			return d, nil
+		}
+		if key.Matches(msg, d.keys.Browse) {
+			if issue, ok := d.selectedIssue(); ok {
+				return d, browseIssue(issue)
+			}
+		}
+		if key.Matches(msg, d.keys.CopyRef) {
+			d.openCopyMenu()
+			return d, nil
+		}
+		if d.sections[d.section].Table && !d.loading {
+			if n, ok := sortColumnKey(msg.String()); ok {
+				return d, d.sortByColumn(n)
 			}
-			return d, tea.Batch(spinCmd, statusCmd, fetchCmd)
 		}
 		if key.Matches(msg, d.keys.NextPage) && !d.loading && !d.loadingMore {
 			req := d.paginator.NextPageRequest()
 			if req != nil {
 				d.loadingMore = true
-				client := d.issueClient
-				after := req.After
-				first := req.First
 				spinCmd := d.spinner.Start("Loading more...")
 				statusCmd := ui.StatusLoading("Loading more issues...")
-				fetchCmd := func() tea.Msg {
-					result, err := client.List(data.IssueListOptions{
-						States: []string{"OPEN"},
-						First:  first,
-						After:  after,
-					})
-					return ui.IssuesPageLoadedMsg{Result: result, Err: err}
-				}
-				return d, tea.Batch(spinCmd, statusCmd, fetchCmd)
+				return d, tea.Batch(spinCmd, statusCmd, d.fetchIssues(req.First, req.After))
 			}
 			return d, ui.StatusInfo(fmt.Sprintf("Showing %d issues", d.paginator.TotalLoaded()))
 		}
+		if d.onBoard() {
+			return d, nil
+		}
+
+	case ui.BoardLoadedMsg:
+		if d.board == nil || msg.RequestID != d.board.requestID {
+			return d, nil
+		}
+		return d, d.applyBoard(msg)
+
+	case ui.BoardMovedMsg:
+		return d, d.applyMove(msg)
 	}
 
 	// Update spinner
@@ -285,24 +467,88 @@ func (d *DashboardView) View() string {
 		return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, errView)
 	}
 
-	return d.list.View()
+	var sb strings.Builder
+	if len(d.sections) > 1 {
+		sb.WriteString(d.tabsView())
+		sb.WriteString("\n")
+	}
+	if d.onBoard() {
+		sb.WriteString(d.tableTitleView())
+		sb.WriteString("\n")
+		sb.WriteString(d.boardView(d.height - strings.Count(sb.String(), "\n")))
+		if d.copyMenu != nil {
+			return overlayBottom(sb.String(), d.copyMenu.View(), d.height)
+		}
+		return sb.String()
+	}
+	if d.sections[d.section].Table {
+		sb.WriteString(d.tableTitleView())
+		sb.WriteString("\n")
+		cols, titleWidth := fitColumns(d.sections[d.section].columns(), d.width)
+		sb.WriteString(tableHeader(cols, titleWidth, d.sections[d.section].columnSort, d.styles.HelpKey.Bold(true)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(d.list.View())
+	if d.sortMenu != nil {
+		return overlayBottom(sb.String(), d.sortMenu.View(), d.height)
+	}
+	if d.copyMenu != nil {
+		return overlayBottom(sb.String(), d.copyMenu.View(), d.height)
+	}
+	return sb.String()
 }
 
 // KeyHints implements ui.View.
 func (d *DashboardView) KeyHints() []string {
+	if d.sortMenu != nil {
+		return []string{"j/k: navigate", "1-7/enter: sort", "esc: cancel"}
+	}
+	if d.copyMenu != nil {
+		return []string{"j/k: navigate", "1-4/enter: copy", "esc: cancel"}
+	}
+	if d.onBoard() {
+		return d.boardKeyHints()
+	}
 	hints := []string{"j/k: navigate", "enter: open", "R: refresh"}
 	if d.paginator.HasNextPage() {
 		hints = append(hints, "L: load more")
 	}
+	if len(d.sections) > 1 {
+		hints = append(hints, "tab: section")
+	}
+	if d.sections[d.section].Table {
+		hints = append(hints, "1-9: sort", "t: list")
+	} else {
+		hints = append(hints, "t: table")
+	}
+	hints = append(hints, "s/S: sort", "b: group")
+	if d.sections[d.section].GroupBy != "" {
+		hints = append(hints, "c: collapse")
+	}
 	hints = append(hints, "q: quit")
 	return hints
 }
 
+// loadedStatus reports a successful load, unless err is a partial data
+// warning, which the app is already showing in the status bar.
+func loadedStatus(err error, text string) tea.Cmd {
+	if err != nil {
+		return nil
+	}
+	return ui.StatusInfo(text)
+}
+
 func (d *DashboardView) updateTitle() {
+	title := d.sections[d.section].Title
 	total := d.paginator.TotalLoaded()
 	if d.paginator.HasNextPage() {
-		d.list.Title = fmt.Sprintf("Open Issues (showing %d+)", total)
+		d.list.Title = fmt.Sprintf("%s (showing %d+)", title, total)
 	} else {
-		d.list.Title = fmt.Sprintf("Open Issues (%d)", total)
+		d.list.Title = fmt.Sprintf("%s (%d)", title, total)
+	}
+	d.list.Title += d.filterTitle()
+	d.list.Title += d.sortTitle()
+	if groupBy := d.sections[d.section].GroupBy; groupBy != "" {
+		d.list.Title += " · by " + groupBy
 	}
 }
@@ -134,3 +134,28 @@ func TestDashboard_RefreshTriggersLoad(t *testing.T) {
 		t.Error("expected command from refresh")
 	}
 }
+
+func TestDashboard_RefreshDiscardsPendingPage(t *testing.T) {
+	client := data.NewIssueClient(&mockQuerier{}, "owner", "repo")
+	dv := NewDashboardView(client, ui.DefaultStyles(), ui.DefaultKeyMap(), 80, 24)
+	dv.Init()
+	dv.Update(ui.IssuesLoadedMsg{
+		RequestID: dv.requestID,
+		Result: data.IssueListResult{
+			Issues:   []data.Issue{{Number: 1, Title: "First", CreatedAt: time.Now()}},
+			PageInfo: data.PageInfo{HasNextPage: true, EndCursor: "c1"},
+		},
+	})
+
+	dv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	pending := dv.pageRequestID
+	dv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+
+	dv.Update(ui.IssuesPageLoadedMsg{
+		RequestID: pending,
+		Result:    data.IssueListResult{Issues: []data.Issue{{Number: 2, Title: "Second", CreatedAt: time.Now()}}},
+	})
+	if n := len(dv.list.Items()); n != 1 {
+		t.Fatalf("expected stale page to be discarded, got %d items", n)
+	}
+}
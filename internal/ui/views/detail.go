@@ -1,6 +1,7 @@
 package views
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/cboone/gh-problemas/internal/ui/components"
 	"github.com/cboone/gh-problemas/internal/utils"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -23,6 +25,9 @@ type DetailView struct {
 	styles          ui.Styles
 	keys            ui.KeyMap
 	dateFormat      string
+	ctx             context.Context
+	cancel          context.CancelFunc
+	requestID       int64
 	issueNumber     int
 	issue           *data.Issue
 	comments        []data.Comment
@@ -31,6 +36,36 @@ type DetailView struct {
 	errMsg          string
 	width           int
 	height          int
+
+	timelineClient *data.TimelineClient
+	events         []data.TimelineEvent
+
+	reactionClient *data.ReactionClient
+	picker         *components.Picker
+	pickerHint     string
+	pickerChoose   func(i int) tea.Cmd
+	pickerTarget   reactionTarget
+
+	// blocks are the body, comments, and events in display order. The
+	// focused block is kept by key across re-renders.
+	blocks    []detailBlock
+	focus     int
+	focusKey  string
+	collapsed map[string]bool
+	// confirmDelete is the ID of the comment awaiting confirmation.
+	confirmDelete string
+	// markdown caches rendered bodies by width and source.
+	markdown map[string]string
+	// hyperlinks makes references in rendered bodies OSC 8 hyperlinks.
+	hyperlinks bool
+
+	projectClient *data.ProjectClient
+	// fieldInput prompts for the value of editField, a project field that
+	// has no options.
+	fieldInput *textinput.Model
+	editField  data.ProjectField
+
+	issueTypeClient *data.IssueTypeClient
 }
 
 // NewDetailView creates a new detail view for the given issue number.
@@ -51,6 +86,7 @@ func NewDetailViewWithCommentsAndDateFormat(client *data.IssueClient, commentCli
 	if dateFormat == "" {
 		dateFormat = "relative"
 	}
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return &DetailView{
 		viewport:      vp,
@@ -60,26 +96,62 @@ func NewDetailViewWithCommentsAndDateFormat(client *data.IssueClient, commentCli
 		styles:        styles,
 		keys:          keys,
 		dateFormat:    dateFormat,
+		ctx:           ctx,
+		cancel:        cancel,
 		issueNumber:   issueNumber,
 		loading:       true,
 		width:         width,
 		height:        height,
+		collapsed:     map[string]bool{},
+		markdown:      map[string]string{},
 	}
 }
 
+// SetReactionClient enables the reaction picker, which adds and removes
+// the viewer's reactions through client.
+func (d *DetailView) SetReactionClient(client *data.ReactionClient) {
+	d.reactionClient = client
+}
+
+// SetTimelineClient interleaves the issue's timeline events with its
+// comments.
+func (d *DetailView) SetTimelineClient(client *data.TimelineClient) {
+	d.timelineClient = client
+}
+
 // Init implements ui.View.
 func (d *DetailView) Init() tea.Cmd {
+	ctx := d.ctx
 	client := d.issueClient
 	number := d.issueNumber
+	id := ui.NextRequestID()
+	d.requestID = id
 	spinCmd := d.spinner.Start("Loading issue...")
 	statusCmd := ui.StatusLoading("Loading issue...")
 	fetchCmd := func() tea.Msg {
-		issue, err := client.Get(number)
-		return ui.IssueDetailLoadedMsg{Issue: issue, Err: err}
+		issue, err := client.GetContext(ctx, number)
+		if err == nil || data.IsPartialData(err) {
+			projects := []data.Issue{issue}
+			if perr := client.LoadProjectsContext(ctx, projects); perr != nil && (err == nil || !data.IsPartialData(perr)) {
+				err = perr
+			}
+			issue = projects[0]
+		}
+		return ui.IssueDetailLoadedMsg{RequestID: id, Issue: issue, Err: err}
 	}
 	return tea.Batch(spinCmd, statusCmd, fetchCmd)
 }
 
+// Close implements ui.Closer, cancelling any in-flight requests.
+func (d *DetailView) Close() {
+	d.cancel()
+}
+
+// OwnsRequest implements ui.RequestOwner.
+func (d *DetailView) OwnsRequest(id int64) bool {
+	return id == d.requestID
+}
+
 // Update implements ui.View.
 func (d *DetailView) Update(msg tea.Msg) (ui.View, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -96,9 +168,12 @@ func (d *DetailView) Update(msg tea.Msg) (ui.View, tea.Cmd) {
 		return d, nil
 
 	case ui.IssueDetailLoadedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
 		d.loading = false
 		d.spinner.Stop()
-		if msg.Err != nil {
+		if msg.Err != nil && !data.IsPartialData(msg.Err) {
 			d.errMsg = fmt.Sprintf("Error loading issue: %v", msg.Err)
 			return d, nil
 		}
@@ -111,41 +186,156 @@ func (d *DetailView) Update(msg tea.Msg) (ui.View, tea.Cmd) {
 		d.renderContent()
 		// Fetch comments if we have a comment client
 		if d.commentClient != nil {
+			ctx := d.ctx
 			cc := d.commentClient
 			number := d.issueNumber
+			id := d.requestID
 			statusCmd := ui.StatusLoading("Loading comments...")
 			fetchCmd := func() tea.Msg {
-				result, err := cc.List(number, 25, "")
+				result, err := cc.ListContext(ctx, number, 25, "")
 				return ui.CommentsLoadedMsg{
-					Comments: result.Comments,
-					PageInfo: result.PageInfo,
-					Err:      err,
+					RequestID: id,
+					Comments:  result.Comments,
+					PageInfo:  result.PageInfo,
+					Err:       err,
 				}
 			}
-			return d, tea.Batch(statusCmd, fetchCmd)
+			return d, tea.Batch(statusCmd, fetchCmd, d.fetchTimeline())
 		}
-		return d, ui.StatusInfo(fmt.Sprintf("Loaded issue #%d", msg.Issue.Number))
+		return d, tea.Batch(loadedStatus(msg.Err, fmt.Sprintf("Loaded issue #%d", msg.Issue.Number)), d.fetchTimeline())
 
 	case ui.CommentsLoadedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
 		d.loadingComments = false
-		if msg.Err != nil {
+		if msg.Err != nil && !data.IsPartialData(msg.Err) {
 			d.errMsg = fmt.Sprintf("Error loading comments: %v", msg.Err)
 			return d, nil
 		}
 		d.comments = msg.Comments
 		d.renderContent()
 		if len(msg.Comments) == 0 {
-			return d, ui.StatusInfo("No comments")
+			return d, loadedStatus(msg.Err, "No comments")
+		}
+		return d, loadedStatus(msg.Err, fmt.Sprintf("Loaded %d comments", len(msg.Comments)))
+
+	case ui.TimelineLoadedMsg:
+		// Events only add context, so a failure is left to the status bar
+		// and the thread is shown without them.
+		if msg.RequestID != d.requestID || msg.Err != nil && !data.IsPartialData(msg.Err) {
+			return d, nil
+		}
+		d.events = msg.Events
+		d.renderContent()
+		return d, nil
+
+	case ui.ReactionsUpdatedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applyReactions(msg)
+
+	case ui.CommentSavedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applySavedComment(msg)
+
+	case ui.CommentDeletedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applyDeletedComment(msg)
+
+	case ui.CommentMinimizedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applyMinimizedComment(msg)
+
+	case ui.ProjectFieldsLoadedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.openFieldPicker(msg)
+
+	case ui.IssueTypesLoadedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.openTypePicker(msg)
+
+	case ui.IssueTypeUpdatedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applyIssueType(msg)
+
+	case ui.IssueBodyUpdatedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applyBody(msg)
+
+	case ui.ProjectFieldUpdatedMsg:
+		if msg.RequestID != d.requestID {
+			return d, nil
+		}
+		return d, d.applyProjectField(msg)
+
+	case commentEditedMsg:
+		if msg.requestID != d.requestID {
+			return d, nil
 		}
-		return d, ui.StatusInfo(fmt.Sprintf("Loaded %d comments", len(msg.Comments)))
+		return d, d.saveComment(msg)
 
 	case tea.KeyMsg:
+		if d.fieldInput != nil {
+			return d, d.updateFieldInput(msg)
+		}
+		if d.picker != nil {
+			return d, d.updatePicker(msg)
+		}
+		if d.confirmDelete != "" {
+			return d, d.updateConfirmDelete(msg)
+		}
+		if key.Matches(msg, d.keys.React) && d.reactionClient != nil && d.issue != nil && !d.loadingComments {
+			return d, d.openReactionPicker()
+		}
+		if key.Matches(msg, d.keys.Project) && d.projectClient != nil && d.issue != nil {
+			return d, d.loadProjectFields()
+		}
+		if key.Matches(msg, d.keys.SubIssues) && d.issue != nil {
+			return d, d.openHierarchyPicker()
+		}
+		if key.Matches(msg, d.keys.Tasks) && d.issue != nil {
+			return d, d.openTaskPicker()
+		}
+		if key.Matches(msg, d.keys.IssueType) && d.issueTypeClient != nil && d.issue != nil {
+			return d, d.loadIssueTypes()
+		}
+		if key.Matches(msg, d.keys.Browse) && d.issue != nil {
+			return d, browseIssue(*d.issue)
+		}
+		if key.Matches(msg, d.keys.CopyRef) && d.issue != nil {
+			d.openCopyPicker()
+			return d, nil
+		}
+		if key.Matches(msg, d.keys.Links) && d.issue != nil {
+			return d, d.openLinksPicker()
+		}
 		if key.Matches(msg, d.keys.Back) {
 			return d, func() tea.Msg { return ui.NavigateBackMsg{} }
 		}
 		if key.Matches(msg, d.keys.Quit) {
 			return d, func() tea.Msg { return ui.NavigateBackMsg{} }
 		}
+		if d.issue != nil {
+			if cmd, ok := d.updateBlocks(msg); ok {
+				return d, cmd
+			}
+		}
 	}
 
 	// Update spinner
@@ -156,14 +346,34 @@ func (d *DetailView) Update(msg tea.Msg) (ui.View, tea.Cmd) {
 
 	// Delegate to viewport
 	var vpCmd tea.Cmd
+	offset := d.viewport.YOffset
 	d.viewport, vpCmd = d.viewport.Update(msg)
 	if vpCmd != nil {
 		cmds = append(cmds, vpCmd)
 	}
+	if d.viewport.YOffset != offset {
+		d.syncFocus()
+	}
 
 	return d, tea.Batch(cmds...)
 }
 
+// fetchTimeline loads the issue's timeline events, if there is a timeline
+// client.
+func (d *DetailView) fetchTimeline() tea.Cmd {
+	if d.timelineClient == nil {
+		return nil
+	}
+	ctx := d.ctx
+	tc := d.timelineClient
+	number := d.issueNumber
+	id := d.requestID
+	return func() tea.Msg {
+		result, err := tc.ListContext(ctx, number, 50, "")
+		return ui.TimelineLoadedMsg{RequestID: id, Events: result.Events, PageInfo: result.PageInfo, Err: err}
+	}
+}
+
 // View implements ui.View.
 func (d *DetailView) View() string {
 	if d.loading {
@@ -175,37 +385,188 @@ func (d *DetailView) View() string {
 		return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, errView)
 	}
 
+	if d.picker != nil {
+		return overlayBottom(d.viewport.View(), d.picker.View(), d.height)
+	}
+	if d.fieldInput != nil {
+		return overlayBottom(d.viewport.View(), d.fieldInputView(), d.height)
+	}
 	return d.viewport.View()
 }
 
 // KeyHints implements ui.View.
 func (d *DetailView) KeyHints() []string {
-	return []string{"j/k: scroll", "esc: back", "q: back"}
+	if d.picker != nil {
+		return []string{d.pickerHint, "esc: cancel"}
+	}
+	if d.fieldInput != nil {
+		return []string{"enter: save", "esc: cancel"}
+	}
+	if d.confirmDelete != "" {
+		return []string{"y: delete", "any other key: cancel"}
+	}
+	if d.issue == nil {
+		return []string{"j/k: scroll", "esc: back", "q: back"}
+	}
+
+	hints := []string{"n/p: block"}
+	if d.commentClient != nil {
+		hints = append(hints, "r: reply")
+	}
+	if d.reactionClient != nil {
+		hints = append(hints, "+: react")
+	}
+	if d.projectClient != nil && len(d.issue.Projects) > 0 {
+		hints = append(hints, "P: project")
+	}
+	if d.issue.Parent != nil || len(d.issue.SubIssues) > 0 {
+		hints = append(hints, "I: sub-issues")
+	}
+	if _, total := data.TaskProgress(d.issue.Body); total > 0 {
+		hints = append(hints, "x: tasks")
+	}
+	if d.issueTypeClient != nil && d.issue.Type.Name != "" {
+		hints = append(hints, "T: type")
+	}
+	hints = append(hints, "y: copy")
+	if c, ok := d.focusedComment(); ok && d.commentClient != nil {
+		if c.ViewerCanUpdate {
+			hints = append(hints, "e: edit")
+		}
+		if c.ViewerCanMinimize {
+			hints = append(hints, "M: hide")
+		}
+	}
+	return append(hints, "esc: back")
 }
 
+// gutterWidth is the width of the focus marker to the left of each block.
+const gutterWidth = 2
+
 func (d *DetailView) renderContent() {
 	if d.issue == nil {
 		return
 	}
+	d.buildBlocks()
+
+	opts := RenderOptions{
+		Width:           d.width,
+		DateFormat:      d.dateFormat,
+		LoadingComments: d.loadingComments,
+	}
+	if d.issueClient != nil {
+		opts.Repo = d.issueClient.Repo()
+	}
+	blockOpts := opts
+	blockOpts.Width = d.width - gutterWidth
+
+	var sb strings.Builder
+	writeIssueHeader(&sb, *d.issue, opts)
+	for i := range d.blocks {
+		b := &d.blocks[i]
+		switch {
+		case i == 1:
+			writeSectionHeader(&sb, opts.Width, d.activityTitle())
+		case i > 1 && (b.kind == commentBlock || d.blocks[i-1].kind == commentBlock):
+			writeThinDivider(&sb, opts.Width)
+		}
+		b.start = strings.Count(sb.String(), "\n")
+		sb.WriteString(d.gutter(d.renderBlock(*b, blockOpts), i == d.focus))
+		b.end = strings.Count(sb.String(), "\n")
+	}
+	if len(d.comments) == 0 && d.loadingComments {
+		sb.WriteString("\n")
+		sb.WriteString(metaStyle.Render("Loading comments..."))
+	}
+	d.viewport.SetContent(sb.String())
+}
+
+// renderMarkdown renders a body, reusing earlier renders of the same text
+// at the same width.
+func (d *DetailView) renderMarkdown(body string, width int) (string, error) {
+	cacheKey := fmt.Sprintf("%d\x00%s", width, body)
+	if out, ok := d.markdown[cacheKey]; ok {
+		return out, nil
+	}
+	out, err := utils.RenderMarkdown(body, width)
+	if err != nil {
+		return out, err
+	}
+	if base := issueBase(d.issue.URL); d.hyperlinks && base != "" {
+		out = linkReferences(out, body, base, d.issueClient.Repo())
+	}
+	d.markdown[cacheKey] = out
+	return out, nil
+}
+
+// RenderOptions configures RenderIssue.
+type RenderOptions struct {
+	Width           int
+	DateFormat      string
+	LoadingComments bool
+	NoColor         bool
+	// Repo is the owner/name of the issue's repository; sub-issues in
+	// other repositories are named with theirs.
+	Repo string
+}
+
+var (
+	metaStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	dividerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+)
+
+// markdownRenderer renders a markdown body at a width.
+type markdownRenderer func(content string, width int) (string, error)
 
+// RenderIssue renders an issue header, metadata, markdown body, and comment
+// thread as displayed by the detail view.
+func RenderIssue(issue data.Issue, comments []data.Comment, opts RenderOptions) string {
 	var sb strings.Builder
-	issue := d.issue
+	renderMarkdown := utils.RenderMarkdown
+	if opts.NoColor {
+		renderMarkdown = utils.RenderMarkdownNoColor
+	}
+
+	writeIssueHeader(&sb, issue, opts)
+	sb.WriteString(renderIssueBody(issue, opts, renderMarkdown, false))
+
+	// Comments
+	if len(comments) > 0 {
+		writeSectionHeader(&sb, opts.Width, fmt.Sprintf("Comments (%d)", len(comments)))
+		for i, c := range comments {
+			if i > 0 {
+				writeThinDivider(&sb, opts.Width)
+			}
+			sb.WriteString(renderComment(c, opts, renderMarkdown, c.IsMinimized))
+		}
+	} else if opts.LoadingComments {
+		sb.WriteString("\n")
+		sb.WriteString(metaStyle.Render("Loading comments..."))
+	}
 
-	// Header
+	return sb.String()
+}
+
+// writeIssueHeader writes the title, metadata, and labels, followed by a
+// divider.
+func writeIssueHeader(sb *strings.Builder, issue data.Issue, opts RenderOptions) {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
 	numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
 	sb.WriteString(titleStyle.Render(issue.Title))
 	sb.WriteString(" ")
 	sb.WriteString(numberStyle.Render(fmt.Sprintf("#%d", issue.Number)))
+	if badge := renderTypeBadge(issue.Type, opts.NoColor); badge != "" {
+		sb.WriteString("  " + badge)
+	}
 	sb.WriteString("\n")
 
 	// Metadata line
 	metaParts := []string{
 		fmt.Sprintf("State: %s", issue.State),
 		fmt.Sprintf("Author: %s", issue.Author),
-		fmt.Sprintf("Created: %s", utils.FormatTime(issue.CreatedAt, d.dateFormat)),
-		fmt.Sprintf("Updated: %s", utils.FormatTime(issue.UpdatedAt, d.dateFormat)),
+		fmt.Sprintf("Created: %s", utils.FormatTime(issue.CreatedAt, opts.DateFormat)),
+		fmt.Sprintf("Updated: %s", utils.FormatTime(issue.UpdatedAt, opts.DateFormat)),
 	}
 	if issue.Milestone != "" {
 		metaParts = append(metaParts, fmt.Sprintf("Milestone: %s", issue.Milestone))
@@ -213,8 +574,10 @@ func (d *DetailView) renderContent() {
 	if len(issue.Assignees) > 0 {
 		metaParts = append(metaParts, fmt.Sprintf("Assignees: %s", strings.Join(issue.Assignees, ", ")))
 	}
+	if done, total := data.TaskProgress(issue.Body); total > 0 {
+		metaParts = append(metaParts, fmt.Sprintf("Tasks: %d/%d", done, total))
+	}
 
-	metaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 	sb.WriteString(metaStyle.Render(strings.Join(metaParts, "  ")))
 	sb.WriteString("\n")
 
@@ -222,6 +585,10 @@ func (d *DetailView) renderContent() {
 	if len(issue.Labels) > 0 {
 		var labelParts []string
 		for _, l := range issue.Labels {
+			if opts.NoColor {
+				labelParts = append(labelParts, "["+l.Name+"]")
+				continue
+			}
 			bg := utils.HexToColor(l.Color)
 			fg := utils.ContrastColor(l.Color)
 			style := lipgloss.NewStyle().Background(bg).Foreground(fg).Padding(0, 1)
@@ -230,15 +597,40 @@ func (d *DetailView) renderContent() {
 		sb.WriteString(strings.Join(labelParts, " "))
 		sb.WriteString("\n")
 	}
+	writeProjectValues(sb, issue)
+	writeHierarchy(sb, issue, opts.Repo)
+
+	sb.WriteString("\n")
+	sb.WriteString(dividerStyle.Render(strings.Repeat("─", opts.Width)))
+	sb.WriteString("\n\n")
+}
+
+// writeSectionHeader writes a divider and a heading such as "Comments (3)".
+func writeSectionHeader(sb *strings.Builder, width int, title string) {
+	sb.WriteString("\n")
+	sb.WriteString(dividerStyle.Render(strings.Repeat("─", width)))
+	sb.WriteString("\n")
+	commentHeaderStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	sb.WriteString(commentHeaderStyle.Render(title))
+	sb.WriteString("\n\n")
+}
 
+// writeThinDivider separates entries of the comment thread.
+func writeThinDivider(sb *strings.Builder, width int) {
 	sb.WriteString("\n")
-	divider := lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(strings.Repeat("─", d.width))
-	sb.WriteString(divider)
+	sb.WriteString(dividerStyle.Render(strings.Repeat("- ", width/2)))
 	sb.WriteString("\n\n")
+}
+
+// renderIssueBody renders the markdown body and its reactions.
+func renderIssueBody(issue data.Issue, opts RenderOptions, renderMarkdown markdownRenderer, collapsed bool) string {
+	if collapsed {
+		return metaStyle.Render("▸ Description collapsed") + "\n"
+	}
 
-	// Body
+	var sb strings.Builder
 	if issue.Body != "" {
-		rendered, err := utils.RenderMarkdown(issue.Body, d.width-4)
+		rendered, err := renderMarkdown(issue.Body, opts.Width-4)
 		if err != nil {
 			sb.WriteString(issue.Body)
 		} else {
@@ -247,48 +639,53 @@ func (d *DetailView) renderContent() {
 	} else {
 		sb.WriteString(metaStyle.Render("No description provided."))
 	}
+	if len(issue.ReactionGroups) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(renderReactions(issue.ReactionGroups, opts.NoColor))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
 
-	// Comments
-	if len(d.comments) > 0 {
+// renderComment renders a comment's author line, body, and reactions. A
+// collapsed comment shows only the author line, with the reason it was
+// hidden if it is minimized.
+func renderComment(c data.Comment, opts RenderOptions, renderMarkdown markdownRenderer, collapsed bool) string {
+	authorStyle := lipgloss.NewStyle().Bold(true)
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var sb strings.Builder
+	sb.WriteString(authorStyle.Render(c.Author))
+	sb.WriteString(" ")
+	sb.WriteString(timeStyle.Render(utils.FormatTime(c.CreatedAt, opts.DateFormat)))
+	if c.Reactions > 0 && len(c.ReactionGroups) == 0 {
+		sb.WriteString(timeStyle.Render(fmt.Sprintf("  %d reactions", c.Reactions)))
+	}
+	switch {
+	case collapsed && c.IsMinimized:
+		sb.WriteString(timeStyle.Render("  ▸ " + hiddenLabel(c)))
 		sb.WriteString("\n")
-		sb.WriteString(divider)
+		return sb.String()
+	case collapsed:
+		sb.WriteString(timeStyle.Render("  ▸ collapsed"))
 		sb.WriteString("\n")
-		commentHeaderStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
-		sb.WriteString(commentHeaderStyle.Render(fmt.Sprintf("Comments (%d)", len(d.comments))))
-		sb.WriteString("\n\n")
-
-		authorStyle := lipgloss.NewStyle().Bold(true)
-		timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-
-		for i, c := range d.comments {
-			sb.WriteString(authorStyle.Render(c.Author))
-			sb.WriteString(" ")
-			sb.WriteString(timeStyle.Render(utils.FormatTime(c.CreatedAt, d.dateFormat)))
-			if c.Reactions > 0 {
-				sb.WriteString(timeStyle.Render(fmt.Sprintf("  %d reactions", c.Reactions)))
-			}
-			sb.WriteString("\n")
-
-			if c.Body != "" {
-				rendered, err := utils.RenderMarkdown(c.Body, d.width-4)
-				if err != nil {
-					sb.WriteString(c.Body)
-				} else {
-					sb.WriteString(rendered)
-				}
-			}
+		return sb.String()
+	case c.IsMinimized:
+		sb.WriteString(timeStyle.Render("  " + hiddenLabel(c)))
+	}
+	sb.WriteString("\n")
 
-			if i < len(d.comments)-1 {
-				sb.WriteString("\n")
-				thinDivider := lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(strings.Repeat("- ", d.width/2))
-				sb.WriteString(thinDivider)
-				sb.WriteString("\n\n")
-			}
+	if c.Body != "" {
+		rendered, err := renderMarkdown(c.Body, opts.Width-4)
+		if err != nil {
+			sb.WriteString(c.Body)
+		} else {
+			sb.WriteString(rendered)
 		}
-	} else if d.loadingComments {
+	}
+	if len(c.ReactionGroups) > 0 {
+		sb.WriteString(renderReactions(c.ReactionGroups, opts.NoColor))
 		sb.WriteString("\n")
-		sb.WriteString(metaStyle.Render("Loading comments..."))
 	}
-
-	d.viewport.SetContent(sb.String())
+	return sb.String()
 }
@@ -1,6 +1,8 @@
 package views
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -33,3 +35,109 @@ func TestDetailView_UsesConfiguredDateFormat(t *testing.T) {
 		t.Fatalf("expected updated date in custom format, got: %q", out)
 	}
 }
+
+func TestDetailView_DiscardsStaleResponses(t *testing.T) {
+	client := data.NewIssueClient(&mockQuerier{}, "owner", "repo")
+	dv := NewDetailView(client, ui.DefaultStyles(), ui.DefaultKeyMap(), 7, 100, 30)
+	dv.Init()
+
+	updated, _ := dv.Update(ui.IssueDetailLoadedMsg{
+		RequestID: dv.requestID - 1,
+		Issue:     data.Issue{Number: 3, Title: "Some other issue"},
+	})
+	d := updated.(*DetailView)
+	if !d.loading || d.issue != nil {
+		t.Fatal("expected response to an earlier request to be ignored")
+	}
+
+	updated, _ = d.Update(ui.IssueDetailLoadedMsg{
+		RequestID: d.requestID,
+		Issue:     data.Issue{Number: 7, Title: "This issue"},
+	})
+	d = updated.(*DetailView)
+	if d.loading || d.issue == nil || d.issue.Number != 7 {
+		t.Fatal("expected response to the current request to be applied")
+	}
+}
+
+func TestDetailView_CloseCancelsFetch(t *testing.T) {
+	client := data.NewIssueClient(&mockQuerier{}, "owner", "repo")
+	dv := NewDetailView(client, ui.DefaultStyles(), ui.DefaultKeyMap(), 7, 100, 30)
+	dv.Init()
+	dv.Close()
+
+	issue, err := client.GetContext(dv.ctx, 7)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled after Close, got %v (%+v)", err, issue)
+	}
+}
+
+func TestRenderIssue_ReactionGroupsNoColor(t *testing.T) {
+	issue := data.Issue{
+		Number: 3,
+		Title:  "Reactions",
+		State:  "OPEN",
+		Author: "alice",
+		Body:   "hello",
+		ReactionGroups: []data.ReactionGroup{
+			{Content: "THUMBS_UP", Count: 3, ViewerHasReacted: true},
+			{Content: "HOORAY", Count: 1},
+		},
+	}
+	comments := []data.Comment{{
+		Author:         "bob",
+		Body:           "same",
+		Reactions:      2,
+		ReactionGroups: []data.ReactionGroup{{Content: "HEART", Count: 2}},
+	}}
+
+	out := RenderIssue(issue, comments, RenderOptions{Width: 80, DateFormat: "relative", NoColor: true})
+	for _, want := range []string{"[👍 3]  🎉 1", "❤️ 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "2 reactions") {
+		t.Errorf("comment header repeats the reaction count:\n%s", out)
+	}
+}
+
+func TestLinkReferences(t *testing.T) {
+	body := "See #2, octo/other#4 and #20."
+	rendered := "\x1b[38;5;252mSee #2, octo/other#4\x1b[0m and #20."
+	out := linkReferences(rendered, body, "https://github.com", "octo/hello")
+
+	link := func(url, text string) string {
+		return "\x1b]8;;" + url + "\x07" + text + "\x1b]8;;\x07"
+	}
+	want := "\x1b[38;5;252mSee " + link("https://github.com/octo/hello/issues/2", "#2") + ", " +
+		link("https://github.com/octo/other/issues/4", "octo/other#4") + "\x1b[0m and " +
+		link("https://github.com/octo/hello/issues/20", "#20") + "."
+	if out != want {
+		t.Errorf("linkReferences =\n%q\nwant\n%q", out, want)
+	}
+	if got := linkReferences("plain", "plain", "https://github.com", "octo/hello"); got != "plain" {
+		t.Errorf("text without references changed: %q", got)
+	}
+}
+
+func TestDetailView_RemovingLastReactionClearsCount(t *testing.T) {
+	dv := NewDetailViewWithCommentsAndDateFormat(nil, nil, ui.DefaultStyles(), ui.DefaultKeyMap(), 1, 100, 30, "relative")
+	dv.issue = &data.Issue{ID: "I_1", Number: 1, Title: "Reactions", Author: "alice"}
+	dv.comments = []data.Comment{{
+		ID:             "C_1",
+		Author:         "bob",
+		Body:           "Thanks!",
+		Reactions:      1,
+		ReactionGroups: []data.ReactionGroup{{Content: "THUMBS_UP", Count: 1, ViewerHasReacted: true}},
+	}}
+	dv.renderContent()
+
+	dv.applyReactions(ui.ReactionsUpdatedMsg{RequestID: dv.requestID, SubjectID: "C_1", Content: "THUMBS_UP", Removed: true})
+	if dv.comments[0].Reactions != 0 {
+		t.Errorf("comment reactions = %d, want 0", dv.comments[0].Reactions)
+	}
+	if out := dv.viewport.View(); strings.Contains(out, "1 reactions") {
+		t.Errorf("expected no stale reaction count, got:\n%s", out)
+	}
+}
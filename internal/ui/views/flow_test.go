@@ -0,0 +1,810 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/fakegithub"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/uitest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestApp builds the App as runApp does, with clients that query q.
+func newTestApp(q data.Querier, pageSize int) *ui.App {
+	issueClient := data.NewIssueClient(q, "octo", "hello")
+	commentClient := data.NewCommentClient(q, "octo", "hello")
+	reactionClient := data.NewReactionClient(q)
+	timelineClient := data.NewTimelineClient(q, "octo", "hello")
+	labelClient := data.NewLabelClient(q, "octo", "hello")
+	projectClient := data.NewProjectClient(q, "octo", "hello")
+	issueTypeClient := data.NewIssueTypeClient(q, "octo", "hello")
+	return ui.NewApp(
+		issueClient,
+		"octo/hello",
+		func(a *ui.App) ui.View {
+			dashboard := NewDashboardViewWithPageSize(a.IssueClient(), a.Styles(), a.Keys(), a.Width(), a.Height(), pageSize)
+			dashboard.SetLabelClient(labelClient)
+			dashboard.SetProjectClient(projectClient)
+			return dashboard
+		},
+		func(a *ui.App, issueNumber int) ui.View {
+			detail := NewDetailViewWithComments(a.IssueClient(), commentClient, a.Styles(), a.Keys(), issueNumber, a.Width(), a.Height())
+			detail.SetReactionClient(reactionClient)
+			detail.SetTimelineClient(timelineClient)
+			detail.SetProjectClient(projectClient)
+			detail.SetIssueTypeClient(issueTypeClient)
+			return detail
+		},
+	)
+}
+
+// flow drives the App against srv over HTTP, through the same querier
+// middleware as runApp.
+type flow struct {
+	*uitest.Driver
+	t   *testing.T
+	app *ui.App
+}
+
+func newFlow(t *testing.T, srv *fakegithub.Server, pageSize int) *flow {
+	t.Helper()
+
+	client, err := srv.GraphQLClient("github.com")
+	if err != nil {
+		t.Fatalf("GraphQLClient: %v", err)
+	}
+	querier := data.NewRateLimitQuerier(client)
+	querier.BaseDelay = time.Millisecond
+	querier.MaxDelay = time.Millisecond
+	querier.MaxRetries = 1
+
+	app := newTestApp(querier, pageSize)
+	f := &flow{Driver: uitest.NewDriver(app), t: t, app: app}
+	querier.OnRateLimit = func(rl data.RateLimit) {
+		f.Post(ui.RateLimitMsg{RateLimit: rl})
+	}
+
+	f.Resize(100, 30)
+	f.Init()
+	return f
+}
+
+// assertScreen checks that the rendered App contains each of want. Runs of
+// whitespace are collapsed, since the status bar may wrap.
+func (f *flow) assertScreen(want ...string) {
+	f.t.Helper()
+	screen := f.Screen()
+	text := strings.Join(strings.Fields(screen), " ")
+	for _, w := range want {
+		if !strings.Contains(text, w) {
+			f.t.Fatalf("screen does not contain %q:\n%s", w, screen)
+		}
+	}
+}
+
+func seedFlowRepo() *fakegithub.Server {
+	store := fakegithub.NewStore()
+	repo := store.AddRepo("octo", "hello")
+	repo.AddLabel("bug", "d73a4a")
+	titles := []string{"Login fails", "Dark mode", "Crash on save", "Typo in docs", "Slow search"}
+	for n, title := range titles {
+		repo.AddIssue(fakegithub.Issue{
+			Title:     title,
+			Body:      "Body of " + title,
+			Author:    "alice",
+			CreatedAt: fakegithub.Epoch.Add(-time.Duration(n) * time.Hour),
+		})
+	}
+	repo.AddComment(1, fakegithub.Comment{Author: "bob", Body: "Seeing this too"})
+	return fakegithub.NewServer(store)
+}
+
+func TestFlow_BrowsePaginateAndOpenIssue(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+
+	f := newFlow(t, srv, 3)
+	f.assertScreen("Login fails", "Crash on save", "Showing 3 issues", "pts")
+
+	f.Keys("L")
+	f.assertScreen("Showing 5 issues")
+
+	f.Keys("enter")
+	if f.app.ViewStackLen() != 2 {
+		t.Fatalf("view stack = %d, want detail view pushed", f.app.ViewStackLen())
+	}
+	f.assertScreen("Body of Login fails", "Seeing this too", "bob")
+
+	f.Keys("esc")
+	if f.app.ViewStackLen() != 1 {
+		t.Fatalf("view stack = %d, want back on the dashboard", f.app.ViewStackLen())
+	}
+
+	want := []string{"ListIssues", "ListIssues", "GetIssue", "IssueProjects", "ListComments", "ListTimeline"}
+	ops := srv.Operations()
+	if strings.Join(ops, ",") != strings.Join(want, ",") {
+		t.Errorf("operations = %v, want %v", ops, want)
+	}
+}
+
+func TestFlow_RefreshFailureThenRecovery(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+
+	f := newFlow(t, srv, 10)
+	srv.Fail("ListIssues", fakegithub.Failure{Status: http.StatusUnauthorized, Message: "Bad credentials", Times: 1})
+
+	f.Keys("R")
+	f.assertScreen("gh auth login")
+
+	srv.Store().Repo("octo", "hello").AddIssue(fakegithub.Issue{Title: "Filed meanwhile", CreatedAt: fakegithub.Epoch.Add(time.Hour)})
+	f.Keys("R")
+	f.assertScreen("Filed meanwhile", "Showing 6 issues")
+}
+
+func TestFlow_DeletedIssue(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+
+	f := newFlow(t, srv, 10)
+	srv.Store().Repo("octo", "hello").DeleteIssue(1)
+
+	f.Keys("enter")
+	f.assertScreen("Issue not found")
+}
+
+func TestFlow_ReactToIssueAndComment(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+
+	long := strings.Repeat("More details.\n\n", 40)
+	srv.Store().Repo("octo", "hello").AddComment(1, fakegithub.Comment{Author: "carol", Body: long})
+
+	f := newFlow(t, srv, 10)
+	f.Keys("enter")
+	f.assertScreen("+: react")
+
+	f.Keys("+")
+	f.assertScreen("React to #1", "1 👍 +1", "8 👀 eyes")
+	f.Keys("4")
+	f.assertScreen("Reacted with 🎉", "🎉 1")
+
+	f.Keys("+", "down", "down", "down", "enter")
+	f.assertScreen("Removed 🎉")
+	if strings.Contains(f.Screen(), "🎉 1") {
+		t.Errorf("reaction still shown after removing it:\n%s", f.Screen())
+	}
+
+	f.Keys("pgdown", "pgdown", "pgdown", "+")
+	f.assertScreen("React to comment by carol")
+	f.Keys("1")
+	f.assertScreen("👍 1")
+
+	issue := srv.Store().Repo("octo", "hello").Issue(1)
+	if len(issue.Reactions) != 0 {
+		t.Errorf("issue reactions = %v, want none", issue.Reactions)
+	}
+	if got := issue.Comments[1].Reactions; len(got) != 1 || got[0].Content != "THUMBS_UP" {
+		t.Errorf("comment reactions = %v, want one THUMBS_UP", got)
+	}
+}
+
+func TestFlow_FocusBlocksAndActOnThem(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.AddEvent(1, fakegithub.Event{Type: "LabeledEvent", Actor: "carol", Label: "bug", CreatedAt: fakegithub.Epoch.Add(time.Minute)})
+	repo.AddComment(1, fakegithub.Comment{Body: "My tpyo", CreatedAt: fakegithub.Epoch.Add(2 * time.Minute)})
+
+	f := newFlow(t, srv, 10)
+	var copied string
+	f.app.SetClipboard(func(text string) error {
+		copied = text
+		return nil
+	})
+	var editing string
+	reply := "My fixed text"
+	f.app.SetEditor(func(text string, done func(string, error) tea.Msg) tea.Cmd {
+		editing = text
+		return func() tea.Msg { return done(reply, nil) }
+	})
+
+	f.Keys("enter")
+	f.assertScreen("Activity (2 comments, 1 events)", "● carol added label bug", "▌ Body of Login fails")
+
+	f.Keys("y")
+	f.assertScreen("Copied description")
+	if copied != "Body of Login fails" {
+		t.Errorf("copied %q, want the issue body", copied)
+	}
+
+	f.Keys("n", "Y")
+	f.assertScreen("▌ bob", "Copied link")
+	if !strings.HasPrefix(copied, "https://github.com/octo/hello/issues/1#issuecomment-") {
+		t.Errorf("copied %q, want the comment permalink", copied)
+	}
+
+	f.Keys("n", "+")
+	f.assertScreen("▌ ● carol", "Events can't be reacted to")
+
+	f.Keys("n", "e")
+	if editing != "My tpyo" {
+		t.Errorf("editor opened with %q, want the comment body", editing)
+	}
+	f.assertScreen("My fixed text", "Comment updated")
+
+	f.Keys("p", "p")
+	reply = "> Seeing this too\n\nMe too"
+	f.Keys("r")
+	if editing != "> Seeing this too\n\n" {
+		t.Errorf("editor opened with %q, want the quoted comment", editing)
+	}
+	f.assertScreen("Seeing this too", "Me too", "Comment posted", "Activity (3 comments, 1 events)")
+
+	f.Keys("D", "n")
+	f.assertScreen("Kept the comment")
+	f.Keys("D", "y")
+	f.assertScreen("Comment deleted", "Activity (2 comments, 1 events)")
+
+	issue := repo.Issue(1)
+	if len(issue.Comments) != 2 || issue.Comments[1].Body != "My fixed text" {
+		t.Errorf("comments = %+v, %+v", issue.Comments[0], issue.Comments[len(issue.Comments)-1])
+	}
+
+	f.Keys("p", "p", "p", "p", "c")
+	f.assertScreen("Description collapsed")
+	f.Keys("c")
+	f.assertScreen("Body of Login fails")
+}
+
+func TestFlow_QuoteReplyDiscardedWhenUnchanged(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+
+	f := newFlow(t, srv, 10)
+	f.app.SetEditor(func(text string, done func(string, error) tea.Msg) tea.Cmd {
+		return func() tea.Msg { return done(text, nil) }
+	})
+
+	f.Keys("enter", "r")
+	f.assertScreen("Comment discarded")
+	if got := len(srv.Store().Repo("octo", "hello").Issue(1).Comments); got != 1 {
+		t.Errorf("comments = %d, want 1", got)
+	}
+}
+
+func TestFlow_MinimizedCommentsAndModeration(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.AddComment(1, fakegithub.Comment{Author: "spammer", Body: "Buy cheap watches", MinimizedReason: "SPAM", CreatedAt: fakegithub.Epoch.Add(time.Minute)})
+
+	f := newFlow(t, srv, 10)
+	f.Keys("enter")
+	f.assertScreen("spammer", "▸ hidden as spam")
+	if strings.Contains(f.Screen(), "Buy cheap watches") {
+		t.Fatalf("minimized comment is expanded:\n%s", f.Screen())
+	}
+
+	f.Keys("n", "e")
+	f.assertScreen("You can't edit this comment")
+	f.Keys("M")
+	f.assertScreen("You can't hide this comment")
+
+	f.Keys("n", "c")
+	f.assertScreen("Buy cheap watches", "hidden as spam")
+
+	repo.Maintainers = []string{"octocat"}
+	f.Keys("esc", "enter", "n", "n", "M")
+	f.assertScreen("Comment shown", "Buy cheap watches")
+
+	f.Keys("p", "M")
+	f.assertScreen("Hide comment by bob as", "3 off-topic")
+	f.Keys("3")
+	f.assertScreen("Comment hidden as off-topic", "bob", "▸ hidden as off-topic")
+	if got := repo.Issue(1).Comments[0].MinimizedReason; got != "OFF_TOPIC" {
+		t.Errorf("bob's comment minimized as %q, want OFF_TOPIC", got)
+	}
+}
+
+func TestFlow_SwitchSectionsAndLayouts(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	srv.Store().Repo("octo", "hello").AddIssue(fakegithub.Issue{Title: "Old crash", State: "CLOSED", Labels: []string{"bug"}})
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Open", States: []string{"OPEN"}},
+		{Title: "Closed bugs", States: []string{"CLOSED"}, Labels: []string{"bug"}, Table: true, Columns: []string{"number", "title", "labels"}},
+	})
+	f.Keys("R")
+	f.assertScreen("Open │ Closed bugs", "Open (5)")
+
+	f.Keys("tab")
+	f.assertScreen("Closed bugs (1)", "# Title Labels", "#6 Old crash bug")
+
+	f.Keys("t")
+	f.assertScreen("List layout", "Closed bugs (1)", "1 item")
+	f.Keys("enter")
+	f.assertScreen("Old crash #6", "State: CLOSED")
+	f.Keys("esc", "shift+tab")
+	f.assertScreen("Open (5)", "Login fails")
+}
+
+// sortMemory is a SortMemory kept in a map.
+type sortMemory map[string]string
+
+func (m sortMemory) Sort(section string) string { return m[section] }
+
+func (m sortMemory) SetSort(section, sort string) error {
+	m[section] = sort
+	return nil
+}
+
+// assertOrder checks that each of titles appears on screen after the one
+// before it.
+func (f *flow) assertOrder(titles ...string) {
+	f.t.Helper()
+	screen := f.Screen()
+	last := -1
+	for _, title := range titles {
+		i := strings.Index(screen, title)
+		if i <= last {
+			f.t.Fatalf("%q is not in order %q:\n%s", title, titles, screen)
+		}
+		last = i
+	}
+}
+
+func TestFlow_SortIssues(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.Issue(4).Reactions = []fakegithub.Reaction{{Content: "THUMBS_UP", User: "bob"}, {Content: "HEART", User: "carol"}}
+	repo.Issue(2).Reactions = []fakegithub.Reaction{{Content: "THUMBS_UP", User: "bob"}}
+	repo.Issue(3).Labels = []string{"p1"}
+	repo.Issue(5).Labels = []string{"P0"}
+
+	f := newFlow(t, srv, 10)
+	memory := sortMemory{"Open": "updated"}
+	dashboard := f.app.CurrentView().(*DashboardView)
+	dashboard.SetSections([]Section{{Title: "Open", States: []string{"OPEN"}}})
+	dashboard.SetSortMemory(memory)
+	f.Keys("R")
+	f.assertScreen("Open (5) · Recently updated")
+
+	f.Keys("s")
+	f.assertScreen("Open (5) · Least recently updated")
+	f.assertOrder("Slow search", "Login fails")
+
+	f.Keys("S")
+	f.assertScreen("Sort Open", "6 Most reactions (local)", "7 Priority (local)")
+	f.Keys("6")
+	f.assertScreen("Open (5) · Most reactions (sorted locally)")
+	f.assertOrder("Typo in docs", "Dark mode", "Login fails")
+
+	requests := len(srv.Operations())
+	f.Keys("s")
+	f.assertScreen("Open (5) · Priority (sorted locally)", "Sorted by priority (locally)")
+	f.assertOrder("Slow search", "Crash on save", "Login fails")
+	if n := len(srv.Operations()); n != requests {
+		t.Errorf("switching between local sorts made %d requests", n-requests)
+	}
+
+	f.Keys("s")
+	f.assertScreen("Open (5)", "Sorted by newest")
+	if memory["Open"] != "created" {
+		t.Errorf("remembered sort = %q, want created", memory["Open"])
+	}
+}
+
+func TestFlow_GroupIssues(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.AddLabel("area/ui", "0e8a16")
+	repo.AddLabel("area/api", "1d76db")
+	repo.Issue(2).Labels = []string{"area/ui"}
+	repo.Issue(4).Labels = []string{"area/ui"}
+	repo.Issue(3).Labels = []string{"area/api"}
+	repo.Issue(1).Assignees = []string{"bob"}
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{{Title: "Open", States: []string{"OPEN"}, GroupBy: "label:area/*"}})
+	f.Keys("R")
+	f.assertScreen("Open (5) · by label:area/*", "▾ area/api (1)", "▾ area/ui (2)", "▾ No area/* label (2)")
+	f.assertOrder("area/api", "Crash on save", "area/ui", "Dark mode", "Typo in docs", "No area/* label", "Login fails", "Slow search")
+
+	// Headers are selectable too; j moves onto the first group's issue.
+	f.Keys("g", "j", "enter")
+	f.assertScreen("Body of Crash on save")
+	f.Keys("esc")
+
+	// c collapses the group of the selected issue and selects its header.
+	f.Keys("j", "j", "c")
+	f.assertScreen("▸ area/ui (2)", "> ▸ area/ui")
+	if strings.Contains(f.Screen(), "Typo in docs") {
+		t.Fatalf("collapsed group still shows its issues:\n%s", f.Screen())
+	}
+	f.Keys("enter")
+	f.assertScreen("▾ area/ui (2)", "Typo in docs", "> ▾ area/ui")
+
+	f.Keys("b")
+	f.assertScreen("Open (5) · by label", "▾ No labels (2)")
+	f.Keys("b", "b")
+	f.assertScreen("Open (5) · by assignee", "▾ bob (1)", "▾ Unassigned (4)")
+	f.Keys("b", "b", "b")
+	f.assertScreen("Open (5) · by type", "▾ No type (5)")
+	f.Keys("b")
+	f.assertScreen("Open (5)", "Not grouped", "5 items")
+}
+
+func TestFlow_LabelBoardMovesIssues(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.AddLabel("status/todo", "ededed")
+	repo.AddLabel("status/doing", "fbca04")
+	repo.AddLabel("status/done", "0e8a16")
+	repo.Issue(1).Labels = []string{"status/todo", "bug"}
+	repo.Issue(2).Labels = []string{"status/todo"}
+	repo.Issue(3).Labels = []string{"status/done"}
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Status", States: []string{"OPEN"}, Board: &Board{Labels: []string{"status/todo", "status/doing", "status/done"}}},
+	})
+	f.Resize(120, 30)
+	f.Keys("R")
+	f.assertScreen("Status (5)", "No status (2)", "status/todo (2)", "status/doing (0)", "status/done (1)")
+
+	// Move #1 from todo to doing.
+	f.Keys("l", "j")
+	f.assertScreen("> #2 Dark mode")
+	f.Keys("k", ">")
+	f.assertScreen("Moved #1 to status/doing", "status/todo (1)", "status/doing (1)", "> #1 Login fails")
+	if got := strings.Join(repo.Issue(1).Labels, ","); got != "bug,status/doing" {
+		t.Errorf("labels = %s, want bug,status/doing", got)
+	}
+
+	// Moving an issue without a status adds the label.
+	f.Keys("h", "h", ">")
+	f.assertScreen("Moved #4 to status/todo", "status/todo (2)")
+	if got := strings.Join(repo.Issue(4).Labels, ","); got != "status/todo" {
+		t.Errorf("labels = %s, want status/todo", got)
+	}
+
+	f.Keys("enter")
+	f.assertScreen("Typo in docs #4")
+
+	ops := strings.Join(srv.Operations(), ",")
+	if add, remove := strings.Index(ops, "AddLabelsToLabelable"), strings.Index(ops, "RemoveLabelsFromLabelable"); add < 0 || remove < add {
+		t.Errorf("expected the new label to be added before the old one is removed: %s", ops)
+	}
+}
+
+func TestFlow_BoardOnShortTerminal(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	srv.Store().Repo("octo", "hello").AddLabel("status/todo", "ededed")
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Status", States: []string{"OPEN"}, Board: &Board{Labels: []string{"status/todo"}}},
+	})
+	f.Keys("R")
+	for _, height := range []int{3, 1} {
+		f.Resize(60, height)
+		_ = f.app.View()
+	}
+	f.Resize(60, 20)
+	f.assertScreen("status/todo (0)")
+}
+
+func TestFlow_ProjectBoardMovesIssues(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	project := srv.Store().AddProject("octo", "Roadmap")
+	project.AddField("Status", "SINGLE_SELECT", "Todo", "In Progress", "Done")
+	project.AddItem(repo, 1, map[string]string{"Status": "Todo"})
+	project.AddItem(repo, 3, map[string]string{"Status": "Done"})
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Roadmap", Board: &Board{Project: 1, Field: "Status"}},
+	})
+	f.Keys("R")
+	f.assertScreen("Roadmap (2)", "Todo (1)", "In Progress (0)", "Done (1)", "> #1 Login fails")
+
+	f.Keys(">")
+	f.assertScreen("Moved #1 to In Progress", "Todo (0)", "In Progress (1)")
+	if got := project.Items[0].Values["Status"]; got != "In Progress" {
+		t.Errorf("status = %q, want In Progress", got)
+	}
+	f.Keys(">", ">")
+	f.assertScreen("Moved #1 to Done", "Done (2)")
+
+	ops := srv.Operations()
+	if n := strings.Count(strings.Join(ops, ","), "ListIssues"); n != 1 {
+		t.Errorf("ListIssues requests = %d, want only the initial dashboard load: %v", n, ops)
+	}
+}
+
+func TestFlow_ProjectBoardOpensAndCopiesCards(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	project := srv.Store().AddProject("octo", "Roadmap")
+	project.AddField("Status", "SINGLE_SELECT", "Todo", "Done")
+	project.AddItem(srv.Store().Repo("octo", "hello"), 2, map[string]string{"Status": "Todo"})
+
+	f := newFlow(t, srv, 10)
+	var copied, opened string
+	f.app.SetClipboard(func(text string) error {
+		copied = text
+		return nil
+	})
+	f.app.SetBrowser(ui.BackgroundBrowser(func(url string) error {
+		opened = url
+		return nil
+	}))
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Roadmap", Board: &Board{Project: 1, Field: "Status"}},
+	})
+	f.Keys("R")
+	f.assertScreen("Todo (1)", "> #2 Dark mode")
+
+	f.Keys("o")
+	f.assertScreen("Opened in the browser")
+	if opened != "https://github.com/octo/hello/issues/2" {
+		t.Errorf("opened %q, want the card's issue URL", opened)
+	}
+
+	f.Keys("u")
+	f.assertScreen("Copy #2 as", "1 https://github.com/octo/hello/issues/2")
+	f.Keys("4")
+	f.assertScreen("Copied markdown link")
+	if want := "[Dark mode (#2)](https://github.com/octo/hello/issues/2)"; copied != want {
+		t.Errorf("copied %q, want %q", copied, want)
+	}
+}
+
+func TestFlow_ProjectFieldsShowFilterAndEdit(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	project := srv.Store().AddProject("octo", "Roadmap")
+	project.AddField("Priority", "SINGLE_SELECT", "P0", "P1", "P2")
+	project.AddField("Estimate", "NUMBER")
+	item := project.AddItem(repo, 1, map[string]string{"Priority": "P1", "Estimate": "3"})
+	project.AddItem(repo, 3, map[string]string{"Priority": "P2"})
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Urgent", States: []string{"OPEN"}, ProjectFields: map[string]string{"priority": "p1"}, Table: true, Columns: []string{"number", "title", "project:Priority", "project:Estimate"}},
+	})
+	f.Keys("R")
+	f.assertScreen("Urgent (5) · 1 with priority: p1", "Priority Estimate", "#1 Login fails P1 3")
+	if strings.Contains(f.Screen(), "Crash on save") {
+		t.Errorf("issue without P1 shown:\n%s", f.Screen())
+	}
+
+	f.Keys("enter")
+	f.assertScreen("Project: Roadmap Priority: P1 Estimate: 3")
+
+	f.Keys("P")
+	f.assertScreen("Project fields of #1", "1 Priority: P1", "2 Estimate: 3")
+	f.Keys("1")
+	f.assertScreen("✓ P1", "4 Clear")
+	f.Keys("1")
+	f.assertScreen("Set Priority to P0", "Priority: P0")
+	if got := item.Values["Priority"]; got != "P0" {
+		t.Errorf("priority = %q, want P0", got)
+	}
+
+	f.Keys("P", "2")
+	f.assertScreen("Estimate (number)", "enter: save")
+	f.Keys("ctrl+u")
+	f.Keys("soon")
+	f.Keys("enter")
+	f.assertScreen("must be a number")
+	f.Keys("P", "2", "ctrl+u")
+	f.Keys("5")
+	f.Keys("enter")
+	f.assertScreen("Set Estimate to 5", "Estimate: 5")
+	if got := item.Values["Estimate"]; got != "5" {
+		t.Errorf("estimate = %q, want 5", got)
+	}
+}
+
+func TestFlow_ProjectFieldsWithoutScope(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	project := srv.Store().AddProject("octo", "Roadmap")
+	project.AddField("Priority", "SINGLE_SELECT", "P0", "P1")
+	project.AddItem(srv.Store().Repo("octo", "hello"), 1, map[string]string{"Priority": "P1"})
+	srv.Fail("IssueProjects", fakegithub.Failure{
+		Type:    "INSUFFICIENT_SCOPES",
+		Message: "Your token has not been granted the required scopes to execute this query.",
+	})
+
+	f := newFlow(t, srv, 10)
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Planned", States: []string{"OPEN"}, Table: true, Columns: []string{"number", "title", "project:Priority"}},
+	})
+	f.Keys("R")
+	f.assertScreen("Showing 5 issues", "#1 Login fails")
+	if strings.Contains(f.Screen(), "P1") || strings.Contains(f.Screen(), "scopes") {
+		t.Errorf("projects shown or error reported without the scope:\n%s", f.Screen())
+	}
+
+	f.Keys("enter")
+	f.assertScreen("Body of Login fails")
+	if strings.Contains(f.Screen(), "Project:") || strings.Contains(f.Screen(), "scopes") {
+		t.Errorf("projects shown or error reported without the scope:\n%s", f.Screen())
+	}
+}
+
+func TestFlow_SubIssuesAndTasks(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.Issue(3).State = "CLOSED"
+	repo.AddSubIssue(1, repo.Issue(2))
+	repo.AddSubIssue(1, repo.Issue(3))
+	repo.Issue(2).Body = "- [x] Design\n- [ ] Build"
+
+	f := newFlow(t, srv, 10)
+	f.assertScreen("Login fails", "1/2 done")
+
+	f.Keys("enter")
+	f.assertScreen("Sub-issues: 1/2 done", "├─ ○ #2 Dark mode", "└─ ✓ #3 Crash on save")
+
+	f.Keys("I")
+	f.assertScreen("Hierarchy of #1", "1 #2 Dark mode")
+	f.Keys("1")
+	if f.app.ViewStackLen() != 3 {
+		t.Fatalf("view stack = %d, want the sub-issue pushed", f.app.ViewStackLen())
+	}
+	f.assertScreen("Parent: ○ #1 Login fails", "Tasks: 1/2")
+
+	f.Keys("x")
+	f.assertScreen("Tasks of #2 (1/2)", "✓ Design", "2 Build")
+	f.Keys("2")
+	f.assertScreen("Checked Build", "Tasks: 2/2")
+	if got := repo.Issue(2).Body; got != "- [x] Design\n- [x] Build" {
+		t.Errorf("body = %q", got)
+	}
+
+	repo.Issue(2).Body = "- [x] Design\n- [x] Build\n- [ ] Ship"
+	f.Keys("x", "1")
+	f.assertScreen("#2 was edited on GitHub", "Tasks: 2/3")
+	if got := repo.Issue(2).Body; got != "- [x] Design\n- [x] Build\n- [ ] Ship" {
+		t.Errorf("conflicting toggle overwrote the body: %q", got)
+	}
+}
+
+func TestFlow_IssueTypesShowFilterAndSet(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	srv.Store().AddIssueType("octo", "Bug", "RED", "Something isn't working")
+	srv.Store().AddIssueType("octo", "Feature", "BLUE", "")
+	repo.Issue(1).Type = "Bug"
+	repo.Issue(3).Type = "Feature"
+
+	f := newFlow(t, srv, 10)
+	f.assertScreen("◆ Bug Login fails", "◆ Feature Crash on save")
+
+	f.app.CurrentView().(*DashboardView).SetSections([]Section{
+		{Title: "Bugs", States: []string{"OPEN"}, Types: []string{"bug"}, GroupBy: "type"},
+	})
+	f.Keys("R")
+	f.assertScreen("Bugs (5) · 1 with type: bug · by type", "▾ Bug (1)")
+	if strings.Contains(f.Screen(), "Crash on save") {
+		t.Errorf("issue of another type shown:\n%s", f.Screen())
+	}
+
+	f.Keys("enter")
+	f.assertScreen("Login fails #1 ◆ Bug")
+	f.Keys("T")
+	f.assertScreen("Type of #1", "✓ Bug · Something isn't working", "3 Clear")
+	f.Keys("2")
+	f.assertScreen("Set type to Feature", "#1 ◆ Feature")
+	if got := repo.Issue(1).Type; got != "Feature" {
+		t.Errorf("type = %q, want Feature", got)
+	}
+	f.Keys("T", "3")
+	f.assertScreen("Removed the type")
+	if got := repo.Issue(1).Type; got != "" {
+		t.Errorf("type = %q, want none", got)
+	}
+}
+
+func TestFlow_OpenInBrowserAndCopyReferences(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	srv.Store().Repo("octo", "hello").Issue(1).Title = "Login [fails]"
+
+	f := newFlow(t, srv, 10)
+	var copied, opened string
+	f.app.SetClipboard(func(text string) error {
+		copied = text
+		return nil
+	})
+
+	f.Keys("o")
+	f.assertScreen("opening the browser")
+
+	f.app.SetBrowser(ui.BackgroundBrowser(func(url string) error {
+		opened = url
+		return nil
+	}))
+	f.Keys("o")
+	f.assertScreen("Opened in the browser")
+	if opened != "https://github.com/octo/hello/issues/1" {
+		t.Errorf("opened %q, want the issue URL from the list", opened)
+	}
+
+	f.Keys("u")
+	f.assertScreen("Copy #1 as", "1 https://github.com/octo/hello/issues/1", "2 #1", "3 octo/hello#1")
+	f.Keys("3")
+	f.assertScreen("Copied reference")
+	if copied != "octo/hello#1" {
+		t.Errorf("copied %q, want the qualified reference", copied)
+	}
+
+	f.Keys("enter", "u", "4")
+	f.assertScreen("Copied markdown link")
+	if want := `[Login \[fails\] (#1)](https://github.com/octo/hello/issues/1)`; copied != want {
+		t.Errorf("copied %q, want %q", copied, want)
+	}
+	f.Keys("u", "2")
+	if copied != "#1" {
+		t.Errorf("copied %q, want the number", copied)
+	}
+
+	f.app.SetBrowser(ui.BackgroundBrowser(func(string) error { return errors.New("xdg-open not found") }))
+	f.Keys("o")
+	f.assertScreen("opening the browser")
+}
+
+func TestFlow_FollowLinks(t *testing.T) {
+	srv := seedFlowRepo()
+	t.Cleanup(srv.Close)
+	repo := srv.Store().Repo("octo", "hello")
+	repo.Issue(1).Body = "Blocked by #2 and octo/other#4, see https://example.com/spec"
+	repo.AddComment(1, fakegithub.Comment{Author: "bob", Body: "@alice fixed in #2 by a1b2c3d"})
+
+	f := newFlow(t, srv, 10)
+	var opened string
+	f.app.SetBrowser(ui.BackgroundBrowser(func(url string) error {
+		opened = url
+		return nil
+	}))
+
+	f.Keys("enter", "f")
+	f.assertScreen("Links in #1", "1 #2", "2 octo/other#4 ↗", "3 https://example.com/spec ↗", "4 @alice ↗", "5 a1b2c3d ↗")
+	f.Keys("2")
+	f.assertScreen("Opened in the browser")
+	if opened != "https://github.com/octo/other/issues/4" {
+		t.Errorf("opened %q, want the other repository's issue", opened)
+	}
+	f.Keys("f", "5")
+	if opened != "https://github.com/octo/hello/commit/a1b2c3d" {
+		t.Errorf("opened %q, want the commit", opened)
+	}
+
+	f.Keys("f", "1")
+	if f.app.ViewStackLen() != 3 {
+		t.Fatalf("view stack = %d, want #2 pushed", f.app.ViewStackLen())
+	}
+	f.assertScreen("Dark mode #2")
+	f.Keys("f")
+	f.assertScreen("#2 has no links")
+}
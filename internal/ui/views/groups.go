@@ -0,0 +1,244 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// grouping buckets the loaded issues by a field. Each issue goes in one
+// group: the first of its labels or assignees that matches.
+type grouping struct {
+	key string
+	// value returns the issue's group, or "" for the group of issues
+	// without one.
+	value func(issue data.Issue, prefix string) string
+	// none names the group of issues without a value.
+	none func(prefix string) string
+	// order returns the groups' sort order; nil sorts them by name.
+	order func(a, b string) int
+}
+
+var groupings = []grouping{
+	{
+		key: "label",
+		value: func(i data.Issue, prefix string) string {
+			for _, l := range i.Labels {
+				if strings.HasPrefix(strings.ToLower(l.Name), strings.ToLower(prefix)) {
+					return l.Name
+				}
+			}
+			return ""
+		},
+		none: func(prefix string) string {
+			if prefix == "" {
+				return "No labels"
+			}
+			return fmt.Sprintf("No %s* label", prefix)
+		},
+	},
+	{
+		key:   "milestone",
+		value: func(i data.Issue, _ string) string { return i.Milestone },
+		none:  func(string) string { return "No milestone" },
+	},
+	{
+		key: "assignee",
+		value: func(i data.Issue, _ string) string {
+			if len(i.Assignees) == 0 {
+				return ""
+			}
+			return i.Assignees[0]
+		},
+		none: func(string) string { return "Unassigned" },
+	},
+	{
+		key:   "author",
+		value: func(i data.Issue, _ string) string { return i.Author },
+		none:  func(string) string { return "No author" },
+	},
+	{
+		key: "state",
+		value: func(i data.Issue, _ string) string {
+			if i.State == "" {
+				return ""
+			}
+			return i.State[:1] + strings.ToLower(i.State[1:])
+		},
+		none: func(string) string { return "No state" },
+		order: func(a, b string) int {
+			states := []string{"Open", "Closed"}
+			return cmp.Compare(slices.Index(states, a), slices.Index(states, b))
+		},
+	},
+	{
+		key:   "type",
+		value: func(i data.Issue, _ string) string { return i.Type.Name },
+		none:  func(string) string { return "No type" },
+	},
+}
+
+// parseGroupBy splits a group_by setting such as "label:area/*" into its
+// grouping and label prefix.
+func parseGroupBy(s string) (grouping, string, bool) {
+	key, prefix, _ := strings.Cut(s, ":")
+	prefix = strings.TrimSuffix(prefix, "*")
+	for _, g := range groupings {
+		if g.key == key && (prefix == "" || key == "label") {
+			return g, prefix, true
+		}
+	}
+	return grouping{}, "", false
+}
+
+// ValidGroupBy reports whether s names a grouping: "label", optionally
+// with a prefix as in "label:area/*", "milestone", "assignee", "author",
+// "state", or "type".
+func ValidGroupBy(s string) bool {
+	_, _, ok := parseGroupBy(s)
+	return ok
+}
+
+// issueGroup is a group of issues under a header.
+type issueGroup struct {
+	name   string
+	issues []data.Issue
+}
+
+// groupIssues buckets issues, keeping their order within each group. The
+// group of issues without a value comes last.
+func groupIssues(issues []data.Issue, groupBy string) []issueGroup {
+	g, prefix, ok := parseGroupBy(groupBy)
+	if !ok {
+		return nil
+	}
+	var groups []issueGroup
+	var none []data.Issue
+	for _, issue := range issues {
+		v := g.value(issue, prefix)
+		if v == "" {
+			none = append(none, issue)
+			continue
+		}
+		i := slices.IndexFunc(groups, func(grp issueGroup) bool { return grp.name == v })
+		if i < 0 {
+			groups = append(groups, issueGroup{name: v})
+			i = len(groups) - 1
+		}
+		groups[i].issues = append(groups[i].issues, issue)
+	}
+
+	order := g.order
+	if order == nil {
+		order = func(a, b string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(b)) }
+	}
+	slices.SortStableFunc(groups, func(a, b issueGroup) int { return order(a.name, b.name) })
+	if len(none) > 0 {
+		groups = append(groups, issueGroup{name: g.none(prefix), issues: none})
+	}
+	return groups
+}
+
+// groupItem is a group header in the dashboard list.
+type groupItem struct {
+	name      string
+	count     int
+	collapsed bool
+}
+
+func (g groupItem) FilterValue() string { return "" }
+
+// renderGroupHeader draws a group header, padded with blank lines to the
+// delegate's height.
+func renderGroupHeader(w io.Writer, styles ui.Styles, g groupItem, selected bool, height int) {
+	arrow := "▾"
+	if g.collapsed {
+		arrow = "▸"
+	}
+	cursor := "  "
+	style := styles.Header
+	if selected {
+		cursor = "> "
+		style = style.Foreground(lipgloss.Color("12"))
+	}
+	count := styles.HelpDesc.Render(fmt.Sprintf("(%d)", g.count))
+	_, _ = fmt.Fprint(w, cursor+style.Render(arrow+" "+g.name)+" "+count+strings.Repeat("\n", max(height-1, 0)))
+}
+
+// groupItems returns the list items for issues in the section's grouping:
+// each group's header, followed by its issues unless it is collapsed.
+func (d *DashboardView) groupItems(issues []data.Issue) []list.Item {
+	sec := d.sections[d.section]
+	var items []list.Item
+	for _, g := range groupIssues(issues, sec.GroupBy) {
+		collapsed := sec.collapsed[g.name]
+		items = append(items, groupItem{name: g.name, count: len(g.issues), collapsed: collapsed})
+		if collapsed {
+			continue
+		}
+		for _, issue := range g.issues {
+			items = append(items, issueItem{issue: issue})
+		}
+	}
+	return items
+}
+
+// groupOptions returns the groupings b cycles through: none, then the
+// label groupings configured in any section, then the other fields.
+func (d *DashboardView) groupOptions() []string {
+	options := append([]string{""}, d.labelGroupings...)
+	for _, g := range groupings {
+		options = append(options, g.key)
+	}
+	return options
+}
+
+// cycleGrouping switches the current section to the next grouping,
+// expanding every group.
+func (d *DashboardView) cycleGrouping() tea.Cmd {
+	sec := &d.sections[d.section]
+	options := d.groupOptions()
+	i := slices.Index(options, sec.GroupBy)
+	sec.GroupBy = options[(i+1)%len(options)]
+	sec.collapsed = nil
+	d.updateTitle()
+	status := "Not grouped"
+	if sec.GroupBy != "" {
+		status = "Grouped by " + sec.GroupBy
+	}
+	return tea.Batch(d.setItems(), ui.StatusInfo(status))
+}
+
+// toggleGroup collapses or expands the group of the selected header or
+// issue, leaving its header selected.
+func (d *DashboardView) toggleGroup() tea.Cmd {
+	items := d.list.Items()
+	if len(items) == 0 {
+		return nil
+	}
+	index := d.list.Index()
+	for ; index >= 0; index-- {
+		if _, ok := items[index].(groupItem); ok {
+			break
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+	g := items[index].(groupItem)
+	sec := &d.sections[d.section]
+	if sec.collapsed == nil {
+		sec.collapsed = map[string]bool{}
+	}
+	sec.collapsed[g.name] = !g.collapsed
+	d.list.Select(index)
+	return d.setItems()
+}
@@ -0,0 +1,64 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cboone/gh-problemas/internal/data"
+)
+
+func groupSummary(groups []issueGroup) string {
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		numbers := make([]string, len(g.issues))
+		for n, issue := range g.issues {
+			numbers[n] = fmt.Sprint(issue.Number)
+		}
+		parts[i] = g.name + "=" + strings.Join(numbers, ",")
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestGroupIssues(t *testing.T) {
+	label := func(names ...string) []data.Label {
+		labels := make([]data.Label, len(names))
+		for i, n := range names {
+			labels[i] = data.Label{Name: n}
+		}
+		return labels
+	}
+	issues := []data.Issue{
+		{Number: 1, State: "OPEN", Labels: label("bug", "area/ui"), Milestone: "v2", Assignees: []string{"bob", "alice"}},
+		{Number: 2, State: "CLOSED", Labels: label("Area/API"), Milestone: "v1"},
+		{Number: 3, State: "OPEN", Labels: label("area/ui"), Assignees: []string{"alice"}},
+		{Number: 4, State: "OPEN"},
+	}
+	tests := []struct {
+		groupBy string
+		want    string
+	}{
+		{"label", "Area/API=2 area/ui=3 bug=1 No labels=4"},
+		{"label:area/*", "Area/API=2 area/ui=1,3 No area/* label=4"},
+		{"milestone", "v1=2 v2=1 No milestone=3,4"},
+		{"assignee", "alice=3 bob=1 Unassigned=2,4"},
+		{"state", "Open=1,3,4 Closed=2"},
+		{"votes", ""},
+	}
+	for _, tt := range tests {
+		if got := groupSummary(groupIssues(issues, tt.groupBy)); got != tt.want {
+			t.Errorf("groupIssues(%q) = %q, want %q", tt.groupBy, got, tt.want)
+		}
+	}
+}
+
+func TestValidGroupBy(t *testing.T) {
+	for s, want := range map[string]bool{
+		"label": true, "label:area/*": true, "author": true, "state": true,
+		"milestone:v1": false, "labels": false, "": false,
+	} {
+		if got := ValidGroupBy(s); got != want {
+			t.Errorf("ValidGroupBy(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// progressText describes sub-issue progress as "3/7 done", or "" without
+// sub-issues.
+func progressText(p data.SubIssueProgress) string {
+	if p.Total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d done", p.Completed, p.Total)
+}
+
+// refName names an issue in a hierarchy, qualified by its repository when
+// that is not repo.
+func refName(ref data.IssueRef, repo string) string {
+	if ref.Repo == "" || strings.EqualFold(ref.Repo, repo) {
+		return fmt.Sprintf("#%d", ref.Number)
+	}
+	return fmt.Sprintf("%s#%d", ref.Repo, ref.Number)
+}
+
+// stateMark is ✓ for closed issues and ○ for open ones.
+func stateMark(state string) string {
+	if state == "CLOSED" {
+		return "✓"
+	}
+	return "○"
+}
+
+// writeHierarchy writes the issue's parent and a tree of its sub-issues,
+// naming issues outside repo with their repository.
+func writeHierarchy(sb *strings.Builder, issue data.Issue, repo string) {
+	if p := issue.Parent; p != nil {
+		sb.WriteString(metaStyle.Render(fmt.Sprintf("Parent: %s %s %s", stateMark(p.State), refName(*p, repo), p.Title)))
+		sb.WriteString("\n")
+	}
+	if issue.SubIssueProgress.Total == 0 && len(issue.SubIssues) == 0 {
+		return
+	}
+	sb.WriteString(metaStyle.Render("Sub-issues: " + progressText(issue.SubIssueProgress)))
+	sb.WriteString("\n")
+	for i, sub := range issue.SubIssues {
+		branch := "├─"
+		if i == len(issue.SubIssues)-1 {
+			branch = "└─"
+		}
+		line := fmt.Sprintf("%s %s %s %s", branch, stateMark(sub.State), refName(sub, repo), sub.Title)
+		if p := progressText(sub.SubIssueProgress); p != "" {
+			line += "  " + p
+		}
+		sb.WriteString(metaStyle.Render(line))
+		sb.WriteString("\n")
+	}
+	if more := issue.SubIssueProgress.Total - len(issue.SubIssues); more > 0 && len(issue.SubIssues) > 0 {
+		sb.WriteString(metaStyle.Render(fmt.Sprintf("   and %d more", more)))
+		sb.WriteString("\n")
+	}
+}
+
+// openHierarchyPicker lists the parent and sub-issues to open.
+func (d *DetailView) openHierarchyPicker() tea.Cmd {
+	var refs []data.IssueRef
+	var items []components.PickerItem
+	repo := d.issueClient.Repo()
+	if p := d.issue.Parent; p != nil {
+		refs = append(refs, *p)
+		items = append(items, components.PickerItem{Label: fmt.Sprintf("↑ %s %s (parent)", refName(*p, repo), p.Title), Marked: p.State == "CLOSED"})
+	}
+	for _, sub := range d.issue.SubIssues {
+		refs = append(refs, sub)
+		items = append(items, components.PickerItem{Label: fmt.Sprintf("%s %s", refName(sub, repo), sub.Title), Marked: sub.State == "CLOSED"})
+	}
+	if len(refs) == 0 {
+		return ui.StatusInfo(fmt.Sprintf("#%d has no parent or sub-issues", d.issue.Number))
+	}
+	d.openPicker(fmt.Sprintf("Hierarchy of #%d", d.issue.Number), items, "1-9/enter: open", func(i int) tea.Cmd {
+		d.picker = nil
+		ref := refs[i]
+		if !strings.EqualFold(ref.Repo, repo) {
+			return ui.StatusInfo(refName(ref, repo) + " is in another repository")
+		}
+		return func() tea.Msg { return ui.NavigateToDetailMsg{IssueNumber: ref.Number} }
+	})
+	return nil
+}
+
+// openTaskPicker lists the body's task list items, checked as they are,
+// to toggle one.
+func (d *DetailView) openTaskPicker() tea.Cmd {
+	body := d.issue.Body
+	tasks := data.ParseTasks(body)
+	if len(tasks) == 0 {
+		return ui.StatusInfo(fmt.Sprintf("#%d has no task list", d.issue.Number))
+	}
+	items := make([]components.PickerItem, len(tasks))
+	for i, t := range tasks {
+		items[i] = components.PickerItem{Label: strings.Repeat("  ", t.Depth) + t.Text, Marked: t.Checked}
+	}
+	done, total := data.TaskProgress(body)
+	d.openPicker(fmt.Sprintf("Tasks of #%d (%d/%d)", d.issue.Number, done, total), items, "1-9/enter: toggle", func(i int) tea.Cmd {
+		d.picker = nil
+		return d.toggleTask(body, tasks[i])
+	})
+	return nil
+}
+
+// toggleTask checks or unchecks task by rewriting the body, unless the
+// body was changed on GitHub since it was loaded.
+func (d *DetailView) toggleTask(body string, task data.Task) tea.Cmd {
+	newBody, err := data.ToggleTask(body, task)
+	if err != nil {
+		return ui.StatusInfo(err.Error())
+	}
+	status := "Checked " + task.Text
+	if task.Checked {
+		status = "Unchecked " + task.Text
+	}
+	client := d.issueClient
+	number := d.issueNumber
+	id := d.requestID
+	return tea.Batch(ui.StatusLoading("Saving task..."), func() tea.Msg {
+		saved, err := client.EditBody(number, body, newBody)
+		return ui.IssueBodyUpdatedMsg{RequestID: id, Body: saved, Status: status, Err: err}
+	})
+}
+
+// applyBody shows the saved body, or on a conflict the body now on
+// GitHub.
+func (d *DetailView) applyBody(msg ui.IssueBodyUpdatedMsg) tea.Cmd {
+	var apiErr *data.Error
+	conflict := errors.As(msg.Err, &apiErr) && apiErr.Kind == data.ErrConflict
+	if msg.Err != nil && !conflict {
+		return nil
+	}
+	d.issue.Body = msg.Body
+	d.renderContent()
+	if conflict {
+		return nil
+	}
+	return ui.StatusInfo(msg.Status)
+}
@@ -0,0 +1,106 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	"github.com/cboone/gh-problemas/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderTypeBadge draws an issue type in its color, set apart from the
+// label chips by a diamond instead of a background, or "" without a type.
+func renderTypeBadge(t data.IssueType, noColor bool) string {
+	if t.Name == "" {
+		return ""
+	}
+	badge := "◆ " + t.Name
+	if noColor {
+		return badge
+	}
+	return lipgloss.NewStyle().Bold(true).Foreground(utils.HexToColor(t.Color)).Render(badge)
+}
+
+// matchesTypes reports whether the issue has one of types, compared without
+// case. Any issue matches when types is empty.
+func matchesTypes(issue data.Issue, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(types, func(t string) bool { return strings.EqualFold(t, issue.Type.Name) })
+}
+
+// SetIssueTypeClient enables setting the issue's type.
+func (d *DetailView) SetIssueTypeClient(client *data.IssueTypeClient) {
+	d.issueTypeClient = client
+}
+
+// loadIssueTypes fetches the repository's issue types for the type picker.
+func (d *DetailView) loadIssueTypes() tea.Cmd {
+	client := d.issueTypeClient
+	id := d.requestID
+	return tea.Batch(ui.StatusLoading("Loading issue types..."), func() tea.Msg {
+		types, err := client.List()
+		return ui.IssueTypesLoadedMsg{RequestID: id, Types: types, Err: err}
+	})
+}
+
+// openTypePicker offers the issue types with the current one checked, and
+// "Clear" when the issue has one.
+func (d *DetailView) openTypePicker(msg ui.IssueTypesLoadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	if len(msg.Types) == 0 {
+		return ui.StatusInfo("This repository has no issue types")
+	}
+	types := msg.Types
+	items := make([]components.PickerItem, len(types))
+	for i, t := range types {
+		label := t.Name
+		if t.Description != "" {
+			label += " · " + t.Description
+		}
+		items[i] = components.PickerItem{Label: label, Marked: t.ID == d.issue.Type.ID}
+	}
+	if d.issue.Type.Name != "" {
+		items = append(items, components.PickerItem{Label: "Clear"})
+	}
+	d.openPicker(fmt.Sprintf("Type of #%d", d.issue.Number), items, "1-9/enter: set", func(i int) tea.Cmd {
+		d.picker = nil
+		if i == len(types) {
+			return d.setIssueType(data.IssueType{})
+		}
+		return d.setIssueType(types[i])
+	})
+	return ui.StatusInfo("")
+}
+
+// setIssueType sets the issue's type to t, or removes it when t is zero.
+func (d *DetailView) setIssueType(t data.IssueType) tea.Cmd {
+	client := d.issueTypeClient
+	issueID := d.issue.ID
+	id := d.requestID
+	return tea.Batch(ui.StatusLoading("Setting type..."), func() tea.Msg {
+		set, err := client.SetType(issueID, t.ID)
+		return ui.IssueTypeUpdatedMsg{RequestID: id, Type: set, Err: err}
+	})
+}
+
+// applyIssueType shows the issue's new type.
+func (d *DetailView) applyIssueType(msg ui.IssueTypeUpdatedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	d.issue.Type = msg.Type
+	d.renderContent()
+	if msg.Type.Name == "" {
+		return ui.StatusInfo("Removed the type")
+	}
+	return ui.StatusInfo("Set type to " + msg.Type.Name)
+}
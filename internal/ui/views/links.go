@@ -0,0 +1,146 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// csiPattern matches the styling sequences in rendered markdown, which
+// references are never split across.
+var csiPattern = regexp.MustCompile(`\x1b\[[0-9;:?]*[ -/]*[@-~]`)
+
+// SetHyperlinks makes the references in bodies and comments OSC 8
+// hyperlinks, for terminals that support them.
+func (d *DetailView) SetHyperlinks(on bool) {
+	d.hyperlinks = on
+	clear(d.markdown)
+}
+
+// issueBase returns the scheme and host of an issue URL, such as
+// https://github.com.
+func issueBase(issueURL string) string {
+	u, err := url.Parse(issueURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// linkReferences makes each reference found in body an OSC 8 hyperlink
+// where it appears in rendered, body's markdown as rendered for the
+// terminal. base and repo locate the issue the body belongs to.
+func linkReferences(rendered, body, base, repo string) string {
+	refs := data.ParseReferences(body)
+	if len(refs) == 0 {
+		return rendered
+	}
+	links := map[string]string{}
+	texts := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if link := r.Link(base, repo); link != "" {
+			links[r.Text] = link
+			texts = append(texts, regexp.QuoteMeta(r.Text))
+		}
+	}
+	// Longer texts first, so that owner/repo#1 wins over #1.
+	slices.SortFunc(texts, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	pattern := regexp.MustCompile(strings.Join(texts, "|"))
+
+	var sb strings.Builder
+	last := 0
+	link := func(plain string) {
+		at := 0
+		for _, m := range pattern.FindAllStringIndex(plain, -1) {
+			if !linkBoundary(plain, m[0], m[1]) {
+				continue
+			}
+			text := plain[m[0]:m[1]]
+			sb.WriteString(plain[at:m[0]])
+			sb.WriteString(ansi.SetHyperlink(links[text]) + text + ansi.ResetHyperlink())
+			at = m[1]
+		}
+		sb.WriteString(plain[at:])
+	}
+	for _, m := range csiPattern.FindAllStringIndex(rendered, -1) {
+		link(rendered[last:m[0]])
+		sb.WriteString(rendered[m[0]:m[1]])
+		last = m[1]
+	}
+	link(rendered[last:])
+	return sb.String()
+}
+
+// linkBoundary reports whether s[start:end] stands alone rather than being
+// part of a longer word, path, or reference.
+func linkBoundary(s string, start, end int) bool {
+	word := func(b byte) bool {
+		return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+	}
+	if start > 0 && (word(s[start-1]) || strings.IndexByte("/#@.-", s[start-1]) >= 0) {
+		return false
+	}
+	return end == len(s) || !word(s[end])
+}
+
+// sameRepoIssue reports whether ref is an issue of repo, which is opened in
+// a new detail view rather than the browser.
+func sameRepoIssue(ref data.Reference, repo string) bool {
+	return ref.Kind == data.RefIssue && (ref.Repo == "" || strings.EqualFold(ref.Repo, repo))
+}
+
+// openLinksPicker lists the references in the body and comments to follow.
+// Issues of the same repository open in a new detail view, and anything
+// else in the browser, marked with ↗.
+func (d *DetailView) openLinksPicker() tea.Cmd {
+	var refs []data.Reference
+	seen := map[string]bool{}
+	bodies := []string{d.issue.Body}
+	for _, c := range d.comments {
+		bodies = append(bodies, c.Body)
+	}
+	for _, body := range bodies {
+		for _, r := range data.ParseReferences(body) {
+			if !seen[r.Text] {
+				seen[r.Text] = true
+				refs = append(refs, r)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return ui.StatusInfo(fmt.Sprintf("#%d has no links", d.issue.Number))
+	}
+
+	repo := d.issueClient.Repo()
+	base := issueBase(d.issue.URL)
+	if base == "" {
+		base = "https://github.com"
+	}
+	items := make([]components.PickerItem, len(refs))
+	for i, r := range refs {
+		label := r.Text
+		if !sameRepoIssue(r, repo) {
+			label += " ↗"
+		}
+		items[i] = components.PickerItem{Label: label}
+	}
+	d.openPicker(fmt.Sprintf("Links in #%d", d.issue.Number), items, "1-9/enter: follow", func(i int) tea.Cmd {
+		d.picker = nil
+		ref := refs[i]
+		if sameRepoIssue(ref, repo) {
+			return func() tea.Msg { return ui.NavigateToDetailMsg{IssueNumber: ref.Number} }
+		}
+		link := ref.Link(base, repo)
+		return func() tea.Msg { return ui.OpenURLMsg{URL: link} }
+	})
+	return nil
+}
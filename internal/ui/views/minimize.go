@@ -0,0 +1,82 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hiddenLabel says why c was minimized, such as "hidden as off-topic".
+func hiddenLabel(c data.Comment) string {
+	if c.MinimizedReason == "" {
+		return "hidden"
+	}
+	return "hidden as " + strings.ToLower(c.MinimizedReason)
+}
+
+// reasonLabel turns a classifier such as "OFF_TOPIC" into the wording
+// GitHub uses for it, "off-topic".
+func reasonLabel(classifier string) string {
+	return strings.ToLower(strings.ReplaceAll(classifier, "_", "-"))
+}
+
+// toggleMinimized shows the focused comment again if it is minimized, or
+// asks for a reason to hide it.
+func (d *DetailView) toggleMinimized() tea.Cmd {
+	c, ok := d.focusedComment()
+	if !ok || !c.ViewerCanMinimize {
+		return ui.StatusInfo("You can't hide this comment")
+	}
+	if c.IsMinimized {
+		return d.minimize(c.ID, "")
+	}
+
+	items := make([]components.PickerItem, len(data.MinimizeReasons))
+	for i, classifier := range data.MinimizeReasons {
+		items[i] = components.PickerItem{Label: reasonLabel(classifier)}
+	}
+	d.openPicker("Hide comment by "+c.Author+" as", items, "1-6/enter: choose", func(i int) tea.Cmd {
+		d.picker = nil
+		return d.minimize(c.ID, data.MinimizeReasons[i])
+	})
+	return nil
+}
+
+// minimize hides the comment with ID commentID for the reason classifier,
+// or shows it again if classifier is empty.
+func (d *DetailView) minimize(commentID, classifier string) tea.Cmd {
+	client := d.commentClient
+	id := d.requestID
+	if classifier == "" {
+		return tea.Batch(ui.StatusLoading("Showing comment..."), func() tea.Msg {
+			c, err := client.Unminimize(commentID)
+			return ui.CommentMinimizedMsg{RequestID: id, Comment: c, Err: err}
+		})
+	}
+	return tea.Batch(ui.StatusLoading("Hiding comment..."), func() tea.Msg {
+		c, err := client.Minimize(commentID, classifier)
+		return ui.CommentMinimizedMsg{RequestID: id, Comment: c, Err: err}
+	})
+}
+
+// applyMinimizedComment replaces the comment and resets its collapsed state,
+// so that a hidden comment folds away and a shown one opens.
+func (d *DetailView) applyMinimizedComment(msg ui.CommentMinimizedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	for i := range d.comments {
+		if d.comments[i].ID == msg.Comment.ID {
+			d.comments[i] = msg.Comment
+		}
+	}
+	delete(d.collapsed, msg.Comment.ID)
+	d.setFocus(d.focus)
+	if msg.Comment.IsMinimized {
+		return ui.StatusInfo("Comment " + hiddenLabel(msg.Comment))
+	}
+	return ui.StatusInfo("Comment shown")
+}
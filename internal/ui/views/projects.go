@@ -0,0 +1,300 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// projectColumnPrefix starts the key of a table column showing a project
+// field, as in "project:Priority".
+const projectColumnPrefix = "project:"
+
+// projectColumn is the table column for the project field named field.
+func projectColumn(field string) tableColumn {
+	return tableColumn{
+		key: projectColumnPrefix + field, header: field, width: 12, priority: 8,
+		cell: func(i data.Issue) string {
+			v, _ := i.ProjectValue(field)
+			return v.Text
+		},
+		compare: func(a, b data.Issue) int { return compareProjectValues(a, b, field) },
+	}
+}
+
+// compareProjectValues orders issues by their value of field, numerically
+// for number fields, with issues without one first.
+func compareProjectValues(a, b data.Issue, field string) int {
+	va, _ := a.ProjectValue(field)
+	vb, _ := b.ProjectValue(field)
+	if va.DataType == "NUMBER" && vb.DataType == "NUMBER" {
+		na, _ := strconv.ParseFloat(va.Text, 64)
+		nb, _ := strconv.ParseFloat(vb.Text, 64)
+		return cmp.Compare(na, nb)
+	}
+	return cmp.Compare(strings.ToLower(va.Text), strings.ToLower(vb.Text))
+}
+
+// ValidTableColumn reports whether key names a table column: one of
+// TableColumnKeys, or "project:" followed by a project field's name.
+func ValidTableColumn(key string) bool {
+	if field, ok := strings.CutPrefix(key, projectColumnPrefix); ok {
+		return field != ""
+	}
+	return slices.Contains(TableColumnKeys(), key)
+}
+
+// usesProjects reports whether the section filters on or has columns of
+// project fields, which are loaded only for such sections.
+func (s Section) usesProjects() bool {
+	return len(s.ProjectFields) > 0 || slices.ContainsFunc(s.Columns, func(key string) bool {
+		return strings.HasPrefix(key, projectColumnPrefix)
+	})
+}
+
+// matchesProjectFields reports whether the issue has each of the project
+// field values in filters, compared without case.
+func matchesProjectFields(issue data.Issue, filters map[string]string) bool {
+	for field, want := range filters {
+		v, ok := issue.ProjectValue(field)
+		if !ok || !strings.EqualFold(v.Text, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterIssues keeps the loaded issues matching the section's project field
+// and type filters, which GitHub can't apply to a list.
+func (d *DashboardView) filterIssues(issues []data.Issue) []data.Issue {
+	sec := d.sections[d.section]
+	if len(sec.ProjectFields) == 0 && len(sec.Types) == 0 {
+		return issues
+	}
+	var out []data.Issue
+	for _, issue := range issues {
+		if matchesProjectFields(issue, sec.ProjectFields) && matchesTypes(issue, sec.Types) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// filterTitle describes the section's project field and type filters for
+// the list title, with the number of loaded issues that match.
+func (d *DashboardView) filterTitle() string {
+	sec := d.sections[d.section]
+	if len(sec.ProjectFields) == 0 && len(sec.Types) == 0 {
+		return ""
+	}
+	var parts []string
+	if len(sec.Types) > 0 {
+		parts = append(parts, "type: "+strings.Join(sec.Types, " or "))
+	}
+	for _, field := range slices.Sorted(maps.Keys(sec.ProjectFields)) {
+		parts = append(parts, field+": "+sec.ProjectFields[field])
+	}
+	return fmt.Sprintf(" · %d with %s", len(d.filterIssues(d.issues)), strings.Join(parts, ", "))
+}
+
+// writeProjectValues writes a line per project the issue is on, with the
+// values of the item's fields.
+func writeProjectValues(sb *strings.Builder, issue data.Issue) {
+	for _, p := range issue.Projects {
+		parts := []string{"Project: " + p.ProjectTitle}
+		for _, v := range p.Values {
+			parts = append(parts, fmt.Sprintf("%s: %s", v.FieldName, v.Text))
+		}
+		sb.WriteString(metaStyle.Render(strings.Join(parts, "  ")))
+		sb.WriteString("\n")
+	}
+}
+
+// SetProjectClient enables editing the values of the issue's project
+// fields.
+func (d *DetailView) SetProjectClient(client *data.ProjectClient) {
+	d.projectClient = client
+}
+
+// loadProjectFields fetches the fields of each project the issue is on,
+// for the field picker.
+func (d *DetailView) loadProjectFields() tea.Cmd {
+	if len(d.issue.Projects) == 0 {
+		return ui.StatusInfo(fmt.Sprintf("#%d is not on any project", d.issue.Number))
+	}
+	client := d.projectClient
+	projects := d.issue.Projects
+	id := d.requestID
+	return tea.Batch(ui.StatusLoading("Loading project fields..."), func() tea.Msg {
+		var fields []data.ProjectField
+		for _, p := range projects {
+			f, err := client.Fields(p.ProjectID)
+			if err != nil {
+				return ui.ProjectFieldsLoadedMsg{RequestID: id, Err: err}
+			}
+			fields = append(fields, f...)
+		}
+		return ui.ProjectFieldsLoadedMsg{RequestID: id, Fields: fields}
+	})
+}
+
+// projectItem returns the issue's item on the project with node ID
+// projectID.
+func (d *DetailView) projectItem(projectID string) (data.IssueProject, bool) {
+	for _, p := range d.issue.Projects {
+		if p.ProjectID == projectID {
+			return p, true
+		}
+	}
+	return data.IssueProject{}, false
+}
+
+// projectFieldValue returns the issue's value of field on its project.
+func (d *DetailView) projectFieldValue(field data.ProjectField) (data.ProjectValue, bool) {
+	p, _ := d.projectItem(field.ProjectID)
+	for _, v := range p.Values {
+		if v.FieldID == field.ID {
+			return v, true
+		}
+	}
+	return data.ProjectValue{}, false
+}
+
+// openFieldPicker shows the project fields with their current values. The
+// project is named when the issue is on more than one.
+func (d *DetailView) openFieldPicker(msg ui.ProjectFieldsLoadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	if len(msg.Fields) == 0 {
+		return ui.StatusInfo("The issue's projects have no fields that can be set")
+	}
+	fields := msg.Fields
+	items := make([]components.PickerItem, len(fields))
+	for i, f := range fields {
+		label := f.Name
+		if len(d.issue.Projects) > 1 {
+			label = f.ProjectTitle + " · " + label
+		}
+		value := "—"
+		if v, ok := d.projectFieldValue(f); ok {
+			value = v.Text
+		}
+		items[i] = components.PickerItem{Label: label + ": " + value}
+	}
+	d.openPicker(fmt.Sprintf("Project fields of #%d", d.issue.Number), items, "1-9/enter: edit", func(i int) tea.Cmd {
+		return d.editProjectField(fields[i])
+	})
+	return ui.StatusInfo("")
+}
+
+// editProjectField offers the options of a single-select or iteration
+// field, with the current one checked, or prompts for any other value.
+// Choosing "Clear" or saving an empty value clears the field.
+func (d *DetailView) editProjectField(field data.ProjectField) tea.Cmd {
+	d.picker = nil
+	current, set := d.projectFieldValue(field)
+	if field.DataType != "SINGLE_SELECT" && field.DataType != "ITERATION" {
+		input := textinput.New()
+		input.Prompt = "> "
+		input.SetValue(current.Text)
+		input.Placeholder = map[string]string{"NUMBER": "a number", "DATE": "YYYY-MM-DD", "TEXT": "text"}[field.DataType]
+		input.Width = max(min(d.width-8, 60), 10)
+		// A blinking cursor would keep a timer running for the prompt.
+		input.Cursor.SetMode(cursor.CursorStatic)
+		input.Focus()
+		d.fieldInput = &input
+		d.editField = field
+		return nil
+	}
+
+	items := make([]components.PickerItem, len(field.Options))
+	for i, o := range field.Options {
+		items[i] = components.PickerItem{Label: o.Name, Marked: set && o.ID == current.OptionID}
+	}
+	if set {
+		items = append(items, components.PickerItem{Label: "Clear"})
+	}
+	d.openPicker(field.Name, items, "1-9/enter: set", func(i int) tea.Cmd {
+		d.picker = nil
+		if i == len(field.Options) {
+			return d.setProjectField(field, "")
+		}
+		return d.setProjectField(field, field.Options[i].ID)
+	})
+	return nil
+}
+
+// updateFieldInput handles keys while a field value is being typed.
+func (d *DetailView) updateFieldInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		d.fieldInput = nil
+		return nil
+	case tea.KeyEnter:
+		value := strings.TrimSpace(d.fieldInput.Value())
+		d.fieldInput = nil
+		return d.setProjectField(d.editField, value)
+	}
+	input, cmd := d.fieldInput.Update(msg)
+	d.fieldInput = &input
+	return cmd
+}
+
+// fieldInputView renders the value prompt in a box like the picker's.
+func (d *DetailView) fieldInputView() string {
+	title := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s (%s)", d.editField.Name, strings.ToLower(d.editField.DataType)))
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1)
+	return box.Render(title + "\n" + d.fieldInput.View())
+}
+
+// setProjectField sets the issue's value of field, or clears it when value
+// is empty.
+func (d *DetailView) setProjectField(field data.ProjectField, value string) tea.Cmd {
+	p, ok := d.projectItem(field.ProjectID)
+	if !ok {
+		return nil
+	}
+	client := d.projectClient
+	id := d.requestID
+	return tea.Batch(ui.StatusLoading(fmt.Sprintf("Setting %s...", field.Name)), func() tea.Msg {
+		v, err := client.SetValue(field, p.ItemID, value)
+		return ui.ProjectFieldUpdatedMsg{RequestID: id, ItemID: p.ItemID, Value: v, Err: err}
+	})
+}
+
+// applyProjectField stores a set or cleared value on the issue's item.
+func (d *DetailView) applyProjectField(msg ui.ProjectFieldUpdatedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	for i := range d.issue.Projects {
+		p := &d.issue.Projects[i]
+		if p.ItemID != msg.ItemID {
+			continue
+		}
+		p.Values = slices.DeleteFunc(p.Values, func(v data.ProjectValue) bool { return v.FieldID == msg.Value.FieldID })
+		if msg.Value.Text != "" {
+			p.Values = append(p.Values, msg.Value)
+		}
+	}
+	d.renderContent()
+	if msg.Value.Text == "" {
+		return ui.StatusInfo("Cleared " + msg.Value.FieldName)
+	}
+	return ui.StatusInfo(fmt.Sprintf("Set %s to %s", msg.Value.FieldName, msg.Value.Text))
+}
@@ -0,0 +1,192 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var reactionNames = map[string]string{
+	"THUMBS_UP":   "+1",
+	"THUMBS_DOWN": "-1",
+	"LAUGH":       "laugh",
+	"HOORAY":      "hooray",
+	"CONFUSED":    "confused",
+	"HEART":       "heart",
+	"ROCKET":      "rocket",
+	"EYES":        "eyes",
+}
+
+// renderReactions renders reaction groups as emoji with counts, such as
+// "👍 3  🎉 1". The viewer's own reactions are highlighted, or bracketed
+// without color.
+func renderReactions(groups []data.ReactionGroup, noColor bool) string {
+	mine := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Background(lipgloss.Color("236"))
+	parts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		part := fmt.Sprintf("%s %d", data.ReactionEmoji(g.Content), g.Count)
+		if g.ViewerHasReacted {
+			if noColor {
+				part = "[" + part + "]"
+			} else {
+				part = mine.Render(part)
+			}
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// reactionTarget is the issue or comment the reaction picker acts on.
+type reactionTarget struct {
+	subjectID string
+	label     string
+	groups    []data.ReactionGroup
+}
+
+// reactionTarget returns the focused issue body or comment. Events have no
+// reactions.
+func (d *DetailView) reactionTarget() (reactionTarget, bool) {
+	b, ok := d.focusedBlock()
+	switch {
+	case !ok || b.kind == eventBlock:
+		return reactionTarget{}, false
+	case b.kind == commentBlock:
+		c := d.comments[b.index]
+		return reactionTarget{subjectID: c.ID, label: "comment by " + c.Author, groups: c.ReactionGroups}, true
+	}
+	return reactionTarget{subjectID: d.issue.ID, label: fmt.Sprintf("#%d", d.issue.Number), groups: d.issue.ReactionGroups}, true
+}
+
+// openReactionPicker shows the reactions for the focused block, with the
+// viewer's own checked.
+func (d *DetailView) openReactionPicker() tea.Cmd {
+	target, ok := d.reactionTarget()
+	if !ok {
+		return ui.StatusInfo("Events can't be reacted to")
+	}
+	items := make([]components.PickerItem, len(data.ReactionContents))
+	for i, content := range data.ReactionContents {
+		items[i] = components.PickerItem{
+			Label:  data.ReactionEmoji(content) + " " + reactionNames[content],
+			Marked: findReaction(target.groups, content).ViewerHasReacted,
+		}
+	}
+	d.openPicker("React to "+target.label, items, "1-8/enter: toggle", func(i int) tea.Cmd {
+		return d.toggleReaction(data.ReactionContents[i])
+	})
+	d.pickerTarget = target
+	return nil
+}
+
+// openPicker shows a picker over the thread and calls choose with the
+// index of the item the user picks.
+func (d *DetailView) openPicker(title string, items []components.PickerItem, hint string, choose func(i int) tea.Cmd) {
+	d.picker = components.NewPicker(title, items, d.styles.SelectedRow)
+	d.pickerHint = hint
+	d.pickerChoose = choose
+}
+
+// updatePicker handles keys while a picker is open.
+func (d *DetailView) updatePicker(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, d.keys.Back), key.Matches(msg, d.keys.Quit), key.Matches(msg, d.keys.React):
+		d.picker = nil
+	case key.Matches(msg, d.keys.Up):
+		d.picker.Up()
+	case key.Matches(msg, d.keys.Down):
+		d.picker.Down()
+	case key.Matches(msg, d.keys.Open):
+		return d.pickerChoose(d.picker.Cursor())
+	default:
+		if i, ok := d.picker.IndexForKey(msg.String()); ok {
+			return d.pickerChoose(i)
+		}
+	}
+	return nil
+}
+
+// toggleReaction closes the picker and adds or removes the viewer's
+// reaction on the picker's target.
+func (d *DetailView) toggleReaction(content string) tea.Cmd {
+	target := d.pickerTarget
+	d.picker = nil
+
+	group := findReaction(target.groups, content)
+	client := d.reactionClient
+	id := d.requestID
+	status := "Adding reaction..."
+	if group.ViewerHasReacted {
+		status = "Removing reaction..."
+	}
+	return tea.Batch(ui.StatusLoading(status), func() tea.Msg {
+		groups, err := client.Toggle(target.subjectID, group)
+		return ui.ReactionsUpdatedMsg{
+			RequestID: id,
+			SubjectID: target.subjectID,
+			Content:   content,
+			Removed:   group.ViewerHasReacted,
+			Groups:    groups,
+			Err:       err,
+		}
+	})
+}
+
+// applyReactions stores the updated groups on the issue or comment, and
+// the total they add up to.
+func (d *DetailView) applyReactions(msg ui.ReactionsUpdatedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+	total := 0
+	for _, g := range msg.Groups {
+		total += g.Count
+	}
+	switch {
+	case d.issue != nil && d.issue.ID == msg.SubjectID:
+		d.issue.ReactionGroups = msg.Groups
+		d.issue.ReactionCount = total
+	default:
+		for i := range d.comments {
+			if d.comments[i].ID == msg.SubjectID {
+				d.comments[i].ReactionGroups = msg.Groups
+				d.comments[i].Reactions = total
+			}
+		}
+	}
+	d.renderContent()
+
+	emoji := data.ReactionEmoji(msg.Content)
+	if msg.Removed {
+		return ui.StatusInfo("Removed " + emoji)
+	}
+	return ui.StatusInfo("Reacted with " + emoji)
+}
+
+// findReaction returns the group for content, or an empty one.
+func findReaction(groups []data.ReactionGroup, content string) data.ReactionGroup {
+	for _, g := range groups {
+		if g.Content == content {
+			return g
+		}
+	}
+	return data.ReactionGroup{Content: content}
+}
+
+// overlayBottom replaces the last lines of base, which is height lines
+// tall, with box.
+func overlayBottom(base, box string, height int) string {
+	lines := strings.Split(base, "\n")
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	boxLines := strings.Split(box, "\n")
+	keep := max(height-len(boxLines), 0)
+	return strings.Join(append(lines[:keep], boxLines...), "\n")
+}
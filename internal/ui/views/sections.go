@@ -0,0 +1,162 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// columns returns the section's table columns.
+func (s Section) columns() []string {
+	if len(s.Columns) == 0 {
+		return DefaultTableColumns
+	}
+	return s.Columns
+}
+
+// applyLayout sets up the list for the current section's layout and sizes
+// it to the space left by the tabs and the table header.
+func (d *DashboardView) applyLayout() {
+	sec := d.sections[d.section]
+	height := d.height
+	if len(d.sections) > 1 {
+		height--
+	}
+	if sec.Table {
+		d.list.SetDelegate(tableDelegate{styles: d.styles, columns: sec.columns()})
+		d.list.SetShowTitle(false)
+		d.list.SetShowStatusBar(false)
+		height -= lipgloss.Height(d.tableTitleView()) + 1
+	} else {
+		d.list.SetDelegate(issueDelegate{styles: d.styles})
+		d.list.SetShowTitle(true)
+		d.list.SetShowStatusBar(true)
+	}
+	d.list.SetSize(d.width, max(height, 0))
+}
+
+// tableTitleView renders the list title, which the list itself does not
+// show in table mode so that the header can sit between it and the rows.
+func (d *DashboardView) tableTitleView() string {
+	return d.list.Styles.TitleBar.Render(d.list.Styles.Title.Render(d.list.Title))
+}
+
+// tabsView renders the section titles, highlighting the current one.
+func (d *DashboardView) tabsView() string {
+	tabs := make([]string, len(d.sections))
+	for i, sec := range d.sections {
+		if i == d.section {
+			tabs[i] = d.styles.Header.Render(sec.Title)
+		} else {
+			tabs[i] = d.styles.HelpDesc.Render(sec.Title)
+		}
+	}
+	return " " + strings.Join(tabs, d.styles.HelpKey.Render(" │ "))
+}
+
+// setItems fills the list with the loaded issues, in the current section's
+// sort order and grouping, keeping the selected issue or header selected.
+func (d *DashboardView) setItems() tea.Cmd {
+	selected := d.list.SelectedItem()
+	issues := d.sortIssues(d.filterIssues(d.issues))
+	var items []list.Item
+	if d.sections[d.section].GroupBy != "" {
+		items = d.groupItems(issues)
+	} else {
+		items = make([]list.Item, len(issues))
+		for i, issue := range issues {
+			items[i] = issueItem{issue: issue}
+		}
+	}
+	cmd := d.list.SetItems(items)
+	if selected != nil {
+		for i, item := range items {
+			if sameItem(item, selected) {
+				d.list.Select(i)
+				break
+			}
+		}
+	}
+	return cmd
+}
+
+// sameItem reports whether a and b show the same issue or group.
+func sameItem(a, b list.Item) bool {
+	switch a := a.(type) {
+	case issueItem:
+		b, ok := b.(issueItem)
+		return ok && a.issue.Number == b.issue.Number
+	case groupItem:
+		b, ok := b.(groupItem)
+		return ok && a.name == b.name
+	}
+	return false
+}
+
+// switchSection shows section i, wrapping around, and loads its issues.
+func (d *DashboardView) switchSection(i int) tea.Cmd {
+	d.section = (i + len(d.sections)) % len(d.sections)
+	d.issues = nil
+	d.board = nil
+	d.list.ResetSelected()
+	d.applyLayout()
+	d.loading = true
+	d.errMsg = ""
+	title := d.sections[d.section].Title
+	spinCmd := d.spinner.Start(fmt.Sprintf("Loading %s...", title))
+	statusCmd := ui.StatusLoading(fmt.Sprintf("Loading %s...", title))
+	return tea.Batch(d.setItems(), spinCmd, statusCmd, d.fetchSection())
+}
+
+// toggleLayout switches the current section between the list and the
+// table.
+func (d *DashboardView) toggleLayout() tea.Cmd {
+	sec := &d.sections[d.section]
+	sec.Table = !sec.Table
+	d.applyLayout()
+	if sec.Table {
+		return ui.StatusInfo("Table layout")
+	}
+	return ui.StatusInfo("List layout")
+}
+
+// sortColumnKey maps the keys "1" to "9" to a visible column index, and
+// "0" to -1, for the loaded order.
+func sortColumnKey(k string) (int, bool) {
+	if len(k) != 1 || k[0] < '0' || k[0] > '9' {
+		return 0, false
+	}
+	return int(k[0]-'0') - 1, true
+}
+
+// sortByColumn sorts the loaded issues by the nth visible column, reversing
+// the order when it is already sorted by it. A negative n returns to the
+// section's sort.
+func (d *DashboardView) sortByColumn(n int) tea.Cmd {
+	sec := &d.sections[d.section]
+	if n < 0 {
+		sec.columnSort = tableSort{}
+		return tea.Batch(d.setItems(), ui.StatusInfo("Sorted as loaded"))
+	}
+
+	cols, _ := fitColumns(sec.columns(), d.width)
+	if n >= len(cols) {
+		return nil
+	}
+	col := cols[n]
+	if sec.columnSort.key == col.key {
+		sec.columnSort.descending = !sec.columnSort.descending
+	} else {
+		sec.columnSort = tableSort{key: col.key}
+	}
+
+	direction := "ascending"
+	if sec.columnSort.descending {
+		direction = "descending"
+	}
+	return tea.Batch(d.setItems(), ui.StatusInfo(fmt.Sprintf("Sorted by %s, %s", col.key, direction)))
+}
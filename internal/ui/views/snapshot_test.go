@@ -0,0 +1,172 @@
+package views
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/fakegithub"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/uitest"
+)
+
+// seedSnapshotRepo creates a repository whose timestamps are fixed relative
+// to uitest.Now.
+func seedSnapshotRepo() *fakegithub.Server {
+	store := fakegithub.NewStore()
+	repo := store.AddRepo("octo", "hello")
+	repo.AddLabel("bug", "d73a4a")
+	repo.AddLabel("enhancement", "a2eeef")
+
+	issues := []fakegithub.Issue{
+		{Title: "Login fails with SSO enabled", Author: "alice", Labels: []string{"bug"}, Assignees: []string{"bob"}},
+		{Title: "Add dark mode", Author: "carol", Labels: []string{"enhancement"}, Milestone: "v1.0"},
+		{Title: "Crash when saving a very long document with many embedded images", Author: fakegithub.Ghost},
+		{Title: "Typo in README", Author: "dave"},
+	}
+	ages := []time.Duration{5 * time.Minute, 3 * time.Hour, 4 * 24 * time.Hour, 90 * 24 * time.Hour}
+	for n, issue := range issues {
+		issue.CreatedAt = uitest.Now.Add(-ages[n])
+		issue.UpdatedAt = issue.CreatedAt
+		issue.Body = fmt.Sprintf("Steps to reproduce:\n\n1. Open the app\n2. See **%s**", issue.Title)
+		repo.AddIssue(issue)
+	}
+	repo.AddComment(1, fakegithub.Comment{Author: "bob", Body: "I can reproduce this.", CreatedAt: uitest.Now.Add(-time.Minute)})
+	repo.AddComment(1, fakegithub.Comment{Author: "alice", Body: "Fixed in `main`.", CreatedAt: uitest.Now.Add(-30 * time.Second)})
+	return fakegithub.NewServer(store)
+}
+
+// startSnapshotApp runs the App against srv in-process at the given size.
+func startSnapshotApp(t *testing.T, srv *fakegithub.Server, width, height int) *uitest.Driver {
+	t.Helper()
+	uitest.Freeze(t)
+
+	d := uitest.NewDriver(newTestApp(srv, 10))
+	d.Resize(width, height)
+	d.Init()
+	return d
+}
+
+func TestSnapshot_Dashboard(t *testing.T) {
+	for _, width := range []int{40, 80, 120} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			d := startSnapshotApp(t, seedSnapshotRepo(), width, 16)
+			uitest.Golden(t, fmt.Sprintf("dashboard_%d", width), d.View())
+		})
+	}
+}
+
+func TestSnapshot_DashboardSelection(t *testing.T) {
+	d := startSnapshotApp(t, seedSnapshotRepo(), 80, 16)
+	d.Keys("j", "j")
+	uitest.Golden(t, "dashboard_selection", d.View())
+}
+
+func TestSnapshot_DashboardTable(t *testing.T) {
+	for _, width := range []int{60, 140} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			d := startSnapshotApp(t, seedSnapshotRepo(), width, 12)
+			d.Keys("t")
+			uitest.Golden(t, fmt.Sprintf("dashboard_table_%d", width), d.View())
+		})
+	}
+}
+
+func TestSnapshot_DashboardTableSorted(t *testing.T) {
+	d := startSnapshotApp(t, seedSnapshotRepo(), 100, 12)
+	d.Keys("t", "j", "2")
+	uitest.Golden(t, "dashboard_table_sorted", d.View())
+}
+
+func TestSnapshot_DashboardTableGrouped(t *testing.T) {
+	d := startSnapshotApp(t, seedSnapshotRepo(), 100, 14)
+	d.Keys("t", "b", "b", "j", "c")
+	uitest.Golden(t, "dashboard_table_grouped", d.View())
+}
+
+func TestSnapshot_DashboardBoard(t *testing.T) {
+	for _, width := range []int{50, 100} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			srv := seedSnapshotRepo()
+			repo := srv.Store().Repo("octo", "hello")
+			repo.AddLabel("status/todo", "ededed")
+			repo.AddLabel("status/doing", "fbca04")
+			repo.Issue(1).Labels = append(repo.Issue(1).Labels, "status/doing")
+			repo.Issue(2).Labels = append(repo.Issue(2).Labels, "status/todo")
+			repo.Issue(4).Labels = []string{"status/todo"}
+
+			d := startSnapshotApp(t, srv, width, 14)
+			d.Model().(*ui.App).CurrentView().(*DashboardView).SetSections([]Section{
+				{Title: "Status", States: []string{"OPEN"}, Board: &Board{Labels: []string{"status/todo", "status/doing"}}},
+			})
+			d.Keys("R", "l", "j")
+			uitest.Golden(t, fmt.Sprintf("dashboard_board_%d", width), d.View())
+		})
+	}
+}
+
+func TestSnapshot_DashboardError(t *testing.T) {
+	srv := seedSnapshotRepo()
+	srv.Fail("ListIssues", fakegithub.Failure{Status: http.StatusUnauthorized, Message: "Bad credentials"})
+
+	d := startSnapshotApp(t, srv, 80, 10)
+	uitest.Golden(t, "dashboard_error", d.View())
+}
+
+func TestSnapshot_DashboardRateLimit(t *testing.T) {
+	d := startSnapshotApp(t, seedSnapshotRepo(), 100, 10)
+	d.Send(ui.RateLimitMsg{RateLimit: data.RateLimit{Limit: 5000, Remaining: 120, ResetAt: uitest.Now.Add(20 * time.Minute)}})
+	uitest.Golden(t, "dashboard_rate_limit", d.View())
+}
+
+func TestSnapshot_Detail(t *testing.T) {
+	for _, width := range []int{60, 100} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			d := startSnapshotApp(t, seedSnapshotRepo(), width, 30)
+			d.Keys("enter")
+			uitest.Golden(t, fmt.Sprintf("detail_%d", width), d.View())
+		})
+	}
+}
+
+func TestSnapshot_DetailDeletedAuthor(t *testing.T) {
+	d := startSnapshotApp(t, seedSnapshotRepo(), 80, 20)
+	d.Keys("j", "j", "enter")
+	uitest.Golden(t, "detail_deleted_author", d.View())
+}
+
+func TestSnapshot_ReplayedCassette(t *testing.T) {
+	uitest.Freeze(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	session := func(q data.ContextQuerier) string {
+		d := uitest.NewDriver(newTestApp(data.NewRateLimitQuerier(q), 10))
+		d.Resize(80, 24)
+		d.Init()
+		d.Keys("j", "enter")
+		return d.Screen()
+	}
+
+	rec := data.NewCassette(path)
+	recorded := session(rec.Recorder("github.com", seedSnapshotRepo()))
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cassette, err := data.LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	replayed := session(cassette.Player("github.com"))
+
+	if replayed != recorded {
+		t.Errorf("replayed screen differs from recorded screen:\nrecorded:\n%s\nreplayed:\n%s", recorded, replayed)
+	}
+	if !strings.Contains(replayed, "Add dark mode") {
+		t.Errorf("replayed screen does not show the issue:\n%s", replayed)
+	}
+}
@@ -0,0 +1,210 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// issueSort is an order the dashboard can show issues in. Server sorts are
+// requested from GitHub with order; local sorts load pages in order and
+// then rearrange the loaded issues with compare.
+type issueSort struct {
+	key     string
+	label   string
+	order   data.IssueOrder
+	compare func(a, b data.Issue, priorities []string) int
+}
+
+var newestFirst = data.IssueOrder{Field: "CREATED_AT", Direction: "DESC"}
+
+var issueSorts = []issueSort{
+	{key: "created", label: "Newest", order: newestFirst},
+	{key: "created-asc", label: "Oldest", order: data.IssueOrder{Field: "CREATED_AT", Direction: "ASC"}},
+	{key: "updated", label: "Recently updated", order: data.IssueOrder{Field: "UPDATED_AT", Direction: "DESC"}},
+	{key: "updated-asc", label: "Least recently updated", order: data.IssueOrder{Field: "UPDATED_AT", Direction: "ASC"}},
+	{key: "comments", label: "Most commented", order: data.IssueOrder{Field: "COMMENTS", Direction: "DESC"}},
+	{key: "reactions", label: "Most reactions", order: newestFirst, compare: compareReactions},
+	{key: "priority", label: "Priority", order: newestFirst, compare: comparePriority},
+}
+
+// DefaultPriorityLabels rank issues for the priority sort when none are
+// configured.
+var DefaultPriorityLabels = []string{"P0", "P1", "P2", "P3"}
+
+// SortKeys returns the names of the sorts a section can use.
+func SortKeys() []string {
+	keys := make([]string, len(issueSorts))
+	for i, s := range issueSorts {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// findSort returns the sort named key, or the default, newest first.
+func findSort(key string) issueSort {
+	for _, s := range issueSorts {
+		if s.key == key {
+			return s
+		}
+	}
+	return issueSorts[0]
+}
+
+func compareReactions(a, b data.Issue, _ []string) int {
+	return cmp.Compare(b.ReactionCount, a.ReactionCount)
+}
+
+// comparePriority puts issues with the most urgent priority label first,
+// and those with none last.
+func comparePriority(a, b data.Issue, priorities []string) int {
+	return cmp.Compare(priorityRank(a, priorities), priorityRank(b, priorities))
+}
+
+func priorityRank(issue data.Issue, priorities []string) int {
+	rank := len(priorities)
+	for _, l := range issue.Labels {
+		for i, p := range priorities {
+			if i < rank && strings.EqualFold(l.Name, p) {
+				rank = i
+			}
+		}
+	}
+	return rank
+}
+
+// SortMemory remembers the sort last used in each section.
+type SortMemory interface {
+	Sort(section string) string
+	SetSort(section, sort string) error
+}
+
+// SetSortMemory restores each section's last sort from m and saves it
+// there whenever it changes.
+func (d *DashboardView) SetSortMemory(m SortMemory) {
+	d.sortMemory = m
+	for i := range d.sections {
+		if k := m.Sort(d.sections[i].Title); slices.Contains(SortKeys(), k) {
+			d.sections[i].Sort = k
+		}
+	}
+}
+
+// SetPriorityLabels sets the labels the priority sort ranks by, most
+// urgent first.
+func (d *DashboardView) SetPriorityLabels(labels []string) {
+	d.priorityLabels = labels
+}
+
+// sortIssues orders issues by the section's column sort, if any, or else
+// by its local sort. Server sorts are left as loaded.
+func (d *DashboardView) sortIssues(issues []data.Issue) []data.Issue {
+	sec := d.sections[d.section]
+	switch {
+	case sec.columnSort.key != "":
+		col, _ := findTableColumn(sec.columnSort.key)
+		issues = slices.Clone(issues)
+		slices.SortStableFunc(issues, col.compare)
+		if sec.columnSort.descending {
+			slices.Reverse(issues)
+		}
+	case findSort(sec.Sort).compare != nil:
+		compare := findSort(sec.Sort).compare
+		issues = slices.Clone(issues)
+		slices.SortStableFunc(issues, func(a, b data.Issue) int { return compare(a, b, d.priorityLabels) })
+	}
+	return issues
+}
+
+// sortTitle describes a sort other than the default for the list title,
+// noting when the issues were sorted here rather than by GitHub. Column
+// sorts are marked in the table header instead.
+func (d *DashboardView) sortTitle() string {
+	s := findSort(d.sections[d.section].Sort)
+	switch {
+	case s.compare != nil:
+		return fmt.Sprintf(" · %s (sorted locally)", s.label)
+	case s.key != issueSorts[0].key:
+		return " · " + s.label
+	}
+	return ""
+}
+
+// cycleSort switches to the sort after the current one.
+func (d *DashboardView) cycleSort() tea.Cmd {
+	current := findSort(d.sections[d.section].Sort)
+	i := slices.IndexFunc(issueSorts, func(s issueSort) bool { return s.key == current.key })
+	return d.setSort(issueSorts[(i+1)%len(issueSorts)])
+}
+
+// openSortMenu shows the sorts with the current one checked.
+func (d *DashboardView) openSortMenu() {
+	current := findSort(d.sections[d.section].Sort)
+	items := make([]components.PickerItem, len(issueSorts))
+	for i, s := range issueSorts {
+		label := s.label
+		if s.compare != nil {
+			label += " (local)"
+		}
+		items[i] = components.PickerItem{Label: label, Marked: s.key == current.key}
+	}
+	d.sortMenu = components.NewPicker("Sort "+d.sections[d.section].Title, items, d.styles.SelectedRow)
+}
+
+// updateSortMenu handles keys while the sort menu is open.
+func (d *DashboardView) updateSortMenu(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, d.keys.Back), key.Matches(msg, d.keys.Quit), key.Matches(msg, d.keys.SortMenu):
+		d.sortMenu = nil
+	case key.Matches(msg, d.keys.Up):
+		d.sortMenu.Up()
+	case key.Matches(msg, d.keys.Down):
+		d.sortMenu.Down()
+	case key.Matches(msg, d.keys.Open):
+		i := d.sortMenu.Cursor()
+		d.sortMenu = nil
+		return d.setSort(issueSorts[i])
+	default:
+		if i, ok := d.sortMenu.IndexForKey(msg.String()); ok {
+			d.sortMenu = nil
+			return d.setSort(issueSorts[i])
+		}
+	}
+	return nil
+}
+
+// setSort applies s to the current section and remembers it. The issues
+// are loaded again when GitHub has to order them differently; otherwise the
+// loaded ones are rearranged.
+func (d *DashboardView) setSort(s issueSort) tea.Cmd {
+	sec := &d.sections[d.section]
+	previous := findSort(sec.Sort)
+	sec.Sort = s.key
+	sec.columnSort = tableSort{}
+
+	status := "Sorted by " + strings.ToLower(s.label)
+	if s.compare != nil {
+		status += " (locally)"
+	}
+	if d.sortMemory != nil {
+		if err := d.sortMemory.SetSort(sec.Title, s.key); err != nil {
+			status += fmt.Sprintf(", but it could not be saved: %v", err)
+		}
+	}
+	if s.order == previous.order {
+		d.updateTitle()
+		return tea.Batch(d.setItems(), ui.StatusInfo(status))
+	}
+
+	d.loading = true
+	d.errMsg = ""
+	spinCmd := d.spinner.Start(status + "...")
+	return tea.Batch(spinCmd, ui.StatusLoading(status+"..."), d.fetchSection())
+}
@@ -0,0 +1,291 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/utils"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// tableColumn is a column of the dashboard table.
+type tableColumn struct {
+	key    string
+	header string
+	// width is the column's fixed width. The title column has none and
+	// takes the space the others leave, but at least minTitleWidth.
+	width int
+	// priority orders the columns for hiding on narrow terminals: the
+	// highest goes first. Zero is never hidden.
+	priority int
+	right    bool
+	cell     func(issue data.Issue) string
+	compare  func(a, b data.Issue) int
+}
+
+const minTitleWidth = 20
+
+var tableColumns = []tableColumn{
+	{
+		key: "number", header: "#", width: 6,
+		cell:    func(i data.Issue) string { return fmt.Sprintf("#%d", i.Number) },
+		compare: func(a, b data.Issue) int { return cmp.Compare(a.Number, b.Number) },
+	},
+	{
+		key: "title", header: "Title",
+		cell:    func(i data.Issue) string { return i.Title },
+		compare: func(a, b data.Issue) int { return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)) },
+	},
+	{
+		key: "author", header: "Author", width: 12, priority: 2,
+		cell:    func(i data.Issue) string { return i.Author },
+		compare: func(a, b data.Issue) int { return cmp.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author)) },
+	},
+	{
+		key: "labels", header: "Labels", width: 20, priority: 3,
+		cell:    func(i data.Issue) string { return renderLabelChips(i.Labels) },
+		compare: func(a, b data.Issue) int { return cmp.Compare(firstLabel(a), firstLabel(b)) },
+	},
+	{
+		key: "milestone", header: "Milestone", width: 12, priority: 6,
+		cell:    func(i data.Issue) string { return i.Milestone },
+		compare: func(a, b data.Issue) int { return cmp.Compare(a.Milestone, b.Milestone) },
+	},
+	{
+		key: "assignees", header: "Assignees", width: 14, priority: 5,
+		cell:    func(i data.Issue) string { return strings.Join(i.Assignees, ", ") },
+		compare: func(a, b data.Issue) int { return cmp.Compare(firstAssignee(a), firstAssignee(b)) },
+	},
+	{
+		key: "type", header: "Type", width: 10, priority: 8,
+		cell: func(i data.Issue) string { return renderTypeBadge(i.Type, false) },
+		compare: func(a, b data.Issue) int {
+			return cmp.Compare(strings.ToLower(a.Type.Name), strings.ToLower(b.Type.Name))
+		},
+	},
+	{
+		key: "age", header: "Age", width: 8, priority: 1,
+		cell:    func(i data.Issue) string { return strings.TrimSuffix(utils.RelativeTime(i.CreatedAt), " ago") },
+		compare: func(a, b data.Issue) int { return b.CreatedAt.Compare(a.CreatedAt) },
+	},
+	{
+		key: "comments", header: "Comments", width: 8, priority: 4, right: true,
+		cell:    func(i data.Issue) string { return countCell(i.CommentCount) },
+		compare: func(a, b data.Issue) int { return cmp.Compare(a.CommentCount, b.CommentCount) },
+	},
+	{
+		key: "reactions", header: "Reactions", width: 9, priority: 7, right: true,
+		cell:    func(i data.Issue) string { return countCell(i.ReactionCount) },
+		compare: func(a, b data.Issue) int { return cmp.Compare(a.ReactionCount, b.ReactionCount) },
+	},
+	{
+		key: "progress", header: "Done", width: 7, priority: 9, right: true,
+		cell: func(i data.Issue) string {
+			if i.SubIssueProgress.Total == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%d/%d", i.SubIssueProgress.Completed, i.SubIssueProgress.Total)
+		},
+		compare: func(a, b data.Issue) int { return cmp.Compare(doneFraction(a), doneFraction(b)) },
+	},
+}
+
+// DefaultTableColumns are shown by a table section that names no columns.
+var DefaultTableColumns = []string{"number", "title", "author", "labels", "age", "comments"}
+
+// TableColumnKeys returns the names of the columns a section can show.
+func TableColumnKeys() []string {
+	keys := make([]string, len(tableColumns))
+	for i, c := range tableColumns {
+		keys[i] = c.key
+	}
+	return keys
+}
+
+func findTableColumn(key string) (tableColumn, bool) {
+	if field, ok := strings.CutPrefix(key, projectColumnPrefix); ok && field != "" {
+		return projectColumn(field), true
+	}
+	for _, c := range tableColumns {
+		if c.key == key {
+			return c, true
+		}
+	}
+	return tableColumn{}, false
+}
+
+// doneFraction is the share of the issue's sub-issues that are completed,
+// or -1 without sub-issues so those sort first.
+func doneFraction(i data.Issue) float64 {
+	if i.SubIssueProgress.Total == 0 {
+		return -1
+	}
+	return float64(i.SubIssueProgress.Completed) / float64(i.SubIssueProgress.Total)
+}
+
+func countCell(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+func firstLabel(i data.Issue) string {
+	if len(i.Labels) == 0 {
+		return ""
+	}
+	return strings.ToLower(i.Labels[0].Name)
+}
+
+func firstAssignee(i data.Issue) string {
+	if len(i.Assignees) == 0 {
+		return ""
+	}
+	return strings.ToLower(i.Assignees[0])
+}
+
+// renderLabelChips renders labels in their colors.
+func renderLabelChips(labels []data.Label) string {
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		style := lipgloss.NewStyle().Background(utils.HexToColor(l.Color)).Foreground(utils.ContrastColor(l.Color)).Padding(0, 1)
+		parts[i] = style.Render(l.Name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// tableCursorWidth is the width of the selection marker before each row.
+const tableCursorWidth = 2
+
+// fitColumns returns the named columns that fit in width, dropping the
+// lowest-priority ones first, and the width left for the title.
+func fitColumns(keys []string, width int) ([]tableColumn, int) {
+	var cols []tableColumn
+	for _, k := range keys {
+		if c, ok := findTableColumn(k); ok {
+			cols = append(cols, c)
+		}
+	}
+
+	for {
+		used, hasTitle := tableCursorWidth, false
+		for _, c := range cols {
+			used += c.width + 1
+			hasTitle = hasTitle || c.width == 0
+		}
+		titleWidth := width - used
+		if !hasTitle || titleWidth >= minTitleWidth {
+			return cols, max(titleWidth, 0)
+		}
+
+		drop := -1
+		for i, c := range cols {
+			if c.priority > 0 && (drop < 0 || c.priority >= cols[drop].priority) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			return cols, max(titleWidth, 0)
+		}
+		cols = append(cols[:drop], cols[drop+1:]...)
+	}
+}
+
+// fitCell truncates or pads s, which may be styled, to exactly width
+// columns.
+func fitCell(s string, width int, right bool) string {
+	s = ansi.Truncate(s, width, "…")
+	pad := strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+	if right {
+		return pad + s
+	}
+	return s + pad
+}
+
+// tableSort is a client-side ordering of the loaded issues by a column.
+type tableSort struct {
+	key        string // empty for the order the issues were loaded in
+	descending bool
+}
+
+// tableHeader renders the column headings, marking the sorted column.
+func tableHeader(cols []tableColumn, titleWidth int, sort tableSort, style lipgloss.Style) string {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		width := c.width
+		if width == 0 {
+			width = titleWidth
+		}
+		header := c.header
+		if c.key == sort.key {
+			if sort.descending {
+				header += " ▼"
+			} else {
+				header += " ▲"
+			}
+		}
+		parts[i] = fitCell(header, width, c.right)
+	}
+	return strings.Repeat(" ", tableCursorWidth) + style.Render(strings.Join(parts, " "))
+}
+
+// tableDelegate renders issue items as one table row each.
+type tableDelegate struct {
+	styles  ui.Styles
+	columns []string
+}
+
+func (d tableDelegate) Height() int                         { return 1 }
+func (d tableDelegate) Spacing() int                        { return 0 }
+func (d tableDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+func (d tableDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if g, ok := item.(groupItem); ok {
+		renderGroupHeader(w, d.styles, g, index == m.Index(), d.Height())
+		return
+	}
+	i, ok := item.(issueItem)
+	if !ok {
+		return
+	}
+
+	isSelected := index == m.Index()
+	numberStyle := d.styles.IssueNumber
+	titleStyle := d.styles.IssueTitle
+	metaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	cursor := "  "
+	if isSelected {
+		numberStyle = numberStyle.Foreground(lipgloss.Color("12"))
+		titleStyle = titleStyle.Foreground(lipgloss.Color("12"))
+		metaStyle = metaStyle.Foreground(lipgloss.Color("244"))
+		cursor = "> "
+	}
+
+	cols, titleWidth := fitColumns(d.columns, m.Width())
+	parts := make([]string, len(cols))
+	for n, c := range cols {
+		width := c.width
+		if width == 0 {
+			width = titleWidth
+		}
+		cell := fitCell(c.cell(i.issue), width, c.right)
+		switch c.key {
+		case "number":
+			cell = numberStyle.Render(cell)
+		case "title":
+			cell = titleStyle.Render(cell)
+		case "labels":
+		default:
+			cell = metaStyle.Render(cell)
+		}
+		parts[n] = cell
+	}
+	_, _ = fmt.Fprint(w, cursor+strings.Join(parts, " "))
+}
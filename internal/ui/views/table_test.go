@@ -0,0 +1,59 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func columnKeys(cols []tableColumn) string {
+	keys := make([]string, len(cols))
+	for i, c := range cols {
+		keys[i] = c.key
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestFitColumns_HidesLowPriorityColumnsWhenNarrow(t *testing.T) {
+	all := TableColumnKeys()
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{200, "number,title,author,labels,milestone,assignees,type,age,comments,reactions,progress"},
+		{100, "number,title,author,labels,assignees,age,comments"},
+		{60, "number,title,author,age"},
+		{30, "number,title"},
+	}
+	for _, tt := range tests {
+		cols, titleWidth := fitColumns(all, tt.width)
+		if got := columnKeys(cols); got != tt.want {
+			t.Errorf("width %d: columns = %s, want %s", tt.width, got, tt.want)
+		}
+		if tt.width >= 60 && titleWidth < minTitleWidth {
+			t.Errorf("width %d: title width = %d, want at least %d", tt.width, titleWidth, minTitleWidth)
+		}
+	}
+}
+
+func TestFitColumns_KeepsConfiguredOrder(t *testing.T) {
+	cols, _ := fitColumns([]string{"age", "title", "number"}, 80)
+	if got := columnKeys(cols); got != "age,title,number" {
+		t.Errorf("columns = %s", got)
+	}
+}
+
+func TestFitCell(t *testing.T) {
+	if got := fitCell("Crash when saving", 10, false); got != "Crash whe…" {
+		t.Errorf("truncated = %q", got)
+	}
+	if got := fitCell("7", 4, true); got != "   7" {
+		t.Errorf("right aligned = %q", got)
+	}
+	chip := renderLabelChips([]data.Label{{Name: "enhancement", Color: "a2eeef"}})
+	if got := lipgloss.Width(fitCell(chip, 8, false)); got != 8 {
+		t.Errorf("styled cell width = %d, want 8", got)
+	}
+}
@@ -0,0 +1,112 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cboone/gh-problemas/internal/data"
+	"github.com/cboone/gh-problemas/internal/ui"
+	"github.com/cboone/gh-problemas/internal/ui/components"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// issueCopy is a way of referring to an issue, offered for copying.
+type issueCopy struct {
+	what string
+	text string
+}
+
+// markdownEscaper escapes the characters that would end a link's text.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// issueCopies returns the issue's URL, number, number qualified by repo
+// (owner/name), and a markdown link to it. Without a URL only the numbers
+// are offered.
+func issueCopies(issue data.Issue, repo string) []issueCopy {
+	number := fmt.Sprintf("#%d", issue.Number)
+	copies := []issueCopy{
+		{what: "number", text: number},
+		{what: "reference", text: repo + number},
+	}
+	if issue.URL == "" {
+		return copies
+	}
+	link := fmt.Sprintf("[%s (%s)](%s)", markdownEscaper.Replace(issue.Title), number, issue.URL)
+	return append([]issueCopy{{what: "URL", text: issue.URL}}, append(copies, issueCopy{what: "markdown link", text: link})...)
+}
+
+// copyItems lists copies for a picker, showing what each would copy.
+func copyItems(copies []issueCopy) []components.PickerItem {
+	items := make([]components.PickerItem, len(copies))
+	for i, c := range copies {
+		items[i] = components.PickerItem{Label: c.text}
+	}
+	return items
+}
+
+func copyCmd(c issueCopy) tea.Cmd {
+	return func() tea.Msg { return ui.CopyMsg{Text: c.text, What: c.what} }
+}
+
+// browseIssue opens the issue's page on GitHub.
+func browseIssue(issue data.Issue) tea.Cmd {
+	if issue.URL == "" {
+		return ui.StatusInfo(fmt.Sprintf("No URL for #%d", issue.Number))
+	}
+	return func() tea.Msg { return ui.OpenURLMsg{URL: issue.URL} }
+}
+
+// openCopyPicker offers ways of referring to the issue to copy.
+func (d *DetailView) openCopyPicker() {
+	copies := issueCopies(*d.issue, d.issueClient.Repo())
+	d.openPicker(fmt.Sprintf("Copy #%d as", d.issue.Number), copyItems(copies), "1-4/enter: copy", func(i int) tea.Cmd {
+		d.picker = nil
+		return copyCmd(copies[i])
+	})
+}
+
+// selectedIssue returns the issue under the cursor of the list, table, or
+// board.
+func (d *DashboardView) selectedIssue() (data.Issue, bool) {
+	if d.onBoard() {
+		if d.board == nil || d.loading {
+			return data.Issue{}, false
+		}
+		return d.selectedCard(d.boardColumns())
+	}
+	item, ok := d.list.SelectedItem().(issueItem)
+	return item.issue, ok
+}
+
+// openCopyMenu offers ways of referring to the selected issue to copy.
+func (d *DashboardView) openCopyMenu() {
+	issue, ok := d.selectedIssue()
+	if !ok {
+		return
+	}
+	d.copies = issueCopies(issue, d.issueClient.Repo())
+	d.copyMenu = components.NewPicker(fmt.Sprintf("Copy #%d as", issue.Number), copyItems(d.copies), d.styles.SelectedRow)
+}
+
+// updateCopyMenu handles keys while the copy menu is open.
+func (d *DashboardView) updateCopyMenu(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, d.keys.Back), key.Matches(msg, d.keys.Quit), key.Matches(msg, d.keys.CopyRef):
+		d.copyMenu = nil
+	case key.Matches(msg, d.keys.Up):
+		d.copyMenu.Up()
+	case key.Matches(msg, d.keys.Down):
+		d.copyMenu.Down()
+	case key.Matches(msg, d.keys.Open):
+		i := d.copyMenu.Cursor()
+		d.copyMenu = nil
+		return copyCmd(d.copies[i])
+	default:
+		if i, ok := d.copyMenu.IndexForKey(msg.String()); ok {
+			d.copyMenu = nil
+			return copyCmd(d.copies[i])
+		}
+	}
+	return nil
+}
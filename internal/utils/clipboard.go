@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// CopyOSC52 copies text to the clipboard of the terminal that w writes to,
+// using the OSC 52 escape sequence, so that it also works over SSH. Inside
+// tmux or screen the sequence is wrapped to reach the outer terminal.
+func CopyOSC52(w io.Writer, text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(w)
+	return err
+}
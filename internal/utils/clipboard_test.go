@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestCopyOSC52(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("#42"))
+	tests := []struct {
+		name, tmux, term, prefix string
+	}{
+		{"plain", "", "xterm-256color", "\x1b]52;c;"},
+		{"tmux", "/tmp/tmux-1000/default,1,0", "tmux-256color", "\x1bPtmux;\x1b\x1b]52;c;"},
+		{"screen", "", "screen-256color", "\x1bP\x1b]52;c;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMUX", tt.tmux)
+			t.Setenv("TERM", tt.term)
+			var buf bytes.Buffer
+			if err := CopyOSC52(&buf, "#42"); err != nil {
+				t.Fatalf("CopyOSC52: %v", err)
+			}
+			got := buf.String()
+			if !strings.HasPrefix(got, tt.prefix) || !strings.Contains(got, encoded) {
+				t.Errorf("sequence = %q, want prefix %q and payload %q", got, tt.prefix, encoded)
+			}
+		})
+	}
+}
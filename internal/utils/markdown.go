@@ -12,8 +12,26 @@ func RenderMarkdown(content string, width int) (string, error) {
 		width = 1
 	}
 
+	return render(content, width, glamour.WithAutoStyle())
+}
+
+// RenderMarkdownNoColor renders a markdown string without colors or other
+// terminal styling, for piping or NO_COLOR output.
+func RenderMarkdownNoColor(content string, width int) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+
+	if width < 1 {
+		width = 1
+	}
+
+	return render(content, width, glamour.WithStandardStyle("notty"))
+}
+
+func render(content string, width int, style glamour.TermRendererOption) (string, error) {
 	r, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+		style,
 		glamour.WithWordWrap(width),
 	)
 	if err != nil {
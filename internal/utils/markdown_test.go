@@ -51,3 +51,16 @@ func TestRenderMarkdown_WidthWrapping(t *testing.T) {
 		t.Errorf("expected text to wrap into multiple lines at width 40, got %d lines", len(lines))
 	}
 }
+
+func TestRenderMarkdownNoColor_NoEscapes(t *testing.T) {
+	out, err := RenderMarkdownNoColor("# Title\n\n**bold** text", 80)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("expected no ANSI escapes, got: %q", out)
+	}
+	if !strings.Contains(out, "bold") {
+		t.Errorf("expected output to contain 'bold', got: %q", out)
+	}
+}
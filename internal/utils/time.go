@@ -7,13 +7,21 @@ import (
 
 const defaultDateFormat = "relative"
 
+// Now returns the current time. Tests replace it to freeze relative
+// timestamps.
+var Now = time.Now
+
+// Location is the time zone clock times are shown in. Tests replace it to
+// show them in a fixed zone.
+var Location = time.Local
+
 // RelativeTime returns a human-readable relative time string.
 func RelativeTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
 	}
 
-	d := time.Since(t)
+	d := Now().Sub(t)
 	if d < 0 {
 		return "just now"
 	}
@@ -47,5 +55,5 @@ func FormatTime(t time.Time, format string) string {
 		return RelativeTime(t)
 	}
 
-	return t.Local().Format(format)
+	return t.In(Location).Format(format)
 }
@@ -40,6 +40,16 @@ func TestRelativeTime(t *testing.T) {
 	})
 }
 
+func TestRelativeTime_FrozenNow(t *testing.T) {
+	frozen := time.Date(2025, time.January, 8, 12, 0, 0, 0, time.UTC)
+	Now = func() time.Time { return frozen }
+	t.Cleanup(func() { Now = time.Now })
+
+	if got := RelativeTime(frozen.Add(-30 * time.Second)); got != "30s ago" {
+		t.Errorf("RelativeTime(30s ago) = %q, want %q", got, "30s ago")
+	}
+}
+
 func TestRelativeTime_Zero(t *testing.T) {
 	got := RelativeTime(time.Time{})
 	if got != "" {